@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// resumeCheckpoint lê o arquivo do -resume e reduz config.Requests às
+// requisições que faltam. As flags devem ser as mesmas da execução salva; o
+// seq continua depois das requisições feitas e a semente é a do checkpoint.
+func resumeCheckpoint(path string, config *loadtest.Config) (*loadtest.Checkpoint, error) {
+	checkpoint, err := loadtest.ReadCheckpoint(path)
+	if err != nil {
+		return nil, err
+	}
+	if checkpoint.URL != config.URL {
+		return nil, fmt.Errorf("checkpoint %s is for %s, not %s", path, checkpoint.URL, config.URL)
+	}
+	if checkpoint.Requests != config.Requests {
+		return nil, fmt.Errorf("checkpoint %s was taken with -requests %d, not %d", path, checkpoint.Requests, config.Requests)
+	}
+	done := checkpoint.Done()
+	if done >= config.Requests {
+		return nil, fmt.Errorf("checkpoint %s already has all %d requests", path, config.Requests)
+	}
+	if config.Seed != 0 && checkpoint.Seed != 0 && config.Seed != checkpoint.Seed {
+		return nil, fmt.Errorf("checkpoint %s was taken with -seed %d, not %d", path, checkpoint.Seed, config.Seed)
+	}
+	config.Requests -= done
+	config.SeqStart = checkpoint.SeqStart + int64(done)
+	if checkpoint.Seed != 0 {
+		config.Seed = checkpoint.Seed
+	}
+	slog.Info("resuming the test", "checkpoint", path, "done", done, "remaining", config.Requests, "saved", checkpoint.Saved.Format(time.RFC3339))
+	return checkpoint, nil
+}
+
+// newCheckpointer prepara o -checkpoint; num teste retomado, os trechos já
+// executados continuam no arquivo.
+func newCheckpointer(path string, interval time.Duration, config loadtest.Config, resumed *loadtest.Checkpoint) *loadtest.Checkpointer {
+	base := loadtest.Checkpoint{URL: config.URL, Requests: config.Requests, SeqStart: config.SeqStart, Seed: config.Seed}
+	if resumed != nil {
+		base = *resumed
+	}
+	return &loadtest.Checkpointer{Path: path, Interval: interval, Base: base}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+func writeCheckpoint(t *testing.T, checkpoint loadtest.Checkpoint) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "checkpoint.json")
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// O trecho retomado continua o seq depois das requisições já feitas e usa
+// a semente gravada, para não repetir URLs, targets e valores sorteados.
+func TestResumeContinuesSequence(t *testing.T) {
+	path := writeCheckpoint(t, loadtest.Checkpoint{
+		URL:      "http://example.com/item/{{seq}}",
+		Requests: 20,
+		SeqStart: 100,
+		Seed:     42,
+		Segments: []loadtest.Report{{TotalRequests: 6}},
+	})
+
+	for _, tc := range []struct {
+		name    string
+		seed    uint64
+		wantErr bool
+	}{
+		{"seed from checkpoint", 0, false},
+		{"same seed", 42, false},
+		{"other seed", 7, true},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			config := loadtest.Config{URL: "http://example.com/item/{{seq}}", Requests: 20, Seed: tc.seed}
+			_, err := resumeCheckpoint(path, &config)
+			if tc.wantErr {
+				if err == nil {
+					t.Fatal("resume with another -seed was accepted")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if config.SeqStart != 106 {
+				t.Errorf("SeqStart = %d, want 106", config.SeqStart)
+			}
+			if config.Seed != 42 {
+				t.Errorf("Seed = %d, want 42", config.Seed)
+			}
+			if config.Requests != 14 {
+				t.Errorf("Requests = %d, want 14", config.Requests)
+			}
+		})
+	}
+}
+
+// O checkpointer de um teste retomado preserva o início do seq e a semente
+// do primeiro trecho.
+func TestCheckpointerKeepsSequenceOrigin(t *testing.T) {
+	config := loadtest.Config{URL: "http://example.com", Requests: 10, SeqStart: 5, Seed: 9}
+	if base := newCheckpointer("x", 0, config, nil).Base; base.SeqStart != 5 || base.Seed != 9 {
+		t.Errorf("new checkpoint SeqStart=%d Seed=%d, want 5 and 9", base.SeqStart, base.Seed)
+	}
+	resumed := &loadtest.Checkpoint{URL: config.URL, Requests: 10, SeqStart: 5, Seed: 9}
+	config.SeqStart = 11
+	if base := newCheckpointer("x", 0, config, resumed).Base; base.SeqStart != 5 {
+		t.Errorf("resumed checkpoint SeqStart = %d, want 5", base.SeqStart)
+	}
+}
@@ -0,0 +1,166 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// runArgs são os argumentos do `run` como foram digitados, gravados no
+// -history e repassados aos -workers.
+var runArgs []string
+
+// execute monta os comandos e executa o escolhido em args.
+func execute(args []string) int {
+	root := newRootCommand()
+	runArgs = args
+	if len(args) > 0 && args[0] == "run" {
+		runArgs = args[1:]
+	}
+	root.SetArgs(legacyArgs(root, args))
+	if err := root.Execute(); err != nil {
+		return exitConfig
+	}
+	return exitOK
+}
+
+func newRootCommand() *cobra.Command {
+	root := &cobra.Command{
+		Use:   filepath.Base(os.Args[0]),
+		Short: "Load tester for HTTP, gRPC, TCP, UDP, DNS, MQTT and Redis",
+		Long: `Load tester for HTTP, gRPC, TCP, UDP, DNS, MQTT and Redis.
+
+Without a command the arguments are the ones of run, as in the first versions:
+  ` + filepath.Base(os.Args[0]) + ` -url https://api.example.com -requests 1000 -concurrency 50`,
+		SilenceUsage: true,
+	}
+	root.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		return fmt.Errorf("%w\nSee '%s --help'", err, cmd.CommandPath())
+	})
+	root.AddGroup(
+		&cobra.Group{ID: "test", Title: "Load tests:"},
+		&cobra.Group{ID: "reports", Title: "Reports and history:"},
+	)
+	root.AddCommand(
+		runCommand(), suiteCommand(), initCommand(), serveCommand(), recordCommand(),
+		reportCommand(), compareCommand(), mergeCommand(), historyCommand(), trendCommand(),
+	)
+
+	// As flags do `run` também valem sem o subcomando, mas ficam fora da
+	// ajuda geral para não esconder a lista de comandos
+	setRunFlags(root)
+	root.Flags().VisitAll(func(f *pflag.Flag) { f.Hidden = true })
+
+	registerCompletions(root)
+	return root
+}
+
+// runLong é a ajuda do `run`, com a política de códigos de saída.
+const runLong = `Run a load test.
+
+Exit codes:
+  0  the test ran and passed the thresholds, if any
+  1  a threshold or -baseline tolerance was violated, or every request failed
+     although the server responded (e.g. all statuses outside -expect-status)
+  2  invalid flags or configuration, or the test could not start
+  3  aborted with Ctrl+C or SIGTERM (the partial report is still written)
+  4  no request got a response, e.g. connection refused, DNS or timeout`
+
+func runCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "run [flags]",
+		Short:   "Run a load test (default when the first argument is a flag)",
+		Long:    runLong,
+		GroupID: "test",
+		Args:    cobra.NoArgs,
+	}
+	setRunFlags(cmd)
+	return cmd
+}
+
+// setRunFlags registra em cmd as flags do teste, definidas com o pacote flag
+// em loadtest.Flags e runFlags, e faz dele um `run`.
+func setRunFlags(cmd *cobra.Command) {
+	fs := flag.NewFlagSet(cmd.Name(), flag.ContinueOnError)
+	build := runFlags(fs, true)
+	cmd.Flags().AddGoFlagSet(fs)
+	cmd.Run = func(cmd *cobra.Command, _ []string) {
+		os.Exit(runTest(build, runArgs, cmd.Flags().Changed))
+	}
+}
+
+// legacyArgs converte as flags longas com um hífen (-url), aceitas desde as
+// primeiras versões, para a forma do pflag (--url). Só nomes de flags
+// conhecidas são convertidos, então valores como -1 ficam intactos.
+func legacyArgs(root *cobra.Command, args []string) []string {
+	names := map[string]bool{"help": true}
+	var collect func(*cobra.Command)
+	collect = func(cmd *cobra.Command) {
+		cmd.Flags().VisitAll(func(f *pflag.Flag) {
+			if len(f.Name) > 1 {
+				names[f.Name] = true
+			}
+		})
+		for _, sub := range cmd.Commands() {
+			collect(sub)
+		}
+	}
+	collect(root)
+
+	converted := make([]string, len(args))
+	copy(converted, args)
+	for i, arg := range args {
+		if arg == "--" {
+			break
+		}
+		if !strings.HasPrefix(arg, "-") || strings.HasPrefix(arg, "--") {
+			continue
+		}
+		if name, _, _ := strings.Cut(arg[1:], "="); names[name] {
+			converted[i] = "-" + arg
+		}
+	}
+	return converted
+}
+
+// flagValues são os valores das flags de escolha fechada, oferecidos pelo
+// completion do shell. As demais flags completam nomes de arquivo.
+var flagValues = map[string][]string{
+	"format":      {"plain", "json", "csv"},
+	"progress":    {"line", "json"},
+	"http":        {"auto", "1.1", "2", "h2c"},
+	"compression": {"gzip", "br", "none"},
+	"range-mode":  {"fixed", "random", "sweep"},
+	"method":      {"GET", "POST", "PUT", "PATCH", "DELETE", "HEAD", "OPTIONS"},
+	"lang":        report.Langs,
+	"log-level":   {"debug", "info", "warn", "error"},
+	"log-format":  {"text", "json"},
+	"profile":     profileNames,
+}
+
+func registerCompletions(cmd *cobra.Command) {
+	for name, values := range flagValues {
+		if cmd.Flags().Lookup(name) != nil {
+			cmd.RegisterFlagCompletionFunc(name, cobra.FixedCompletions(values, cobra.ShellCompDirectiveNoFileComp))
+		}
+	}
+	for _, sub := range cmd.Commands() {
+		registerCompletions(sub)
+	}
+}
+
+// addLangFlag registra o -lang em um subcomando e o aplica antes de
+// executá-lo.
+func addLangFlag(cmd *cobra.Command) {
+	lang := cmd.Flags().String("lang", "en", langUsage)
+	cmd.PreRunE = func(*cobra.Command, []string) error {
+		return setLang(*lang)
+	}
+}
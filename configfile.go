@@ -0,0 +1,58 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+)
+
+// applyConfigFile aplica os valores de um arquivo do -config às flags que
+// não foram passadas na linha de comando (explicit) e devolve os nomes das
+// flags aplicadas. As chaves são os nomes das flags sem o hífen; listas
+// servem às flags repetíveis, como -resolve.
+func applyConfigFile(fs *flag.FlagSet, path string, explicit func(string) bool) (map[string]bool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]any
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(&values); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	return applyConfigValues(fs, path, values, explicit)
+}
+
+// applyConfigValues aplica values, indexados pelo nome das flags, como
+// applyConfigFile. source identifica a origem nas mensagens de erro.
+func applyConfigValues(fs *flag.FlagSet, source string, values map[string]any, explicit func(string) bool) (map[string]bool, error) {
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	applied := make(map[string]bool)
+	for _, name := range names {
+		if name == "config" || fs.Lookup(name) == nil {
+			return nil, fmt.Errorf("%s: unknown flag %q", source, name)
+		}
+		if explicit(name) {
+			continue
+		}
+		items, ok := values[name].([]any)
+		if !ok {
+			items = []any{values[name]}
+		}
+		for _, item := range items {
+			if err := fs.Set(name, fmt.Sprint(item)); err != nil {
+				return nil, fmt.Errorf("%s: -%s: %w", source, name, err)
+			}
+		}
+		applied[name] = true
+	}
+	return applied, nil
+}
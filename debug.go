@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// runDebugRequest envia uma única requisição e imprime tudo o que foi
+// enviado e recebido, no estilo do curl -v. Devolve false quando ela falhou.
+func runDebugRequest(config loadtest.Config) bool {
+	exchange, err := loadtest.DebugRequest(context.Background(), config)
+	if err != nil {
+		fmt.Printf(report.Msg("❌ Debug request failed: %v\n"), err)
+		return false
+	}
+
+	req := exchange.Request
+	fmt.Printf(report.Msg("🐞 Debug request (%s)\n"), exchange.RemoteAddr)
+	fmt.Printf("----------------------------------------\n")
+	fmt.Printf("> %s %s\n", req.Method, req.URL)
+	if req.Host != "" {
+		fmt.Printf("> Host: %s\n", req.Host)
+	}
+	printHeaders("> ", req.Headers)
+	if len(req.Body) > 0 {
+		fmt.Println(">")
+		printBody("> ", req.Body, req.BodySize)
+	}
+	fmt.Println()
+
+	fmt.Printf("< %s %s\n", exchange.Proto, exchange.Status)
+	printHeaders("< ", exchange.Headers)
+	if len(exchange.Body) > 0 {
+		fmt.Println("<")
+		printBody("< ", exchange.Body, exchange.BodySize)
+	}
+	if len(exchange.Trailers) > 0 {
+		fmt.Println("<")
+		printHeaders("< ", exchange.Trailers)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+
+	fmt.Printf(report.Msg("⏱️ Timing: %v total\n"), exchange.Duration)
+	fmt.Printf("----------------------------------------\n")
+	p := exchange.Phases
+	for _, phase := range []struct {
+		name     string
+		duration time.Duration
+	}{
+		{"DNS Lookup", p.DNSLookup},
+		{"TCP Connect", p.TCPConnect},
+		{"TLS Handshake", p.TLSHandshake},
+		{"Request Upload", p.RequestUpload},
+		{"Time to First Byte", p.TimeToFirstByte},
+		{"Content Transfer", p.ContentTransfer},
+	} {
+		fmt.Printf("%-20s %v\n", report.Msg(phase.name)+":", phase.duration)
+	}
+	if exchange.TLSVersion != "" {
+		fmt.Printf(report.Msg("Negotiated %s\n"), exchange.TLSVersion)
+	}
+	if exchange.Redirects > 0 {
+		fmt.Printf(report.Msg("Redirects Followed: %d\n"), exchange.Redirects)
+	}
+	fmt.Printf(report.Msg("Bytes Received: %d (%d decoded)\n"), exchange.WireBytes, exchange.BodySize)
+	fmt.Printf("----------------------------------------\n")
+	return true
+}
@@ -0,0 +1,281 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// distributedWorker é uma instância do modo serve que recebe parte do teste.
+type distributedWorker struct {
+	URL      string
+	ID       string
+	Status   string
+	Progress loadtest.LiveSnapshot
+	Report   loadtest.Report
+}
+
+// splitShare divide total em n partes o mais iguais possível.
+func splitShare(total, n int) []int {
+	shares := make([]int, n)
+	for i := range shares {
+		shares[i] = total / n
+		if i < total%n {
+			shares[i]++
+		}
+	}
+	return shares
+}
+
+// coordinatorFlags só valem no processo que distribui o teste: o veredito
+// contra o -baseline e o -history são feitos com o relatório combinado.
+var coordinatorFlags = []string{"workers", "baseline", "history"}
+
+// workerArgs remove -workers e as coordinatorFlags dos argumentos; os
+// valores divididos são acrescentados no fim, onde têm precedência sobre os
+// originais.
+func workerArgs(args []string) []string {
+	var out []string
+	for i := 0; i < len(args); i++ {
+		name, _, inline := strings.Cut(strings.TrimLeft(args[i], "-"), "=")
+		switch {
+		case !slices.Contains(coordinatorFlags, name):
+			out = append(out, args[i])
+		case !inline:
+			i++
+		}
+	}
+	return out
+}
+
+// workerFileFlags recebem caminhos de arquivos. Repassadas aos workers, elas
+// seriam lidas (ou gravadas, no caso do -request-log) no disco de cada um.
+var workerFileFlags = []string{
+	"config", "body-template", "body-file", "targets", "postman", "postman-env", "replay", "har", "save-targets",
+	"proto", "import-path", "header-file", "kerberos-keytab", "krb5-conf", "cert", "key", "cacert", "error-rules",
+	"tcp-payload-file", "request-log",
+}
+
+// workerSecretFlags aceitam '@file' no lugar do valor.
+var workerSecretFlags = []string{"oauth2-client-secret", "kerberos-password", "bearer-token", "jwt-claims"}
+
+// localFileFlag devolve a primeira flag de fs que faz o teste ler ou gravar
+// um arquivo local, ou "" se não há nenhuma. Com -workers elas são
+// rejeitadas em vez de repassadas com um caminho que só existe aqui.
+func localFileFlag(fs *flag.FlagSet) string {
+	value := func(name string) string {
+		f := fs.Lookup(name)
+		if f == nil || f.Value.String() == f.DefValue {
+			return ""
+		}
+		return f.Value.String()
+	}
+	for _, name := range workerFileFlags {
+		if value(name) != "" {
+			return name
+		}
+	}
+	for _, name := range workerSecretFlags {
+		if strings.HasPrefix(value(name), "@") {
+			return name
+		}
+	}
+	switch {
+	case strings.Contains(value("ntlm"), ":@"):
+		return "ntlm"
+	case strings.Contains(value("form"), "=@"):
+		return "form"
+	}
+	// Fora do HMAC a -jwt-key é o arquivo PEM da chave privada
+	alg := fs.Lookup("jwt-alg").Value.String()
+	if key := value("jwt-key"); strings.HasPrefix(key, "@") || key != "" && !strings.HasPrefix(alg, "HS") {
+		return "jwt-key"
+	}
+	return ""
+}
+
+// runDistributed divide as requisições, a concorrência e as taxas entre os
+// workers, acompanha o progresso e combina os relatórios no fim. Cancelar
+// ctx cancela o teste nos workers, que devolvem os relatórios parciais.
+func runDistributed(ctx context.Context, args []string, config loadtest.Config, urls []string) (*loadtest.Report, error) {
+	if len(urls) > config.Requests {
+		urls = urls[:config.Requests]
+	}
+	requests := splitShare(config.Requests, len(urls))
+	concurrency := splitShare(max(config.Concurrency, len(urls)), len(urls))
+	base := workerArgs(args)
+
+	workers := make([]*distributedWorker, len(urls))
+	// Cada worker numera as suas requisições depois das dos anteriores, para
+	// o seq não se repetir entre eles
+	seqStart := config.SeqStart
+	for i, u := range urls {
+		share := append([]string(nil), base...)
+		share = append(share, "-requests", strconv.Itoa(requests[i]), "-concurrency", strconv.Itoa(concurrency[i]),
+			"-seq-start", strconv.FormatInt(seqStart, 10))
+		seqStart += int64(requests[i])
+		if config.UDP != nil && config.UDP.Rate > 0 {
+			share = append(share, "-udp-rate", strconv.Itoa(max(1, splitShare(config.UDP.Rate, len(urls))[i])))
+		}
+		if config.MQTT != nil && config.MQTT.Rate > 0 {
+			share = append(share, "-mqtt-rate", strconv.Itoa(max(1, splitShare(config.MQTT.Rate, len(urls))[i])))
+		}
+
+		worker := &distributedWorker{URL: strings.TrimRight(u, "/")}
+		err := ctx.Err()
+		if err == nil {
+			err = worker.submit(share)
+		}
+		if err != nil {
+			// Cancelar o que já foi submetido
+			for _, w := range workers[:i] {
+				w.cancel()
+			}
+			return nil, err
+		}
+		workers[i] = worker
+	}
+	slog.Info("test split across workers", "workers", len(workers))
+
+	start := time.Now()
+	tty := loadtest.StdoutIsTerminal()
+	abort := ctx.Done()
+	aborted := false
+	for tick := 1; ; tick++ {
+		select {
+		case <-abort:
+			// Os workers param como pelo DELETE da API e o acompanhamento
+			// segue até eles devolverem os relatórios parciais
+			slog.Warn("cancelling the test on the workers")
+			for _, w := range workers {
+				w.cancel()
+			}
+			abort, aborted = nil, true
+		case <-time.After(time.Second):
+		}
+		done, running := 0, 0
+		var wg sync.WaitGroup
+		for _, w := range workers {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				w.poll()
+			}()
+		}
+		wg.Wait()
+		update := loadtest.ProgressUpdate{Elapsed: time.Since(start).Seconds(), Planned: config.Requests}
+		for _, w := range workers {
+			done += w.Progress.Total
+			update.Errors += w.Progress.Errors
+			update.Rate += w.Progress.RPS
+			// Os percentis dos workers não podem ser somados; o maior p95
+			// é um limite superior do p95 combinado
+			update.P95 = max(update.P95, w.Progress.P95)
+			if w.Status == testRunning || w.Status == "" {
+				running++
+			}
+		}
+		update.Completed = done
+		update.Done = running == 0
+		slog.Debug("polled workers", "running", running, "workers", len(workers))
+		switch {
+		case config.Quiet:
+		case config.Progress == "json":
+			loadtest.WriteProgressLine(update)
+		case tty || update.Done || tick%5 == 0:
+			// Sem terminal, uma linha a cada 5 segundos
+			loadtest.PrintProgress(update, tty)
+		}
+		if running == 0 {
+			break
+		}
+	}
+
+	reports := make([]loadtest.Report, 0, len(workers))
+	for _, w := range workers {
+		if w.Status == testFailed {
+			return nil, fmt.Errorf("worker %s failed", w.URL)
+		}
+		if err := w.fetchReport(); err != nil {
+			return nil, err
+		}
+		reports = append(reports, w.Report)
+	}
+	merged := report.Merge(reports)
+	// A execução é identificada pela máquina que coordenou os workers
+	merged.Metadata = loadtest.NewMetadata(config.Labels)
+	merged.Aborted = aborted
+	return &merged, nil
+}
+
+func (w *distributedWorker) submit(args []string) error {
+	body, _ := json.Marshal(map[string][]string{"args": args})
+	resp, err := http.Post(w.URL+"/tests", "application/json", bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("submitting to worker %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+	var created struct {
+		ID    string `json:"id"`
+		Error string `json:"error"`
+	}
+	json.NewDecoder(resp.Body).Decode(&created)
+	if resp.StatusCode != http.StatusCreated {
+		return fmt.Errorf("worker %s rejected the test: %s", w.URL, created.Error)
+	}
+	w.ID = created.ID
+	slog.Debug("test submitted to worker", "worker", w.URL, "id", w.ID)
+	return nil
+}
+
+// poll atualiza status e progresso; falhas temporárias mantêm o último valor.
+func (w *distributedWorker) poll() {
+	resp, err := http.Get(w.URL + "/tests/" + w.ID)
+	if err != nil {
+		slog.Warn("polling worker", "worker", w.URL, "error", err)
+		return
+	}
+	defer resp.Body.Close()
+	var status struct {
+		Status   string                `json:"status"`
+		Progress loadtest.LiveSnapshot `json:"progress"`
+	}
+	if json.NewDecoder(resp.Body).Decode(&status) == nil && resp.StatusCode == http.StatusOK {
+		w.Status = status.Status
+		w.Progress = status.Progress
+	}
+}
+
+func (w *distributedWorker) fetchReport() error {
+	resp, err := http.Get(w.URL + "/tests/" + w.ID + "/report")
+	if err != nil {
+		return fmt.Errorf("fetching report from worker %s: %w", w.URL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("fetching report from worker %s: %s", w.URL, resp.Status)
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&w.Report); err != nil {
+		return fmt.Errorf("decoding report from worker %s: %w", w.URL, err)
+	}
+	return nil
+}
+
+func (w *distributedWorker) cancel() {
+	req, _ := http.NewRequest(http.MethodDelete, w.URL+"/tests/"+w.ID, nil)
+	if resp, err := http.DefaultClient.Do(req); err == nil {
+		resp.Body.Close()
+	}
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"io"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestWorkerArgs(t *testing.T) {
+	args := []string{"-url", "http://api", "-workers", "http://w1,http://w2", "-baseline=base.json",
+		"--history", "runs.db", "-requests", "100"}
+	want := []string{"-url", "http://api", "-requests", "100"}
+	if got := workerArgs(args); !slices.Equal(got, want) {
+		t.Errorf("workerArgs() = %q, want %q", got, want)
+	}
+}
+
+func TestWorkersRejectLocalFiles(t *testing.T) {
+	tests := []struct {
+		args []string
+		flag string // "" quando a combinação é aceita
+	}{
+		{[]string{"-headers", "X-Env:staging"}, ""},
+		{[]string{"-bearer-token", "abc123"}, ""},
+		{[]string{"-jwt-key", "secret"}, ""},
+		{[]string{"-body-file", "payload.bin"}, "body-file"},
+		{[]string{"-cert", "client.pem", "-key", "client.key"}, "cert"},
+		{[]string{"-cacert", "ca.pem"}, "cacert"},
+		{[]string{"-request-log", "requests.ndjson"}, "request-log"},
+		{[]string{"-header-file", "X-User:users.txt"}, "header-file"},
+		{[]string{"-bearer-token", "@token.txt"}, "bearer-token"},
+		{[]string{"-form", "file=@photo.jpg"}, "form"},
+		{[]string{"-ntlm", `CORP\user:@password.txt`}, "ntlm"},
+		{[]string{"-jwt-key", "key.pem", "-jwt-alg", "RS256"}, "jwt-key"},
+	}
+	for _, tt := range tests {
+		t.Run(strings.Join(tt.args, " "), func(t *testing.T) {
+			args := append([]string{"-url", "http://localhost", "-requests", "10", "-workers", "http://w1"}, tt.args...)
+			_, _, err := parseConfig(args, io.Discard)
+			switch {
+			case tt.flag == "" && err != nil:
+				t.Errorf("unexpected error: %v", err)
+			case tt.flag != "" && (err == nil || !strings.Contains(err.Error(), "-"+tt.flag+" is not supported with -workers")):
+				t.Errorf("error = %v, want -%s rejected", err, tt.flag)
+			}
+		})
+	}
+}
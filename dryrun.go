@@ -0,0 +1,96 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// runDryRun valida a configuração, resolve o destino e imprime a primeira
+// requisição sem enviar carga. Devolve false quando algo falhou.
+func runDryRun(config loadtest.Config) bool {
+	preview, err := loadtest.DryRun(context.Background(), config)
+	if err != nil {
+		fmt.Printf(report.Msg("❌ Dry run failed: %v\n"), err)
+		return false
+	}
+
+	mode := strings.ToUpper(preview.Mode)
+	if mode == "" {
+		mode = "HTTP"
+	}
+	fmt.Print(report.Msg("🔎 Dry run: configuration is valid, no load was sent\n"))
+	fmt.Printf("----------------------------------------\n")
+	fmt.Printf(report.Msg("Mode: %s\n"), mode)
+	fmt.Printf(report.Msg("Requests: %d | Concurrency: %d\n"), config.Requests, config.Concurrency)
+	if len(preview.Addrs) > 0 {
+		fmt.Printf(report.Msg("Target: %s -> %s\n"), preview.Target, strings.Join(preview.Addrs, ", "))
+	} else {
+		fmt.Printf(report.Msg("Target: %s\n"), preview.Target)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+
+	fmt.Print(report.Msg("📨 First request\n"))
+	fmt.Printf("----------------------------------------\n")
+	// Nos outros modos a URL já aparece em Target
+	line := preview.Method
+	if preview.Mode == "" || preview.Mode == "dns" {
+		line += " " + preview.URL
+	}
+	if line != "" {
+		fmt.Println(line)
+	}
+	if preview.Host != "" {
+		fmt.Printf("Host: %s\n", preview.Host)
+	}
+	printHeaders("", preview.Headers)
+	if len(preview.Body) > 0 {
+		if line != "" || len(preview.Headers) > 0 {
+			fmt.Println()
+		}
+		printBody("", preview.Body, preview.BodySize)
+	}
+	fmt.Printf("----------------------------------------\n")
+	return true
+}
+
+// printHeaders imprime os headers em ordem alfabética, um por linha.
+func printHeaders(prefix string, headers http.Header) {
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		for _, value := range headers[name] {
+			fmt.Printf("%s%s: %s\n", prefix, name, value)
+		}
+	}
+}
+
+// printBody mostra o corpo como texto ou, se for binário, só o tamanho.
+func printBody(prefix string, body []byte, size int64) {
+	if !utf8.Valid(body) {
+		fmt.Printf(report.Msg("%s(binary body, %s)\n"), prefix, sizeLabel(size))
+		return
+	}
+	for _, line := range strings.Split(strings.TrimRight(string(body), "\n"), "\n") {
+		fmt.Println(prefix + line)
+	}
+	if size > int64(len(body)) {
+		fmt.Printf(report.Msg("%s... (%s in total)\n"), prefix, sizeLabel(size))
+	}
+}
+
+func sizeLabel(size int64) string {
+	if size < 0 {
+		return report.Msg("unknown size")
+	}
+	return loadtest.FormatByteSize(size)
+}
@@ -0,0 +1,191 @@
+// Package export converte relatórios do loadtest para formatos de arquivo.
+package export
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+type ReportExporter interface {
+	Export(loadtest.Report) string
+}
+
+type JSONExporter struct{}
+
+type CSVExporter struct{}
+
+func (j JSONExporter) Export(r loadtest.Report) string {
+	data, _ := json.MarshalIndent(r, "", " ")
+	return string(data)
+}
+
+func (c CSVExporter) Export(r loadtest.Report) string {
+	var sb strings.Builder
+	// Cabeçalho
+	sb.WriteString("Total Time (s),Total Requests,RPS,Min Duration (ms),Max Duration (ms),Avg Duration (ms),Errors,Send Window (s)")
+	// A coluna do tamanho só aparece nos testes com corpo sintético
+	if r.BodySize > 0 {
+		sb.WriteString(",Body Size (bytes)")
+	}
+	sb.WriteString("\n")
+	// Dados principais
+	// Sem latências medidas as colunas ficam vazias (null), não zeradas
+	latency := func(d time.Duration) string {
+		if !r.HasLatency() {
+			return ""
+		}
+		return fmt.Sprintf("%.2f", float64(d.Milliseconds()))
+	}
+	sb.WriteString(fmt.Sprintf("%.2f,%d,%.2f,%s,%s,%s,%d,%.2f",
+		r.TotalTime.Seconds(),
+		r.TotalRequests,
+		r.RPS,
+		latency(r.MinDuration),
+		latency(r.MaxDuration),
+		latency(r.AvgDuration),
+		r.Errors,
+		r.SendWindow.Seconds()))
+	if r.BodySize > 0 {
+		sb.WriteString(fmt.Sprintf(",%d", r.BodySize))
+	}
+	sb.WriteString("\n")
+	// Rótulos e dados do ambiente
+	sb.WriteString("\nRun Metadata\n")
+	sb.WriteString("Key,Value\n")
+	for _, item := range r.Metadata.List() {
+		sb.WriteString(fmt.Sprintf("%s,%s\n", csvField(item[0]), csvField(item[1])))
+	}
+	if r.Seed != 0 {
+		sb.WriteString(fmt.Sprintf("seed,%d\n", r.Seed))
+	}
+	switch {
+	case r.Aborted:
+		sb.WriteString("status,aborted\n")
+	case r.MaxDurationReached:
+		sb.WriteString("status,max_duration_reached\n")
+	}
+	// Status Codes
+	sb.WriteString("\nStatus Code Distribution\n")
+	sb.WriteString("Code,Count,Percentage\n")
+	for code, count := range r.StatusCodes {
+		percentage := float64(count) / float64(r.TotalRequests) * 100
+		sb.WriteString(fmt.Sprintf("%d,%d,%.2f\n", code, count, percentage))
+	}
+	// Falhas sem resposta do servidor
+	if len(r.ErrorKinds) > 0 {
+		sb.WriteString("\nTransport Errors\n")
+		sb.WriteString("Kind,Count,Percentage\n")
+		for kind, count := range r.ErrorKinds {
+			percentage := float64(count) / float64(r.TotalRequests) * 100
+			sb.WriteString(fmt.Sprintf("%s,%d,%.2f\n", kind, count, percentage))
+		}
+	}
+	// Categorias do -error-rules
+	if len(r.ErrorCategories) > 0 {
+		sb.WriteString("\nError Categories\n")
+		sb.WriteString("Category,Severity,Count,Percentage\n")
+		for name, category := range r.ErrorCategories {
+			percentage := float64(category.Count) / float64(r.TotalRequests) * 100
+			sb.WriteString(fmt.Sprintf("%s,%s,%d,%.2f\n", csvField(name), category.Severity, category.Count, percentage))
+		}
+	}
+	// Protocolos negociados
+	sb.WriteString("\nProtocol Distribution\n")
+	sb.WriteString("Protocol,Count\n")
+	for proto, count := range r.Protocols {
+		sb.WriteString(fmt.Sprintf("%s,%d\n", proto, count))
+	}
+	// Versões TLS negociadas
+	sb.WriteString("\nTLS Version Distribution\n")
+	sb.WriteString("Version,Count\n")
+	for version, count := range r.TLSVersions {
+		sb.WriteString(fmt.Sprintf("%s,%d\n", version, count))
+	}
+	// Família de endereços usada
+	sb.WriteString("\nAddress Family Distribution\n")
+	sb.WriteString("Family,Count\n")
+	for family, count := range r.IPFamilies {
+		sb.WriteString(fmt.Sprintf("%s,%d\n", family, count))
+	}
+	// Latência por IP de destino
+	sb.WriteString("\nPer-IP Breakdown\n")
+	sb.WriteString("IP,Requests,Errors,Min (ms),Max (ms),Avg (ms)\n")
+	for ip, stats := range r.PerIP {
+		sb.WriteString(fmt.Sprintf("%s,%d,%d,%.2f,%.2f,%.2f\n",
+			ip,
+			stats.Requests,
+			stats.Errors,
+			float64(stats.Latency.Min.Microseconds())/1000,
+			float64(stats.Latency.Max.Microseconds())/1000,
+			float64(stats.Latency.Avg.Microseconds())/1000))
+	}
+	// Fases da requisição
+	sb.WriteString("\nLatency Breakdown\n")
+	sb.WriteString("Phase,Count,Min (ms),Max (ms),Avg (ms)\n")
+	for _, phase := range r.Phases.List() {
+		sb.WriteString(fmt.Sprintf("%s,%d,%.2f,%.2f,%.2f\n",
+			phase.Name,
+			phase.Stats.Count,
+			float64(phase.Stats.Min.Microseconds())/1000,
+			float64(phase.Stats.Max.Microseconds())/1000,
+			float64(phase.Stats.Avg.Microseconds())/1000))
+	}
+	// Ajustes de concorrência feitos durante o teste
+	if len(r.ConcurrencyChanges) > 0 {
+		sb.WriteString("\nConcurrency Changes\n")
+		sb.WriteString("At (s),From,To\n")
+		for _, change := range r.ConcurrencyChanges {
+			sb.WriteString(fmt.Sprintf("%.2f,%d,%d\n", change.At.Seconds(), change.From, change.To))
+		}
+	}
+	// Mudanças do -watch
+	if len(r.Timeline) > 0 {
+		sb.WriteString("\nTimeline\n")
+		sb.WriteString("At (s),Change\n")
+		for _, event := range r.Timeline {
+			sb.WriteString(fmt.Sprintf("%.2f,%s\n", event.At.Seconds(), csvField(event.Change)))
+		}
+	}
+	return sb.String()
+}
+
+// ExportSizeSweep grava as rodadas do -body-sizes como um único array JSON
+// de {body_size, p95, report}.
+func (j JSONExporter) ExportSizeSweep(steps []loadtest.SweepStep) string {
+	data, _ := json.MarshalIndent(steps, "", " ")
+	return string(data)
+}
+
+// ExportSizeSweep grava a tabela que compara as rodadas do -body-sizes,
+// seguida do CSV completo de cada uma.
+func (c CSVExporter) ExportSizeSweep(steps []loadtest.SweepStep) string {
+	var sb strings.Builder
+	sb.WriteString("Payload Size Sweep\n")
+	sb.WriteString("Body Size (bytes),Requests,Errors,Avg Duration (ms),P95 (ms),RPS,Upload MB/s\n")
+	for _, step := range steps {
+		r := step.Report
+		avg, p95 := "", ""
+		if r.HasLatency() {
+			avg = fmt.Sprintf("%.2f", float64(r.AvgDuration.Microseconds())/1000)
+			p95 = fmt.Sprintf("%.2f", float64(r.Percentile(95).Microseconds())/1000)
+		}
+		sb.WriteString(fmt.Sprintf("%d,%d,%d,%s,%s,%.2f,%.2f\n", step.Size, r.TotalRequests, r.Errors, avg, p95, r.RPS, r.UploadRate/(1<<20)))
+	}
+	for _, step := range steps {
+		sb.WriteString("\n")
+		sb.WriteString(c.Export(step.Report))
+	}
+	return sb.String()
+}
+
+// csvField coloca entre aspas os valores com vírgula, aspas ou quebra de linha.
+func csvField(value string) string {
+	if !strings.ContainsAny(value, ",\"\n\r") {
+		return value
+	}
+	return `"` + strings.ReplaceAll(value, `"`, `""`) + `"`
+}
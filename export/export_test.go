@@ -0,0 +1,61 @@
+package export
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+func sweepSteps() []loadtest.SweepStep {
+	var steps []loadtest.SweepStep
+	for i, size := range []int64{1024, 4096} {
+		latencies := loadtest.NewHistogram()
+		latencies.Add(time.Duration(i+1) * 10 * time.Millisecond)
+		steps = append(steps, loadtest.SweepStep{Size: size, Report: loadtest.Report{
+			TotalRequests: 1,
+			StatusCodes:   map[int]int{200: 1},
+			ErrorKinds:    map[loadtest.ErrorKind]int{},
+			Latencies:     latencies,
+			BodySize:      size,
+		}})
+	}
+	return steps
+}
+
+// A varredura sai como um único array JSON, com o tamanho de cada rodada.
+func TestExportSizeSweepJSON(t *testing.T) {
+	var steps []struct {
+		BodySize int64            `json:"body_size"`
+		P95      *time.Duration   `json:"p95"`
+		Report   *json.RawMessage `json:"report"`
+	}
+	if err := json.Unmarshal([]byte(JSONExporter{}.ExportSizeSweep(sweepSteps())), &steps); err != nil {
+		t.Fatal(err)
+	}
+	if len(steps) != 2 || steps[0].BodySize != 1024 || steps[1].BodySize != 4096 {
+		t.Fatalf("steps = %+v", steps)
+	}
+	for _, step := range steps {
+		if step.P95 == nil || step.Report == nil {
+			t.Errorf("body_size %d: p95 or report missing", step.BodySize)
+		}
+	}
+}
+
+func TestExportSizeSweepCSV(t *testing.T) {
+	lines := strings.Split(CSVExporter{}.ExportSizeSweep(sweepSteps()), "\n")
+	if !strings.HasPrefix(lines[1], "Body Size (bytes),") {
+		t.Errorf("comparison header = %q", lines[1])
+	}
+	for i, prefix := range []string{"1024,1,0,", "4096,1,0,"} {
+		if !strings.HasPrefix(lines[2+i], prefix) {
+			t.Errorf("row %d = %q, want prefix %q", i, lines[2+i], prefix)
+		}
+	}
+	if !strings.HasSuffix(lines[5], ",Body Size (bytes)") || !strings.HasSuffix(lines[6], ",1024") {
+		t.Errorf("step report is not tagged with its size: %q / %q", lines[5], lines[6])
+	}
+}
@@ -0,0 +1,356 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+	_ "modernc.org/sqlite"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+const defaultHistoryPath = "~/.stress/history.db"
+
+// historyRun é o resumo de uma execução gravado no banco do -history.
+type historyRun struct {
+	ID        int64
+	Name      string
+	StartedAt time.Time
+	URL       string
+	Mode      string
+	Args      []string
+	GitSHA    string
+	Requests  int
+	Errors    int
+	ErrorRate float64
+	RPS       float64
+	TotalTime time.Duration
+	Min       time.Duration
+	Avg       time.Duration
+	Max       time.Duration
+	P50       time.Duration
+	P90       time.Duration
+	P95       time.Duration
+	P99       time.Duration
+	Stopped   bool
+	Passed    *bool // nil quando nenhum limite foi definido
+}
+
+const historySchema = `
+CREATE TABLE IF NOT EXISTS runs (
+	id          INTEGER PRIMARY KEY AUTOINCREMENT,
+	name        TEXT NOT NULL,
+	started_at  TEXT NOT NULL,
+	url         TEXT NOT NULL,
+	mode        TEXT NOT NULL,
+	args        TEXT NOT NULL,
+	git_sha     TEXT NOT NULL,
+	requests    INTEGER NOT NULL,
+	errors      INTEGER NOT NULL,
+	error_rate  REAL NOT NULL,
+	rps         REAL NOT NULL,
+	total_ms    REAL NOT NULL,
+	min_ms      REAL NOT NULL,
+	avg_ms      REAL NOT NULL,
+	max_ms      REAL NOT NULL,
+	p50_ms      REAL NOT NULL,
+	p90_ms      REAL NOT NULL,
+	p95_ms      REAL NOT NULL,
+	p99_ms      REAL NOT NULL,
+	stopped     INTEGER NOT NULL,
+	passed      INTEGER
+);
+CREATE INDEX IF NOT EXISTS runs_name_started ON runs (name, started_at);
+`
+
+// expandHome troca o ~ inicial pelo diretório do usuário, mesmo quando o
+// shell não o expande (caminho entre aspas).
+func expandHome(path string) (string, error) {
+	if path != "~" && !strings.HasPrefix(path, "~/") {
+		return path, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, strings.TrimPrefix(path, "~")), nil
+}
+
+func openHistory(path string) (*sql.DB, error) {
+	path, err := expandHome(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating history directory: %w", err)
+	}
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("opening history: %w", err)
+	}
+	if _, err := db.Exec(historySchema); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("opening history %s: %w", path, err)
+	}
+	return db, nil
+}
+
+func newHistoryRun(name, url string, args []string, result loadtest.Report, verdict *report.Verdict) historyRun {
+	run := historyRun{
+		Name:      name,
+		StartedAt: time.Now().Add(-result.TotalTime).UTC(),
+		URL:       url,
+		Mode:      result.Mode,
+		Args:      args,
+		GitSHA:    gitSHA(result.Metadata),
+		Requests:  result.TotalRequests,
+		Errors:    result.Errors,
+		ErrorRate: report.ErrorRate(result),
+		RPS:       result.RPS,
+		TotalTime: result.TotalTime,
+		Stopped:   result.Stopped,
+	}
+	// Sem latências medidas as colunas ficam zeradas, em vez das durações
+	// das falhas e do mínimo inicial do relatório
+	if result.HasLatency() {
+		run.Min, run.Avg, run.Max = result.MinDuration, result.AvgDuration, result.MaxDuration
+		run.P50, run.P90, run.P95, run.P99 = result.Percentile(50), result.Percentile(90), result.Percentile(95), result.Percentile(99)
+	}
+	if run.Mode == "" {
+		run.Mode = "http"
+	}
+	if verdict != nil {
+		run.Passed = &verdict.Passed
+	}
+	return run
+}
+
+func saveHistoryRun(path string, run historyRun) (int64, error) {
+	db, err := openHistory(path)
+	if err != nil {
+		return 0, err
+	}
+	defer db.Close()
+
+	args, _ := json.Marshal(run.Args)
+	var passed any
+	if run.Passed != nil {
+		passed = *run.Passed
+	}
+	res, err := db.Exec(`INSERT INTO runs (name, started_at, url, mode, args, git_sha, requests, errors,
+		error_rate, rps, total_ms, min_ms, avg_ms, max_ms, p50_ms, p90_ms, p95_ms, p99_ms, stopped, passed)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		run.Name, run.StartedAt.Format(time.RFC3339Nano), run.URL, run.Mode, string(args), run.GitSHA,
+		run.Requests, run.Errors, run.ErrorRate, run.RPS, toMillis(run.TotalTime), toMillis(run.Min),
+		toMillis(run.Avg), toMillis(run.Max), toMillis(run.P50), toMillis(run.P90), toMillis(run.P95),
+		toMillis(run.P99), run.Stopped, passed)
+	if err != nil {
+		return 0, fmt.Errorf("saving run to history: %w", err)
+	}
+	return res.LastInsertId()
+}
+
+func toMillis(d time.Duration) float64 {
+	return float64(d) / float64(time.Millisecond)
+}
+
+func fromMillis(ms float64) time.Duration {
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+const historyColumns = `id, name, started_at, url, mode, args, git_sha, requests, errors, error_rate, rps,
+	total_ms, min_ms, avg_ms, max_ms, p50_ms, p90_ms, p95_ms, p99_ms, stopped, passed`
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanHistoryRun(row rowScanner) (historyRun, error) {
+	var run historyRun
+	var startedAt, args string
+	var total, minMS, avg, maxMS, p50, p90, p95, p99 float64
+	var passed sql.NullBool
+	err := row.Scan(&run.ID, &run.Name, &startedAt, &run.URL, &run.Mode, &args, &run.GitSHA,
+		&run.Requests, &run.Errors, &run.ErrorRate, &run.RPS, &total, &minMS, &avg, &maxMS,
+		&p50, &p90, &p95, &p99, &run.Stopped, &passed)
+	if err != nil {
+		return run, err
+	}
+	run.StartedAt, _ = time.Parse(time.RFC3339Nano, startedAt)
+	json.Unmarshal([]byte(args), &run.Args)
+	run.TotalTime, run.Min, run.Avg, run.Max = fromMillis(total), fromMillis(minMS), fromMillis(avg), fromMillis(maxMS)
+	run.P50, run.P90, run.P95, run.P99 = fromMillis(p50), fromMillis(p90), fromMillis(p95), fromMillis(p99)
+	if passed.Valid {
+		run.Passed = &passed.Bool
+	}
+	return run, nil
+}
+
+// listHistoryRuns devolve as últimas execuções, da mais recente para a mais
+// antiga, opcionalmente filtradas pelo nome do teste.
+func listHistoryRuns(db *sql.DB, name string, limit int) ([]historyRun, error) {
+	query := "SELECT " + historyColumns + " FROM runs"
+	var params []any
+	if name != "" {
+		query += " WHERE name = ?"
+		params = append(params, name)
+	}
+	query += " ORDER BY started_at DESC, id DESC LIMIT ?"
+	params = append(params, limit)
+
+	rows, err := db.Query(query, params...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var runs []historyRun
+	for rows.Next() {
+		run, err := scanHistoryRun(rows)
+		if err != nil {
+			return nil, err
+		}
+		runs = append(runs, run)
+	}
+	return runs, rows.Err()
+}
+
+func getHistoryRun(db *sql.DB, id int64) (historyRun, error) {
+	run, err := scanHistoryRun(db.QueryRow("SELECT "+historyColumns+" FROM runs WHERE id = ?", id))
+	if errors.Is(err, sql.ErrNoRows) {
+		return run, fmt.Errorf("run %d not found in history", id)
+	}
+	return run, err
+}
+
+// hasLatency informa se a execução gravou latências; sem nenhuma medida
+// elas ficam zeradas.
+func (r historyRun) hasLatency() bool {
+	return r.Max > 0
+}
+
+func (r historyRun) result() string {
+	switch {
+	case r.Passed != nil && *r.Passed:
+		return "passed"
+	case r.Passed != nil:
+		return "failed"
+	case r.Stopped:
+		return "stopped"
+	default:
+		return "-"
+	}
+}
+
+// historyCommand implementa o subcomando `history`: `history [list]` lista as
+// execuções gravadas e `history show ID` detalha uma delas.
+func historyCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "history [flags] [list | show ID]",
+		Short:   "List the runs recorded with -history",
+		GroupID: "reports",
+		Args:    cobra.NoArgs,
+	}
+	dbFlag := cmd.PersistentFlags().String("db", defaultHistoryPath, "History database written by -history")
+	// `history` sozinho é o mesmo que `history list`
+	listFlags := func(cmd *cobra.Command) {
+		nameFlag := cmd.Flags().String("name", "", "Only list runs of the test with this name")
+		limitFlag := cmd.Flags().Int("limit", 20, "Maximum number of runs listed")
+		addLangFlag(cmd)
+		cmd.RunE = func(*cobra.Command, []string) error {
+			return listHistory(*dbFlag, *nameFlag, *limitFlag)
+		}
+	}
+	listFlags(cmd)
+	list := &cobra.Command{
+		Use:   "list [flags]",
+		Short: "List the recorded runs (the default)",
+		Args:  cobra.NoArgs,
+	}
+	listFlags(list)
+
+	show := &cobra.Command{
+		Use:   "show [flags] ID",
+		Short: "Show the details of a recorded run",
+		Args:  cobra.ExactArgs(1),
+	}
+	addLangFlag(show)
+	show.RunE = func(_ *cobra.Command, args []string) error {
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid run ID %q", args[0])
+		}
+		db, err := openHistory(*dbFlag)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+		run, err := getHistoryRun(db, id)
+		if err != nil {
+			return err
+		}
+		printHistoryRun(run)
+		return nil
+	}
+	cmd.AddCommand(list, show)
+	return cmd
+}
+
+func listHistory(path, name string, limit int) error {
+	db, err := openHistory(path)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+	runs, err := listHistoryRuns(db, name, limit)
+	if err != nil {
+		return fmt.Errorf("listing runs: %w", err)
+	}
+	if len(runs) == 0 {
+		fmt.Println(report.Msg("No runs recorded yet"))
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, report.Msg("ID\tSTARTED\tNAME\tREQUESTS\tRPS\tP95\tERRORS\tRESULT"))
+	for _, run := range runs {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%d\t%.2f\t%s\t%.2f%%\t%s\n", run.ID,
+			run.StartedAt.Local().Format("2006-01-02 15:04:05"), run.Name, run.Requests,
+			run.RPS, report.Latency(run.P95.Round(time.Microsecond), run.hasLatency()), run.ErrorRate, report.Msg(run.result()))
+	}
+	w.Flush()
+	return nil
+}
+
+func printHistoryRun(run historyRun) {
+	fmt.Printf(report.Msg("\n🗂️  Run %d: %s\n"), run.ID, run.Name)
+	fmt.Println("----------------------------------------")
+	fmt.Printf(report.Msg("Started: %s\n"), run.StartedAt.Local().Format(time.RFC3339))
+	fmt.Printf("URL: %s\n", run.URL)
+	fmt.Printf(report.Msg("Mode: %s\n"), run.Mode)
+	fmt.Printf(report.Msg("Git commit: %s\n"), run.GitSHA)
+	fmt.Printf(report.Msg("Arguments: %s\n"), strings.Join(run.Args, " "))
+	fmt.Printf(report.Msg("Result: %s\n"), report.Msg(run.result()))
+	fmt.Println("----------------------------------------")
+	fmt.Printf(report.Msg("Total Time: %.2f seconds\n"), run.TotalTime.Seconds())
+	fmt.Printf(report.Msg("Total Requests: %d\n"), run.Requests)
+	fmt.Printf(report.Msg("Requests per Second: %.2f\n"), run.RPS)
+	fmt.Printf(report.Msg("Errors: %d (%.2f%%)\n"), run.Errors, run.ErrorRate)
+	fmt.Println("----------------------------------------")
+	measured := run.hasLatency()
+	fmt.Printf(report.Msg("Minimum: %v\n"), report.Latency(run.Min, measured))
+	fmt.Printf(report.Msg("Average: %v\n"), report.Latency(run.Avg, measured))
+	fmt.Printf(report.Msg("Maximum: %v\n"), report.Latency(run.Max, measured))
+	fmt.Printf("P50: %v\n", report.Latency(run.P50, measured))
+	fmt.Printf("P90: %v\n", report.Latency(run.P90, measured))
+	fmt.Printf("P95: %v\n", report.Latency(run.P95, measured))
+	fmt.Printf("P99: %v\n", report.Latency(run.P99, measured))
+}
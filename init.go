@@ -0,0 +1,200 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// initCommand implementa o subcomando `init`: pergunta o alvo, a carga e os
+// limites e grava um arquivo para o -config, sem precisar conhecer as flags.
+func initCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "init [-o stress.json]",
+		Short:   "Ask for the target, load and thresholds and write a -config file",
+		GroupID: "test",
+		Args:    cobra.NoArgs,
+	}
+	outputFlag := cmd.Flags().StringP("output", "o", "stress.json", "Config file to write")
+	addLangFlag(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		w := &wizard{in: bufio.NewReader(os.Stdin), out: os.Stdout}
+		if _, err := os.Stat(*outputFlag); err == nil {
+			answer, err := w.ask(report.Msg("File exists, overwrite? (y/N)"), "n", nil)
+			if err != nil || !strings.HasPrefix(strings.ToLower(answer), "y") {
+				return
+			}
+		}
+		values, err := w.run()
+		if err != nil {
+			slog.Error("init aborted", "error", err)
+			os.Exit(1)
+		}
+		data, _ := json.MarshalIndent(values, "", "  ")
+		if err := os.WriteFile(*outputFlag, append(data, '\n'), 0o644); err != nil {
+			slog.Error("writing the config", "error", err)
+			os.Exit(1)
+		}
+		fmt.Fprintf(w.out, report.Msg("\n✅ Config written to %s\n"), *outputFlag)
+		fmt.Fprintf(w.out, report.Msg("Run it with: %s -config %s\n"), os.Args[0], *outputFlag)
+	}
+	return cmd
+}
+
+// wizard faz as perguntas do `init`, repetindo cada uma até a resposta ser
+// válida.
+type wizard struct {
+	in  *bufio.Reader
+	out io.Writer
+}
+
+// ask mostra a pergunta com a resposta padrão entre colchetes; uma linha
+// vazia fica com o padrão.
+func (w *wizard) ask(question, def string, check func(string) error) (string, error) {
+	for {
+		if def != "" {
+			fmt.Fprintf(w.out, "%s [%s]: ", question, def)
+		} else {
+			fmt.Fprintf(w.out, "%s: ", question)
+		}
+		line, err := w.in.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", errors.New("no more input")
+		}
+		answer := strings.TrimSpace(line)
+		if answer == "" {
+			answer = def
+		}
+		if check == nil {
+			return answer, nil
+		}
+		if err := check(answer); err != nil {
+			fmt.Fprintf(w.out, "  %v\n", err)
+			continue
+		}
+		return answer, nil
+	}
+}
+
+// run faz as perguntas e devolve os valores do -config, indexados pelo nome
+// das flags. Respostas vazias não entram no arquivo.
+func (w *wizard) run() (map[string]any, error) {
+	values := make(map[string]any)
+	questions := []struct {
+		flag     string
+		question string
+		def      string
+		check    func(string) error
+		skip     func() bool
+	}{
+		{flag: "url", question: "Target URL", check: checkURL},
+		{flag: "method", question: "HTTP method", def: "GET", check: checkMethod},
+		{flag: "headers", question: "Headers as Name:Value, separated by commas (empty for none)", check: checkHeaders},
+		{flag: "body", question: "Request body (empty for none)", skip: func() bool {
+			method := values["method"]
+			return method == "GET" || method == "HEAD" || method == "DELETE" || method == "OPTIONS"
+		}},
+		{flag: "requests", question: "Total number of requests", def: "1000", check: checkPositiveInt},
+		{flag: "concurrency", question: "Concurrent requests", def: "10", check: checkPositiveInt},
+		{flag: "max-duration", question: "Time limit, e.g. 5m (empty for none)", check: checkDuration},
+		{flag: "max-p95", question: "Fail when the p95 latency is above, e.g. 500ms (empty to skip)", check: checkDuration},
+		{flag: "max-error-rate", question: "Fail when the error rate is above this percentage (empty to skip)", check: checkPercent},
+		{flag: "min-rps", question: "Fail when the throughput is below this many requests per second (empty to skip)", check: checkNonNegative},
+	}
+	for _, q := range questions {
+		if q.skip != nil && q.skip() {
+			continue
+		}
+		answer, err := w.ask(report.Msg(q.question), q.def, q.check)
+		if err != nil {
+			return nil, err
+		}
+		if answer == "" {
+			continue
+		}
+		switch q.flag {
+		case "requests", "concurrency":
+			values[q.flag], _ = strconv.Atoi(answer)
+		case "max-error-rate", "min-rps":
+			values[q.flag], _ = strconv.ParseFloat(answer, 64)
+		case "method":
+			values[q.flag] = strings.ToUpper(answer)
+		default:
+			values[q.flag] = answer
+		}
+	}
+	return values, nil
+}
+
+func checkURL(value string) error {
+	u, err := url.Parse(value)
+	if err != nil || u.Scheme == "" || u.Host == "" {
+		return errors.New(report.Msg("enter a full URL, e.g. https://api.example.com/health"))
+	}
+	return nil
+}
+
+func checkMethod(value string) error {
+	if value == "" || strings.Trim(strings.ToUpper(value), "ABCDEFGHIJKLMNOPQRSTUVWXYZ") != "" {
+		return errors.New(report.Msg("enter a method such as GET or POST"))
+	}
+	return nil
+}
+
+func checkHeaders(value string) error {
+	for _, pair := range splitList(value) {
+		if name, _, ok := strings.Cut(pair, ":"); !ok || strings.TrimSpace(name) == "" {
+			return fmt.Errorf(report.Msg("invalid header %q, expected Name:Value"), pair)
+		}
+	}
+	return nil
+}
+
+func checkPositiveInt(value string) error {
+	if n, err := strconv.Atoi(value); err != nil || n < 1 {
+		return errors.New(report.Msg("enter a whole number of at least 1"))
+	}
+	return nil
+}
+
+func checkDuration(value string) error {
+	if value == "" {
+		return nil
+	}
+	if d, err := time.ParseDuration(value); err != nil || d <= 0 {
+		return errors.New(report.Msg("enter a duration such as 500ms, 30s or 5m"))
+	}
+	return nil
+}
+
+func checkPercent(value string) error {
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.ParseFloat(value, 64); err != nil || n < 0 || n > 100 {
+		return errors.New(report.Msg("enter a percentage between 0 and 100"))
+	}
+	return nil
+}
+
+func checkNonNegative(value string) error {
+	if value == "" {
+		return nil
+	}
+	if n, err := strconv.ParseFloat(value, 64); err != nil || n < 0 {
+		return errors.New(report.Msg("enter a number of at least 0"))
+	}
+	return nil
+}
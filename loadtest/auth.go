@@ -0,0 +1,121 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"golang.org/x/oauth2"
+	"golang.org/x/oauth2/clientcredentials"
+)
+
+type BasicAuth struct {
+	Username string
+	Password string
+}
+
+func parseBasicAuth(value string) (*BasicAuth, error) {
+	if value == "" {
+		return nil, nil
+	}
+	user, pass, ok := strings.Cut(value, ":")
+	if !ok || user == "" {
+		return nil, fmt.Errorf("invalid -basic-auth (use user:pass)")
+	}
+	return &BasicAuth{Username: user, Password: pass}, nil
+}
+
+type OAuth2Options struct {
+	TokenURL     string
+	ClientID     string
+	ClientSecret string
+	Scopes       string
+}
+
+// newOAuth2TokenSource obtém um token via client credentials antes do teste.
+// O TokenSource devolvido renova o token automaticamente perto da expiração,
+// compartilhando-o entre todos os workers.
+func newOAuth2TokenSource(opts OAuth2Options) (oauth2.TokenSource, error) {
+	if opts.TokenURL == "" {
+		return nil, nil
+	}
+	if opts.ClientID == "" {
+		return nil, fmt.Errorf("-oauth2-client-id is required with -oauth2-token-url")
+	}
+
+	secret, err := resolveSecret(opts.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+
+	cc := &clientcredentials.Config{
+		ClientID:     opts.ClientID,
+		ClientSecret: secret,
+		TokenURL:     opts.TokenURL,
+	}
+	if opts.Scopes != "" {
+		cc.Scopes = strings.Split(opts.Scopes, ",")
+	}
+
+	source := cc.TokenSource(context.Background())
+	if _, err := source.Token(); err != nil {
+		return nil, fmt.Errorf("fetching OAuth2 token: %w", err)
+	}
+	return source, nil
+}
+
+// resolveSecret lê o valor de um literal, de um arquivo (@arquivo) ou de uma
+// variável de ambiente (env:VAR), evitando segredos no histórico do shell.
+func resolveSecret(value string) (string, error) {
+	switch {
+	case strings.HasPrefix(value, "@"):
+		data, err := os.ReadFile(value[1:])
+		if err != nil {
+			return "", fmt.Errorf("reading secret file: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	case strings.HasPrefix(value, "env:"):
+		name := value[len("env:"):]
+		secret, ok := os.LookupEnv(name)
+		if !ok {
+			return "", fmt.Errorf("environment variable %s is not set", name)
+		}
+		return strings.TrimSpace(secret), nil
+	default:
+		return value, nil
+	}
+}
+
+// applyAuth adiciona as credenciais configuradas à requisição.
+func applyAuth(req *http.Request, config Config) error {
+	if config.BasicAuth != nil {
+		req.SetBasicAuth(config.BasicAuth.Username, config.BasicAuth.Password)
+	}
+	if config.BearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+config.BearerToken)
+	}
+	if config.OAuth2 != nil {
+		token, err := config.OAuth2.Token()
+		if err != nil {
+			return fmt.Errorf("refreshing OAuth2 token: %w", err)
+		}
+		token.SetAuthHeader(req)
+	}
+	if config.NTLM != nil {
+		// O Negotiator converte as credenciais Basic no handshake NTLM
+		req.SetBasicAuth(config.NTLM.Username, config.NTLM.Password)
+	}
+	if config.Kerberos != nil {
+		return config.Kerberos.apply(req)
+	}
+	if config.JWT != nil {
+		token, err := config.JWT.mint()
+		if err != nil {
+			return fmt.Errorf("signing JWT: %w", err)
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	return nil
+}
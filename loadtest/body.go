@@ -0,0 +1,211 @@
+package loadtest
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/textproto"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+type FormField struct {
+	Name        string
+	Value       string
+	File        string
+	ContentType string
+	data        []byte
+}
+
+// parseFormFields interpreta campos no estilo do curl: "campo=valor" ou
+// "campo=@arquivo[;type=mime/tipo]". Os arquivos são lidos uma única vez.
+func parseFormFields(values []string) ([]FormField, error) {
+	var fields []FormField
+	for _, value := range values {
+		name, rest, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid -form %q (use field=value or field=@file)", value)
+		}
+
+		field := FormField{Name: name}
+		if !strings.HasPrefix(rest, "@") {
+			field.Value = rest
+			fields = append(fields, field)
+			continue
+		}
+
+		path, contentType, _ := strings.Cut(rest[1:], ";type=")
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading form file: %w", err)
+		}
+		if contentType == "" {
+			contentType = mime.TypeByExtension(filepath.Ext(path))
+		}
+		if contentType == "" {
+			contentType = "application/octet-stream"
+		}
+		field.File = path
+		field.ContentType = contentType
+		field.data = data
+		fields = append(fields, field)
+	}
+	return fields, nil
+}
+
+type requestBody struct {
+	reader      io.Reader
+	length      int64 // -1 quando desconhecido
+	contentType string
+	streaming   bool
+}
+
+// newRequestBody monta o corpo de cada requisição. Corpos multipart são
+// gerados novamente a cada chamada, com um boundary próprio. Arquivos e
+// corpos sintéticos são enviados em streaming, sem passar pela memória.
+func newRequestBody(config Config, vars templateVars) (requestBody, error) {
+	switch {
+	case config.BodyFile != "":
+		file, err := os.Open(config.BodyFile)
+		if err != nil {
+			return requestBody{}, fmt.Errorf("opening body file: %w", err)
+		}
+		info, err := file.Stat()
+		if err != nil {
+			file.Close()
+			return requestBody{}, fmt.Errorf("reading body file: %w", err)
+		}
+		return requestBody{reader: file, length: info.Size(), streaming: true}, nil
+	case config.BodySize > 0:
+		return requestBody{reader: io.LimitReader(patternReader{}, config.BodySize), length: config.BodySize, streaming: true}, nil
+	case len(config.Form) > 0:
+		return newMultipartBody(config.Form)
+	case config.BodyTemplate != nil:
+		rendered, err := renderTemplate(config.BodyTemplate, vars)
+		if err != nil {
+			return requestBody{}, fmt.Errorf("rendering body template: %w", err)
+		}
+		return requestBody{reader: strings.NewReader(rendered), length: int64(len(rendered))}, nil
+	default:
+		return requestBody{reader: strings.NewReader(config.Body), length: int64(len(config.Body))}, nil
+	}
+}
+
+// gzipBody comprime o corpo da requisição. Corpos em memória são
+// comprimidos de uma vez para manter o Content-Length; corpos em streaming
+// são comprimidos sob demanda e enviados sem tamanho conhecido.
+func gzipBody(body requestBody) (requestBody, error) {
+	if !body.streaming {
+		var buf bytes.Buffer
+		gz := gzip.NewWriter(&buf)
+		if _, err := io.Copy(gz, body.reader); err != nil {
+			return requestBody{}, err
+		}
+		if err := gz.Close(); err != nil {
+			return requestBody{}, err
+		}
+		body.reader = &buf
+		body.length = int64(buf.Len())
+		return body, nil
+	}
+
+	pr, pw := io.Pipe()
+	source := body.reader
+	go func() {
+		gz := gzip.NewWriter(pw)
+		_, err := io.Copy(gz, source)
+		if err == nil {
+			err = gz.Close()
+		}
+		if closer, ok := source.(io.Closer); ok {
+			closer.Close()
+		}
+		pw.CloseWithError(err)
+	}()
+	body.reader = pr
+	body.length = -1
+	return body, nil
+}
+
+// chunkedReader entrega o corpo em pedaços de tamanho fixo, com uma pausa
+// opcional entre eles. Cada Read vira um chunk na codificação chunked,
+// simulando um produtor lento.
+type chunkedReader struct {
+	r       io.Reader
+	size    int
+	delay   time.Duration
+	started bool
+}
+
+func (c *chunkedReader) Read(p []byte) (int, error) {
+	if c.started && c.delay > 0 {
+		time.Sleep(c.delay)
+	}
+	c.started = true
+	if len(p) > c.size {
+		p = p[:c.size]
+	}
+	n, err := io.ReadFull(c.r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
+
+func (c *chunkedReader) Close() error {
+	if closer, ok := c.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// patternReader gera um fluxo infinito de bytes não repetitivos o bastante
+// para não ser trivialmente comprimido por proxies.
+type patternReader struct{}
+
+func (patternReader) Read(p []byte) (int, error) {
+	for i := range p {
+		p[i] = byte('a' + (i*7)%26)
+	}
+	return len(p), nil
+}
+
+func newMultipartBody(form []FormField) (requestBody, error) {
+	var buf bytes.Buffer
+	writer := multipart.NewWriter(&buf)
+	for _, field := range form {
+		if field.File == "" {
+			if err := writer.WriteField(field.Name, field.Value); err != nil {
+				return requestBody{}, err
+			}
+			continue
+		}
+
+		header := make(textproto.MIMEHeader)
+		header.Set("Content-Disposition", fmt.Sprintf(`form-data; name="%s"; filename="%s"`,
+			escapeQuotes(field.Name), escapeQuotes(filepath.Base(field.File))))
+		header.Set("Content-Type", field.ContentType)
+		part, err := writer.CreatePart(header)
+		if err != nil {
+			return requestBody{}, err
+		}
+		if _, err := part.Write(field.data); err != nil {
+			return requestBody{}, err
+		}
+	}
+	if err := writer.Close(); err != nil {
+		return requestBody{}, err
+	}
+	return requestBody{reader: &buf, length: int64(buf.Len()), contentType: writer.FormDataContentType()}, nil
+}
+
+var quoteEscaper = strings.NewReplacer("\\", "\\\\", `"`, "\\\"")
+
+func escapeQuotes(s string) string {
+	return quoteEscaper.Replace(s)
+}
@@ -0,0 +1,81 @@
+package loadtest
+
+import (
+	"net/http"
+	"sync"
+)
+
+// validatorStore guarda os validadores (ETag e Last-Modified) da última
+// resposta completa de cada URL, usados nas requisições condicionais.
+type validatorStore struct {
+	mu         sync.Mutex
+	validators map[string]cacheValidator
+}
+
+type cacheValidator struct {
+	etag         string
+	lastModified string
+}
+
+func newValidatorStore() *validatorStore {
+	return &validatorStore{validators: make(map[string]cacheValidator)}
+}
+
+// apply transforma a requisição em condicional quando já há validadores
+// para a URL. Retorna false enquanto a URL ainda não foi vista.
+func (s *validatorStore) apply(req *http.Request) bool {
+	s.mu.Lock()
+	v, ok := s.validators[req.URL.String()]
+	s.mu.Unlock()
+	if !ok {
+		return false
+	}
+	if v.etag != "" {
+		req.Header.Set("If-None-Match", v.etag)
+	}
+	if v.lastModified != "" {
+		req.Header.Set("If-Modified-Since", v.lastModified)
+	}
+	return true
+}
+
+func (s *validatorStore) update(req *http.Request, resp *http.Response) {
+	if resp.StatusCode != http.StatusOK {
+		return
+	}
+	v := cacheValidator{
+		etag:         resp.Header.Get("ETag"),
+		lastModified: resp.Header.Get("Last-Modified"),
+	}
+	if v.etag == "" && v.lastModified == "" {
+		return
+	}
+	s.mu.Lock()
+	s.validators[req.URL.String()] = v
+	s.mu.Unlock()
+}
+
+// CacheStats separa requisições condicionais das completas.
+type CacheStats struct {
+	Conditional   int
+	NotModified   int
+	NotModifiedPc float64    // 304 sobre o total de condicionais
+	Validation    PhaseStats // latência das requisições condicionais
+	Full          PhaseStats // latência das requisições sem validadores
+}
+
+func (c *CacheStats) add(result Result) {
+	if result.Error != nil {
+		return
+	}
+	if !result.Conditional {
+		c.Full.add(result.Duration)
+		return
+	}
+	c.Conditional++
+	if result.StatusCode == http.StatusNotModified {
+		c.NotModified++
+	}
+	c.Validation.add(result.Duration)
+	c.NotModifiedPc = float64(c.NotModified) / float64(c.Conditional) * 100
+}
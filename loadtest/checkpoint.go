@@ -0,0 +1,78 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"time"
+)
+
+// Checkpoint é o conteúdo do arquivo do -checkpoint: os relatórios parciais
+// de cada trecho do teste. Um teste retomado com -resume acrescenta um
+// trecho; o último é regravado a cada intervalo.
+type Checkpoint struct {
+	URL      string
+	Requests int    // total pedido no -requests, somando todos os trechos
+	SeqStart int64  // -seq-start do primeiro trecho
+	Seed     uint64 // semente do teste, repetida nos trechos retomados
+	Saved    time.Time
+	Segments []Report
+}
+
+// Done é o número de requisições já concluídas em todos os trechos.
+func (c Checkpoint) Done() int {
+	done := 0
+	for _, segment := range c.Segments {
+		done += segment.TotalRequests
+	}
+	return done
+}
+
+func ReadCheckpoint(path string) (*Checkpoint, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var checkpoint Checkpoint
+	if err := json.Unmarshal(data, &checkpoint); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", path, err)
+	}
+	return &checkpoint, nil
+}
+
+// Checkpointer grava o progresso do teste em Path a cada Interval, para
+// que uma execução longa interrompida ou derrubada possa ser retomada.
+type Checkpointer struct {
+	Path     string
+	Interval time.Duration
+	Base     Checkpoint // trechos anteriores, quando o teste foi retomado
+	last     time.Time
+}
+
+func (c *Checkpointer) due() bool {
+	if c.last.IsZero() {
+		c.last = time.Now()
+	}
+	return time.Since(c.last) >= c.Interval
+}
+
+// save grava os trechos anteriores mais o atual. Grava em um arquivo
+// temporário e renomeia, para que uma queda no meio não corrompa o último
+// checkpoint bom.
+func (c *Checkpointer) save(current Report) {
+	c.last = time.Now()
+	checkpoint := c.Base
+	checkpoint.Saved = c.last
+	checkpoint.Segments = append(checkpoint.Segments[:len(checkpoint.Segments):len(checkpoint.Segments)], current)
+	data, err := json.Marshal(checkpoint)
+	if err == nil {
+		tmp := c.Path + ".tmp"
+		if err = os.WriteFile(tmp, data, 0o644); err == nil {
+			err = os.Rename(tmp, c.Path)
+		}
+	}
+	if err != nil {
+		slog.Warn("writing the checkpoint", "path", c.Path, "error", err)
+	}
+}
@@ -0,0 +1,67 @@
+package loadtest
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+func acceptEncoding(compression string) (string, error) {
+	switch compression {
+	case "", "none":
+		return "", nil
+	case "gzip":
+		return "gzip", nil
+	case "br":
+		return "br", nil
+	default:
+		return "", fmt.Errorf("invalid compression %q (use gzip, br or none)", compression)
+	}
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+// Close repassa o fechamento ao leitor original (ex.: arquivo do corpo).
+func (c *countingReader) Close() error {
+	if closer, ok := c.r.(io.Closer); ok {
+		return closer.Close()
+	}
+	return nil
+}
+
+// readBody consome o corpo da resposta, descomprimindo-o quando necessário,
+// e escreve o conteúdo descomprimido em dst. Retorna os bytes recebidos na
+// conexão e os bytes após a descompressão.
+func readBody(resp *http.Response, dst io.Writer) (wire, decoded int64, compressed bool, err error) {
+	counter := &countingReader{r: resp.Body}
+
+	var body io.Reader = counter
+	switch strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding"))) {
+	case "gzip":
+		gz, gzErr := gzip.NewReader(counter)
+		if gzErr != nil {
+			io.Copy(io.Discard, counter)
+			return counter.n, 0, true, gzErr
+		}
+		defer gz.Close()
+		body, compressed = gz, true
+	case "br":
+		body, compressed = brotli.NewReader(counter), true
+	}
+
+	decoded, err = io.Copy(dst, body)
+	return counter.n, decoded, compressed, err
+}
@@ -0,0 +1,109 @@
+package loadtest
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ConcurrencyChange registra um ajuste da concorrência durante o teste.
+type ConcurrencyChange struct {
+	At   time.Duration // desde o início do teste
+	From int
+	To   int
+}
+
+// ConcurrencyControl muda a concorrência de um teste em andamento. O painel
+// de terminal usa as teclas + e -; o -ui e o serve expõem um endpoint.
+type ConcurrencyControl struct {
+	mu      sync.Mutex
+	target  int
+	current int
+	changed chan struct{}
+}
+
+func NewConcurrencyControl() *ConcurrencyControl {
+	return &ConcurrencyControl{changed: make(chan struct{}, 1)}
+}
+
+// Set pede a nova concorrência. Ela vale assim que o teste tiver usuários
+// virtuais livres para retirar ou acabar de criar os que faltam, e nunca
+// passa do número de requisições.
+func (c *ConcurrencyControl) Set(n int) error {
+	if n < 1 {
+		return fmt.Errorf("invalid concurrency %d, must be at least 1", n)
+	}
+	c.mu.Lock()
+	c.target = n
+	c.mu.Unlock()
+	select {
+	case c.changed <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Adjust soma delta à concorrência pedida, sem descer de 1.
+func (c *ConcurrencyControl) Adjust(delta int) {
+	c.mu.Lock()
+	n := max(1, c.target+delta)
+	c.mu.Unlock()
+	c.Set(n)
+}
+
+// Current é a concorrência em vigor; 0 antes do teste começar.
+func (c *ConcurrencyControl) Current() int {
+	if c == nil {
+		return 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current
+}
+
+// run aplica os pedidos de Set ao pool de workers até done ser fechado:
+// para subir, inicia workers com os VUs retirados ou novos; para descer,
+// retira workers conforme as requisições deles terminam.
+func (c *ConcurrencyControl) run(pool *workerPool, current, limit int, start time.Time, done <-chan struct{}) []ConcurrencyChange {
+	c.mu.Lock()
+	c.current = current
+	if c.target == 0 {
+		c.target = current
+	}
+	c.mu.Unlock()
+
+	var changes []ConcurrencyChange
+	for {
+		select {
+		case <-done:
+			return changes
+		case <-c.changed:
+		}
+		c.mu.Lock()
+		target := min(c.target, limit)
+		c.mu.Unlock()
+
+		from := current
+		for current < target && pool.add() {
+			current++
+		}
+	drain:
+		for current > target {
+			select {
+			case pool.retire <- struct{}{}:
+				current--
+			case <-pool.done:
+				break drain
+			case <-done:
+				break drain
+			}
+		}
+
+		c.mu.Lock()
+		c.current = current
+		c.mu.Unlock()
+		if current != from {
+			changes = append(changes, ConcurrencyChange{At: time.Since(start), From: from, To: current})
+		}
+	}
+}
@@ -0,0 +1,611 @@
+package loadtest
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+)
+
+// Flags registra em fs as flags que descrevem o teste e devolve a função
+// que monta a Config depois do fs.Parse. O CLI acrescenta as suas próprias
+// flags (saída, notificações, histórico) ao mesmo FlagSet.
+func Flags(fs *flag.FlagSet) func() (Config, error) {
+	urlFlag := fs.String("url", "", "URL to test")
+	requestsFlag := fs.Int("requests", 0, "Number of requests to make")
+	concurrencyFlag := fs.Int("concurrency", 1, "Number of concurrent requests")
+	seqStartFlag := fs.Int64("seq-start", 0, "Number requests for seq and .Seq after this value; -workers gives each worker its own range so the sequence is unique across the run")
+	seedFlag := fs.Uint64("seed", 0, "Seed for the random template functions, random -header-file/-query/-range-mode picks and DNS query IDs; the same seed repeats the same requests (0 = random, printed in the report)")
+	maxDurationFlag := fs.Duration("max-duration", 0, "Stop sending requests after this long even if -requests was not reached (0 = no limit)")
+	timeoutFlag := fs.Duration("timeout", 10*time.Second, "Timeout for each request")
+	connectTimeoutFlag := fs.Duration("connect-timeout", 30*time.Second, "Timeout for establishing the TCP connection")
+	tlsTimeoutFlag := fs.Duration("tls-timeout", 0, "Timeout for the TLS handshake (0 = no limit besides -timeout)")
+	responseHeaderTimeoutFlag := fs.Duration("response-header-timeout", 0, "Timeout waiting for response headers after the request is sent (0 = no limit besides -timeout)")
+	methodFlag := fs.String("method", "GET", "HTTP method to use")
+	formatFlag := fs.String("format", "plain", "Output format (plain, json, csv)")
+	progressFlag := fs.String("progress", "line", "Progress output: 'line' (updated in place) or 'json' (one JSON line per second)")
+	headersFlag := fs.String("headers", "", "Headers in format 'key1:value1,key2:value2'")
+	bodyFlag := fs.String("body", "", "Request body")
+	bodyTemplateFlag := fs.String("body-template", "", "Go template file rendered as the request body on every request")
+	bodyFileFlag := fs.String("body-file", "", "Stream the request body from this file")
+	bodySizeFlag := fs.String("body-size", "", "Stream a synthetic body of this size (e.g. 10MB, 1GB)")
+	bodySizesFlag := fs.String("body-sizes", "", "Repeat the test for each synthetic body size and compare them (e.g. 1KB,10KB,100KB,1MB)")
+	chunkedFlag := fs.Bool("chunked", false, "Send the request body with chunked transfer encoding")
+	chunkSizeFlag := fs.String("chunk-size", "16KB", "Chunk size used with -chunked")
+	chunkDelayFlag := fs.Duration("chunk-delay", 0, "Delay between chunks used with -chunked (simulates a slow producer)")
+	compressBodyFlag := fs.Bool("compress-body", false, "Gzip the request body and set Content-Encoding: gzip")
+	expectContinueFlag := fs.Bool("expect-continue", false, "Send 'Expect: 100-continue' and wait for the interim response before uploading the body")
+	continueTimeoutFlag := fs.Duration("continue-timeout", time.Second, "How long to wait for '100 Continue' before sending the body anyway")
+	var formFlag stringList
+	var trailerFlag stringList
+	var queryFlag stringList
+	var headerFileFlag stringList
+	var protoFlag stringList
+	var redisCommandFlag stringList
+	targetsFlag := fs.String("targets", "", "JSON file with a list of requests (name, method, url, headers, body) sent in rotation")
+	postmanFlag := fs.String("postman", "", "Run the requests of a Postman collection (v2.1 JSON) in rotation")
+	postmanEnvFlag := fs.String("postman-env", "", "Postman environment file with the variables used by -postman")
+	var fromCurlFlag stringList
+	fs.Var(&fromCurlFlag, "from-curl", "Build the request from a curl command, e.g. pasted from 'Copy as cURL' (repeatable; several are sent in rotation)")
+	replayFlag := fs.String("replay", "", "Replay the requests of a -request-log file in their original order and timing")
+	replaySpeedFlag := fs.Float64("replay-speed", 1, "Speed multiplier for -replay (2 = twice as fast, 0 = as fast as possible)")
+	harFlag := fs.String("har", "", "Replay the requests of a HAR file exported by the browser in rotation")
+	preserveTimingFlag := fs.Bool("preserve-timing", false, "Keep the original gaps between -har or -targets requests (uses offset_ms)")
+	saveTargetsFlag := fs.String("save-targets", "", "Write the requests converted from -postman or -har to a -targets file")
+	fs.Var(&redisCommandFlag, "redis-command", "Command sent in Redis mode (-url redis://host:port), e.g. 'SET key:{{seq}} value'; several are sent in rotation (repeatable, default PING)")
+	fs.Var(&protoFlag, "proto", "Proto file describing the gRPC service (repeatable; without it the schema comes from server reflection)")
+	var importPathFlag stringList
+	fs.Var(&importPathFlag, "import-path", "Directory searched for proto imports (repeatable)")
+	callFlag := fs.String("call", "", "gRPC method to call as pkg.Service/Method (enables gRPC mode; -url is grpc://host:port or grpcs://host:port)")
+	dataFlag := fs.String("data", "", "gRPC request message as JSON (templates allowed); a JSON array sends several messages on client-streaming calls")
+	streamCountFlag := fs.Int("stream-count", 1, "How many times the -data messages are sent on client-streaming and bidi calls")
+	streamIntervalFlag := fs.Duration("stream-interval", 0, "Delay between messages sent on a gRPC stream")
+	fs.Var(&headerFileFlag, "header-file", "Rotate a header through the lines of a file: 'Name:file' (round-robin) or 'Name:file:random' (repeatable)")
+	fs.Var(&queryFlag, "query", "Query parameter added per request: 'name=value', 'name=rand:1-1000', 'name=rand:a|b|c' or 'name=seq:1-10' (repeatable)")
+	fs.Var(&trailerFlag, "trailer", "Request trailer 'Name:value' sent after a chunked body (repeatable)")
+	fs.Var(&formFlag, "form", "Multipart form field 'name=value' or file 'name=@path[;type=mime]' (repeatable)")
+	basicAuthFlag := fs.String("basic-auth", "", "Basic auth credentials in format 'user:pass'")
+	oauth2TokenURLFlag := fs.String("oauth2-token-url", "", "OAuth2 token endpoint for the client-credentials flow")
+	oauth2ClientIDFlag := fs.String("oauth2-client-id", "", "OAuth2 client ID")
+	oauth2ClientSecretFlag := fs.String("oauth2-client-secret", "", "OAuth2 client secret: literal value, '@file' or 'env:VAR'")
+	oauth2ScopesFlag := fs.String("oauth2-scopes", "", "Comma-separated OAuth2 scopes")
+	ntlmFlag := fs.String("ntlm", "", "NTLM credentials in format 'DOMAIN\\user:pass' (password may be '@file' or 'env:VAR')")
+	kerberosPrincipalFlag := fs.String("kerberos-principal", "", "Kerberos principal (user@REALM) for SPNEGO/Negotiate auth")
+	kerberosPasswordFlag := fs.String("kerberos-password", "", "Kerberos password: literal value, '@file' or 'env:VAR'")
+	kerberosKeytabFlag := fs.String("kerberos-keytab", "", "Kerberos keytab file (instead of a password)")
+	krb5ConfFlag := fs.String("krb5-conf", "/etc/krb5.conf", "Path to krb5.conf")
+	kerberosSPNFlag := fs.String("kerberos-spn", "", "Service principal name (default HTTP/<host>)")
+	jwtKeyFlag := fs.String("jwt-key", "", "Sign a fresh JWT per request: HMAC secret ('@file', 'env:VAR') or PEM private key file")
+	jwtAlgFlag := fs.String("jwt-alg", "HS256", "JWT signing algorithm (HS256, RS256, ES256, PS256, EdDSA, ...)")
+	jwtClaimsFlag := fs.String("jwt-claims", "", "JWT claims as a JSON object or '@file' (jti, iat, nbf and exp are added per request)")
+	jwtTTLFlag := fs.Duration("jwt-ttl", 5*time.Minute, "JWT lifetime used for the exp claim")
+	loginURLFlag := fs.String("login-url", "", "Login endpoint called once per virtual user; the session is reused and renewed on 401")
+	loginMethodFlag := fs.String("login-method", "POST", "HTTP method of the login request")
+	loginBodyFlag := fs.String("login-body", "", "Body of the login request")
+	loginHeadersFlag := fs.String("login-headers", "", "Headers of the login request in format 'key1:value1,key2:value2'")
+	loginExtractFlag := fs.String("login-extract", "json:token", "Where to find the session token: json:path.to.token, header:Name or cookie:Name")
+	loginTokenHeaderFlag := fs.String("login-token-header", "Authorization", "Header that carries the extracted token (Authorization adds the Bearer prefix)")
+	bearerTokenFlag := fs.String("bearer-token", "", "Bearer token: literal value, '@file' or 'env:VAR'")
+	httpVersionFlag := fs.String("http", "auto", "HTTP protocol (auto, 1.1, 2, h2c)")
+	certFlag := fs.String("cert", "", "Client certificate for mutual TLS (PEM, or .p12/.pfx bundle)")
+	keyFlag := fs.String("key", "", "Client private key for mutual TLS (PEM)")
+	keyPassFlag := fs.String("key-pass", "", "Password for an encrypted client key or PKCS#12 bundle")
+	caCertFlag := fs.String("cacert", "", "CA bundle (PEM) used to verify the server certificate")
+	insecureFlag := fs.Bool("insecure", false, "Skip TLS certificate verification (unsafe)")
+	tlsMinFlag := fs.String("tls-min", "", "Minimum TLS version (1.0, 1.1, 1.2, 1.3)")
+	tlsMaxFlag := fs.String("tls-max", "", "Maximum TLS version (1.0, 1.1, 1.2, 1.3)")
+	connectToFlag := fs.String("connect-to", "", "Connect to this host:port instead of the URL host (Host and SNI are kept)")
+	successStatusFlag := fs.String("success-status", DefaultSuccessStatus, "Status codes counted as success in the summary: codes, classes and ranges, e.g. 2xx,304 or 200-204")
+	var expectStatusFlag stringList
+	fs.Var(&expectStatusFlag, "expect-status", "HTTP status codes expected from the target, e.g. 404 or 429 or 200-299 (repeatable); other responses count as errors and the summary success rate uses these codes instead of -success-status")
+	errorRulesFlag := fs.String("error-rules", "", "JSON file with custom error categories, severities and per-category max rates, matched by error kind, status and message")
+	var labelFlag stringList
+	fs.Var(&labelFlag, "label", "Label 'key=value' attached to the report, e.g. -label env=staging (repeatable)")
+	var resolveFlag stringList
+	fs.Var(&resolveFlag, "resolve", "Pin host:port to an address, curl-style 'host:port:addr' (repeatable)")
+	dnsServerFlag := fs.String("dns-server", "", "DNS server (ip[:port]) used instead of the system resolver")
+	ipv4Flag := fs.Bool("ipv4", false, "Only connect over IPv4")
+	ipv6Flag := fs.Bool("ipv6", false, "Only connect over IPv6")
+	unixSocketFlag := fs.String("unix-socket", "", "Dial this Unix domain socket instead of TCP (the URL supplies path and Host)")
+	disableKeepAliveFlag := fs.Bool("disable-keepalive", false, "Open a new connection for every request")
+	rangeModeFlag := fs.String("range-mode", "", "Send Range requests: fixed, random or sweep")
+	rangeSizeFlag := fs.String("range-size", "64KB", "Byte window requested by each Range request")
+	rangeOffsetFlag := fs.Int64("range-offset", 0, "Start of the window for -range-mode fixed")
+	rangeTotalFlag := fs.String("range-total", "", "Resource size for random/sweep windows (default: discovered with HEAD)")
+	sseFlag := fs.Bool("sse", false, "Server-Sent Events mode: hold each request open as an event stream and measure event latency")
+	sseDurationFlag := fs.Duration("sse-duration", 30*time.Second, "How long each SSE connection is held open")
+	tcpPayloadFlag := fs.String("tcp-payload", "", "Hex-encoded payload sent on each connection in TCP mode (-url tcp://host:port)")
+	tcpPayloadFileFlag := fs.String("tcp-payload-file", "", "File sent as the payload on each connection in TCP mode")
+	tcpReadFlag := fs.Int("tcp-read", -1, "Bytes to read back in TCP mode (-1 = the payload size, for echo servers)")
+	udpPayloadFlag := fs.String("udp-payload", "", "Hex-encoded datagram sent in UDP mode (-url udp://host:port)")
+	udpSizeFlag := fs.String("udp-size", "", "Size of a synthetic datagram in UDP mode (default 64 bytes)")
+	mqttTopicFlag := fs.String("mqtt-topic", "", "Topic to publish to in MQTT mode (-url mqtt://host:port), templates allowed")
+	mqttQoSFlag := fs.Int("mqtt-qos", 0, "QoS level for MQTT publish and subscribe (0, 1 or 2)")
+	mqttRateFlag := fs.Int("mqtt-rate", 0, "Messages per second across all clients in MQTT mode (0 = unlimited)")
+	mqttSubscribeFlag := fs.String("mqtt-subscribe", "", "Topic every MQTT client subscribes to, counting received messages")
+	udpRateFlag := fs.Int("udp-rate", 0, "Datagrams per second across all workers in UDP mode (0 = unlimited)")
+	udpReplyFlag := fs.Bool("udp-reply", false, "Wait for a reply to each datagram and report round trip and loss")
+	dnsQueryFlag := fs.String("dns-query", "", "DNS mode: name queried on every request against -dns-server or the system resolver (templates allowed)")
+	dnsTypeFlag := fs.String("dns-type", "A", "Record type queried in DNS mode (A, AAAA, CNAME, MX, NS, PTR, SOA, SRV, TXT, ANY)")
+	cacheValidateFlag := fs.Bool("cache-validate", false, "Send conditional requests (If-None-Match/If-Modified-Since) using validators from earlier responses")
+	compressionFlag := fs.String("compression", "none", "Accept-Encoding to negotiate (gzip, br, none)")
+	maxRedirectsFlag := fs.Int("max-redirects", 10, "Maximum number of redirects to follow")
+	noFollowFlag := fs.Bool("no-follow", false, "Do not follow redirects (3xx responses are reported as-is)")
+	prewarmFlag := fs.Bool("prewarm", false, "Open the keep-alive connection pool before measurement starts")
+	noDNSCacheFlag := fs.Bool("no-dns-cache", false, "Resolve the target on every new connection instead of caching")
+	dnsCacheTTLFlag := fs.Duration("dns-cache-ttl", 0, "Re-resolve cached DNS entries after this interval (0 = resolve once per run)")
+	localAddrFlag := fs.String("local-addr", "", "Source IP addresses or interface names for outgoing connections, comma-separated (rotated per connection)")
+	maxIdleConnsFlag := fs.Int("max-idle-conns", 0, "Maximum idle connections across all hosts (0 = match -max-idle-conns-per-host)")
+	maxIdleConnsPerHostFlag := fs.Int("max-idle-conns-per-host", 0, "Maximum idle connections per host (0 = max(concurrency, 100))")
+	maxConnsPerHostFlag := fs.Int("max-conns-per-host", 0, "Maximum connections per host, including active ones (0 = unlimited)")
+	idleConnTimeoutFlag := fs.Duration("idle-conn-timeout", 90*time.Second, "How long an idle connection stays in the pool")
+	writeBufferSizeFlag := fs.Int("write-buffer-size", 0, "Transport write buffer size in bytes (0 = 4KB default)")
+	readBufferSizeFlag := fs.Int("read-buffer-size", 0, "Transport read buffer size in bytes (0 = 4KB default)")
+	throttleFlag := fs.String("throttle", "", "Limit bandwidth per connection and direction (e.g. 1Mbps, 512Kbps, 100KB/s)")
+	hostHeaderFlag := fs.String("host-header", "", "Override the Host header and TLS SNI")
+	ciphersFlag := fs.String("ciphers", "", "Comma-separated TLS cipher suites (TLS 1.2 and below)")
+
+	return func() (Config, error) {
+		// Processar headers
+		headersMap := parseHeaders(*headersFlag)
+
+		config := Config{
+			URL:                   *urlFlag,
+			Requests:              *requestsFlag,
+			Concurrency:           *concurrencyFlag,
+			MaxDuration:           *maxDurationFlag,
+			Seed:                  *seedFlag,
+			SeqStart:              *seqStartFlag,
+			Timeout:               *timeoutFlag,
+			Method:                *methodFlag,
+			Format:                *formatFlag,
+			Progress:              *progressFlag,
+			Headers:               headersMap,
+			Body:                  *bodyFlag,
+			HTTPVersion:           *httpVersionFlag,
+			ConnectTo:             *connectToFlag,
+			HostHeader:            *hostHeaderFlag,
+			DNSServer:             *dnsServerFlag,
+			UnixSocket:            *unixSocketFlag,
+			DisableKeepAlive:      *disableKeepAliveFlag,
+			Compression:           *compressionFlag,
+			MaxRedirects:          *maxRedirectsFlag,
+			NoFollow:              *noFollowFlag,
+			Prewarm:               *prewarmFlag,
+			NoDNSCache:            *noDNSCacheFlag,
+			DNSCacheTTL:           *dnsCacheTTLFlag,
+			ConnectTimeout:        *connectTimeoutFlag,
+			TLSTimeout:            *tlsTimeoutFlag,
+			ResponseHeaderTimeout: *responseHeaderTimeoutFlag,
+			MaxIdleConns:          *maxIdleConnsFlag,
+			MaxIdleConnsPerHost:   *maxIdleConnsPerHostFlag,
+			MaxConnsPerHost:       *maxConnsPerHostFlag,
+			IdleConnTimeout:       *idleConnTimeoutFlag,
+			WriteBufferSize:       *writeBufferSizeFlag,
+			ReadBufferSize:        *readBufferSizeFlag,
+		}
+
+		var targets []Target
+		var err error
+		sources := 0
+		for _, source := range []string{*targetsFlag, *postmanFlag, *harFlag, *replayFlag, strings.Join(fromCurlFlag, "")} {
+			if source != "" {
+				sources++
+			}
+		}
+		switch {
+		case sources > 1:
+			return Config{}, errors.New("-targets, -postman, -har, -replay and -from-curl are mutually exclusive")
+		case *targetsFlag != "":
+			targets, err = loadTargets(*targetsFlag)
+		case *postmanFlag != "":
+			targets, err = loadPostmanTargets(*postmanFlag, *postmanEnvFlag)
+		case *harFlag != "":
+			targets, err = loadHARTargets(*harFlag)
+		case *replayFlag != "":
+			if *replaySpeedFlag < 0 {
+				return Config{}, errors.New("-replay-speed must not be negative")
+			}
+			targets, err = loadReplayTargets(*replayFlag, *replaySpeedFlag)
+		case len(fromCurlFlag) > 0:
+			for _, command := range fromCurlFlag {
+				var curl *curlCommand
+				if curl, err = parseCurlCommand(command); err != nil {
+					break
+				}
+				targets = append(targets, curl.Target)
+				// Opções de TLS e protocolo do curl valem para o teste todo
+				if curl.Insecure {
+					*insecureFlag = true
+				}
+				if curl.HTTP2 && config.HTTPVersion == "auto" {
+					config.HTTPVersion = "2"
+				}
+			}
+		}
+		if err != nil {
+			return Config{}, err
+		}
+		if targets != nil {
+			if *saveTargetsFlag != "" {
+				if err := SaveTargets(*saveTargetsFlag, targets); err != nil {
+					return Config{}, err
+				}
+				slog.Info("targets saved", "count", len(targets), "path", *saveTargetsFlag)
+			}
+			if err := prepareTargets(targets); err != nil {
+				return Config{}, err
+			}
+			if len(targets) > 1 {
+				slog.Info("running requests in rotation", "targets", len(targets))
+			}
+			config.Targets = targets
+			config.PreserveTiming = *preserveTimingFlag
+			if *replayFlag != "" {
+				// Por padrão o replay repete exatamente a sequência gravada
+				config.PreserveTiming = *replaySpeedFlag > 0
+				if config.Requests == 0 {
+					config.Requests = len(targets)
+				}
+			}
+			// Pré-aquecimento e descobertas usam o primeiro alvo
+			if config.URL == "" {
+				config.URL = targets[0].URL
+			}
+		}
+
+		if (config.URL == "" && *dnsQueryFlag == "") || config.Requests == 0 {
+			return Config{}, errors.New("URL and number of requests are required")
+		}
+		// Sem nenhum worker o pool nunca terminaria
+		if config.Requests < 1 {
+			return Config{}, fmt.Errorf("invalid -requests %d (use at least 1)", config.Requests)
+		}
+		if config.Concurrency < 1 {
+			return Config{}, fmt.Errorf("invalid -concurrency %d (use at least 1)", config.Concurrency)
+		}
+
+		if config.SeqStart < 0 {
+			return Config{}, fmt.Errorf("invalid -seq-start %d", config.SeqStart)
+		}
+
+		if config.MaxDuration < 0 {
+			return Config{}, fmt.Errorf("invalid -max-duration %v", config.MaxDuration)
+		}
+
+		if config.Format != "plain" && config.Format != "json" && config.Format != "csv" {
+			return Config{}, fmt.Errorf("invalid -format %q (use plain, json or csv)", config.Format)
+		}
+
+		if config.Progress != "line" && config.Progress != "json" {
+			return Config{}, fmt.Errorf("invalid -progress %q (use line or json)", config.Progress)
+		}
+
+		if _, err := parseHTTPVersion(config.HTTPVersion); err != nil {
+			return Config{}, err
+		}
+
+		if config.Labels, err = parseLabels(labelFlag); err != nil {
+			return Config{}, err
+		}
+
+		switch {
+		case *ipv4Flag && *ipv6Flag:
+			return Config{}, errors.New("-ipv4 and -ipv6 are mutually exclusive")
+		case *ipv4Flag:
+			config.IPFamily = "4"
+		case *ipv6Flag:
+			config.IPFamily = "6"
+		}
+
+		if _, err := acceptEncoding(config.Compression); err != nil {
+			return Config{}, err
+		}
+
+		if *callFlag != "" {
+			config.GRPC = &GRPCOptions{
+				ProtoFiles:     protoFlag,
+				ImportPaths:    importPathFlag,
+				Call:           *callFlag,
+				Data:           *dataFlag,
+				StreamCount:    *streamCountFlag,
+				StreamInterval: *streamIntervalFlag,
+			}
+		}
+
+		if strings.HasPrefix(config.URL, "tcp://") {
+			tcpOptions, err := newTCPOptions(*tcpPayloadFlag, *tcpPayloadFileFlag, *tcpReadFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			config.TCP = tcpOptions
+		}
+
+		if strings.HasPrefix(config.URL, "udp://") {
+			udpSize, err := parseByteSize(*udpSizeFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			udpOptions, err := newUDPOptions(*udpPayloadFlag, udpSize, *udpRateFlag, *udpReplyFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			config.UDP = udpOptions
+		}
+
+		if strings.HasPrefix(config.URL, "mqtt://") || strings.HasPrefix(config.URL, "mqtts://") {
+			mqttOptions, err := newMQTTOptions(*mqttTopicFlag, *mqttQoSFlag, *mqttRateFlag, *mqttSubscribeFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			config.MQTT = mqttOptions
+		}
+
+		if strings.HasPrefix(config.URL, "redis://") || strings.HasPrefix(config.URL, "rediss://") {
+			redisOptions, err := newRedisOptions(config.URL, redisCommandFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			config.Redis = redisOptions
+		}
+
+		if *dnsQueryFlag != "" {
+			dnsQuery, err := newDNSQueryOptions(*dnsQueryFlag, *dnsTypeFlag, config.DNSServer)
+			if err != nil {
+				return Config{}, err
+			}
+			config.DNSQuery = dnsQuery
+		}
+
+		if *sseFlag {
+			if *sseDurationFlag <= 0 {
+				return Config{}, errors.New("-sse-duration must be greater than zero")
+			}
+			config.SSE = &SSEOptions{Duration: *sseDurationFlag}
+		}
+
+		if *cacheValidateFlag {
+			config.CacheValidators = newValidatorStore()
+		}
+
+		if *rangeModeFlag != "" {
+			rangeSize, err := parseByteSize(*rangeSizeFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			rangeTotal, err := parseByteSize(*rangeTotalFlag)
+			if err != nil {
+				return Config{}, err
+			}
+			config.Range = &RangeOptions{
+				Mode:   *rangeModeFlag,
+				Size:   rangeSize,
+				Offset: *rangeOffsetFlag,
+				Total:  rangeTotal,
+			}
+			if err := validateRangeOptions(config.Range); err != nil {
+				return Config{}, err
+			}
+		}
+
+		localAddrs, err := parseLocalAddrs(*localAddrFlag, config.IPFamily)
+		if err != nil {
+			return Config{}, err
+		}
+		config.LocalAddrs = localAddrs
+
+		form, err := parseFormFields(formFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Form = form
+		config.BodyFile = *bodyFileFlag
+		config.BodySize, err = parseByteSize(*bodySizeFlag)
+		if err != nil {
+			return Config{}, err
+		}
+
+		chunkSize, err := parseByteSize(*chunkSizeFlag)
+		if err != nil || chunkSize <= 0 {
+			return Config{}, errors.New("invalid -chunk-size")
+		}
+		config.Chunked = *chunkedFlag
+		config.ChunkSize = int(chunkSize)
+		config.ChunkDelay = *chunkDelayFlag
+		config.CompressBody = *compressBodyFlag
+
+		if *bodyTemplateFlag != "" {
+			config.BodyTemplate, err = loadTemplateFile(*bodyTemplateFlag)
+		} else {
+			config.BodyTemplate, err = parseTemplate("body", config.Body)
+		}
+		if err != nil {
+			return Config{}, err
+		}
+		config.URLTemplate, err = parseTemplate("url", config.URL)
+		if err != nil {
+			return Config{}, err
+		}
+		config.URLGlob, err = parseURLGlob(config.URL)
+		if err != nil {
+			return Config{}, err
+		}
+		if config.URLGlob != nil {
+			slog.Info("URL pattern expanded", "urls", config.URLGlob.size())
+		}
+		config.HeaderTemplates, err = parseHeaderTemplates(config.Headers)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Query, err = parseQueryParams(queryFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.HeaderRotations, err = parseHeaderRotations(headerFileFlag)
+		if err != nil {
+			return Config{}, err
+		}
+
+		trailers, err := parseTrailers(trailerFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Trailers = trailers
+
+		bodySources := 0
+		for _, enabled := range []bool{config.Body != "", *bodyTemplateFlag != "", len(form) > 0, config.BodyFile != "", config.BodySize > 0, *bodySizesFlag != ""} {
+			if enabled {
+				bodySources++
+			}
+		}
+		if bodySources > 1 {
+			return Config{}, errors.New("-body, -body-template, -form, -body-file, -body-size and -body-sizes are mutually exclusive")
+		}
+
+		if *expectContinueFlag {
+			if bodySources == 0 {
+				return Config{}, errors.New("-expect-continue requires a request body")
+			}
+			if *continueTimeoutFlag <= 0 {
+				return Config{}, errors.New("-continue-timeout must be greater than zero")
+			}
+		}
+		config.ExpectContinue = *expectContinueFlag
+		config.ContinueTimeout = *continueTimeoutFlag
+
+		basicAuth, err := parseBasicAuth(*basicAuthFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.BasicAuth = basicAuth
+
+		bearerToken, err := resolveSecret(*bearerTokenFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.BearerToken = bearerToken
+
+		tokenSource, err := newOAuth2TokenSource(OAuth2Options{
+			TokenURL:     *oauth2TokenURLFlag,
+			ClientID:     *oauth2ClientIDFlag,
+			ClientSecret: *oauth2ClientSecretFlag,
+			Scopes:       *oauth2ScopesFlag,
+		})
+		if err != nil {
+			return Config{}, err
+		}
+		config.OAuth2 = tokenSource
+
+		ntlmAuth, err := parseNTLMAuth(*ntlmFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.NTLM = ntlmAuth
+
+		kerberosAuth, err := newKerberosAuth(KerberosOptions{
+			Principal:  *kerberosPrincipalFlag,
+			Password:   *kerberosPasswordFlag,
+			Keytab:     *kerberosKeytabFlag,
+			ConfigFile: *krb5ConfFlag,
+			SPN:        *kerberosSPNFlag,
+		})
+		if err != nil {
+			return Config{}, err
+		}
+		config.Kerberos = kerberosAuth
+
+		jwtMinter, err := newJWTMinter(JWTOptions{
+			Key:       *jwtKeyFlag,
+			Algorithm: *jwtAlgFlag,
+			Claims:    *jwtClaimsFlag,
+			TTL:       *jwtTTLFlag,
+		})
+		if err != nil {
+			return Config{}, err
+		}
+		config.JWT = jwtMinter
+
+		if *loginURLFlag != "" {
+			config.Login = &LoginOptions{
+				URL:         *loginURLFlag,
+				Method:      *loginMethodFlag,
+				Body:        *loginBodyFlag,
+				Headers:     parseHeaders(*loginHeadersFlag),
+				Extract:     *loginExtractFlag,
+				TokenHeader: *loginTokenHeaderFlag,
+			}
+			if err := validateLoginOptions(config.Login); err != nil {
+				return Config{}, err
+			}
+		}
+
+		authMethods := 0
+		for _, enabled := range []bool{basicAuth != nil, bearerToken != "", tokenSource != nil, ntlmAuth != nil, kerberosAuth != nil, jwtMinter != nil, config.Login != nil} {
+			if enabled {
+				authMethods++
+			}
+		}
+		if authMethods > 1 {
+			return Config{}, errors.New("only one authentication method can be used at a time")
+		}
+
+		throttle, err := parseBandwidth(*throttleFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Throttle = throttle
+
+		resolve, err := parseResolve(resolveFlag)
+		if err != nil {
+			return Config{}, err
+		}
+		config.Resolve = resolve
+
+		tlsConfig, err := newTLSConfig(TLSOptions{
+			CertFile: *certFlag,
+			KeyFile:  *keyFlag,
+			KeyPass:  *keyPassFlag,
+			CAFile:   *caCertFlag,
+			Insecure: *insecureFlag,
+			MinVer:   *tlsMinFlag,
+			MaxVer:   *tlsMaxFlag,
+			Ciphers:  *ciphersFlag,
+		})
+		if err != nil {
+			return Config{}, err
+		}
+		config.TLSConfig = tlsConfig
+
+		if *insecureFlag {
+			slog.Warn("TLS certificate verification is disabled (-insecure); the server identity is not checked, use this only against trusted test environments")
+		}
+
+		if *bodySizesFlag != "" {
+			if config.BodySizes, err = parseByteSizes(*bodySizesFlag); err != nil {
+				return Config{}, err
+			}
+		}
+
+		if _, err := ParseStatusSet(*successStatusFlag); err != nil {
+			return Config{}, fmt.Errorf("-success-status: %w", err)
+		}
+		config.SuccessStatus = *successStatusFlag
+		if len(expectStatusFlag) > 0 {
+			spec := strings.Join(expectStatusFlag, ",")
+			if config.ExpectStatus, err = ParseStatusSet(spec); err != nil {
+				return Config{}, fmt.Errorf("-expect-status: %w", err)
+			}
+			config.SuccessStatus = spec
+		}
+
+		if *errorRulesFlag != "" {
+			if config.ErrorRules, err = LoadErrorRules(*errorRulesFlag); err != nil {
+				return Config{}, err
+			}
+		}
+
+		return config, nil
+	}
+}
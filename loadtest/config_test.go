@@ -0,0 +1,45 @@
+package loadtest
+
+import (
+	"flag"
+	"strings"
+	"testing"
+)
+
+func parseFlags(t *testing.T, args ...string) (Config, error) {
+	t.Helper()
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	build := Flags(fs)
+	if err := fs.Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return build()
+}
+
+// Concorrência ou -requests abaixo de 1 deixariam o pool sem workers, e o
+// teste nunca terminaria.
+func TestFlagsRejectInvalidLoad(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-concurrency", "0"}, "invalid -concurrency 0"},
+		{[]string{"-concurrency=-1"}, "invalid -concurrency -1"},
+		{[]string{"-requests", "-5"}, "invalid -requests -5"},
+	}
+	for _, tt := range tests {
+		args := append([]string{"-url", "http://example.com", "-requests", "10"}, tt.args...)
+		_, err := parseFlags(t, args...)
+		if err == nil || !strings.Contains(err.Error(), tt.want) {
+			t.Errorf("%v: error = %v, want %q", tt.args, err, tt.want)
+		}
+	}
+
+	config, err := parseFlags(t, "-url", "http://example.com", "-requests", "1", "-concurrency", "1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config.Requests != 1 || config.Concurrency != 1 {
+		t.Errorf("Requests, Concurrency = %d, %d, want 1, 1", config.Requests, config.Concurrency)
+	}
+}
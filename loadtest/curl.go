@@ -0,0 +1,279 @@
+package loadtest
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// curlCommand é o resultado da conversão de um comando curl ("Copy as
+// cURL" do DevTools). Opções de TLS e protocolo valem para o teste todo.
+type curlCommand struct {
+	Target   Target
+	Insecure bool
+	HTTP2    bool
+}
+
+// Opções do curl sem efeito no teste de carga, com ou sem valor.
+var (
+	curlIgnoredFlags = map[string]bool{
+		"-s": true, "--silent": true, "-S": true, "--show-error": true, "-v": true, "--verbose": true,
+		"-L": true, "--location": true, "-i": true, "--include": true, "--compressed": true,
+		"-f": true, "--fail": true, "-g": true, "--globoff": true, "-N": true, "--no-buffer": true,
+		"--http1.1": true, "-#": true, "--progress-bar": true,
+	}
+	curlIgnoredValueFlags = map[string]bool{
+		"-o": true, "--output": true, "-w": true, "--write-out": true, "-m": true, "--max-time": true,
+		"--connect-timeout": true, "--retry": true, "--max-redirs": true,
+	}
+)
+
+func parseCurlCommand(command string) (*curlCommand, error) {
+	args, err := splitShellWords(command)
+	if err != nil {
+		return nil, err
+	}
+	if len(args) > 0 && (args[0] == "curl" || strings.HasSuffix(args[0], "/curl")) {
+		args = args[1:]
+	}
+
+	cmd := &curlCommand{}
+	headers := make(map[string]string)
+	var data []string
+	var method, rawURL string
+	var getData, head bool
+
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		// --opcao=valor
+		if strings.HasPrefix(arg, "--") {
+			if name, value, ok := strings.Cut(arg, "="); ok {
+				arg = name
+				args = append(args[:i+1], append([]string{value}, args[i+1:]...)...)
+			}
+		}
+		value := func() (string, error) {
+			if i+1 >= len(args) {
+				return "", fmt.Errorf("curl option %s requires a value", arg)
+			}
+			i++
+			return args[i], nil
+		}
+
+		switch {
+		case !strings.HasPrefix(arg, "-") || arg == "-":
+			if rawURL != "" {
+				return nil, fmt.Errorf("curl command has more than one URL")
+			}
+			rawURL = arg
+		case arg == "--url":
+			if rawURL, err = value(); err != nil {
+				return nil, err
+			}
+		case arg == "-X" || arg == "--request":
+			if method, err = value(); err != nil {
+				return nil, err
+			}
+		case arg == "-H" || arg == "--header":
+			h, err := value()
+			if err != nil {
+				return nil, err
+			}
+			name, v, ok := strings.Cut(h, ":")
+			if !ok {
+				return nil, fmt.Errorf("invalid curl header %q", h)
+			}
+			headers[strings.TrimSpace(name)] = strings.TrimSpace(v)
+		case arg == "-d" || arg == "--data" || arg == "--data-raw" || arg == "--data-binary" || arg == "--data-ascii":
+			d, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if strings.HasPrefix(d, "@") && arg != "--data-raw" {
+				content, err := os.ReadFile(d[1:])
+				if err != nil {
+					return nil, fmt.Errorf("reading curl data file: %w", err)
+				}
+				d = string(content)
+				if arg != "--data-binary" {
+					d = strings.NewReplacer("\r", "", "\n", "").Replace(d)
+				}
+			}
+			data = append(data, d)
+		case arg == "--data-urlencode":
+			d, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if name, v, ok := strings.Cut(d, "="); ok {
+				d = url.QueryEscape(name) + "=" + url.QueryEscape(v)
+				if name == "" {
+					d = url.QueryEscape(v)
+				}
+			} else {
+				d = url.QueryEscape(d)
+			}
+			data = append(data, d)
+		case arg == "-u" || arg == "--user":
+			credentials, err := value()
+			if err != nil {
+				return nil, err
+			}
+			headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+		case arg == "-A" || arg == "--user-agent":
+			if headers["User-Agent"], err = value(); err != nil {
+				return nil, err
+			}
+		case arg == "-e" || arg == "--referer":
+			if headers["Referer"], err = value(); err != nil {
+				return nil, err
+			}
+		case arg == "-b" || arg == "--cookie":
+			cookie, err := value()
+			if err != nil {
+				return nil, err
+			}
+			if !strings.Contains(cookie, "=") {
+				return nil, fmt.Errorf("curl cookie jar files are not supported (%s)", cookie)
+			}
+			headers["Cookie"] = cookie
+		case arg == "-G" || arg == "--get":
+			getData = true
+		case arg == "-I" || arg == "--head":
+			head = true
+		case arg == "-k" || arg == "--insecure":
+			cmd.Insecure = true
+		case arg == "--http2" || arg == "--http2-prior-knowledge":
+			cmd.HTTP2 = true
+		case arg == "-F" || arg == "--form":
+			return nil, fmt.Errorf("curl -F is not supported, use -form instead")
+		case curlIgnoredFlags[arg]:
+		case curlIgnoredValueFlags[arg]:
+			if _, err := value(); err != nil {
+				return nil, err
+			}
+		default:
+			return nil, fmt.Errorf("unsupported curl option %s", arg)
+		}
+	}
+
+	if rawURL == "" {
+		return nil, fmt.Errorf("curl command has no URL")
+	}
+	if !strings.Contains(rawURL, "://") {
+		rawURL = "http://" + rawURL
+	}
+
+	body := strings.Join(data, "&")
+	switch {
+	case getData && body != "":
+		// -G envia os dados na query string
+		sep := "?"
+		if strings.Contains(rawURL, "?") {
+			sep = "&"
+		}
+		rawURL += sep + body
+		body = ""
+	case body != "" && !hasHeader(headers, "Content-Type"):
+		headers["Content-Type"] = "application/x-www-form-urlencoded"
+	}
+	if method == "" {
+		switch {
+		case head:
+			method = http.MethodHead
+		case body != "":
+			method = http.MethodPost
+		default:
+			method = http.MethodGet
+		}
+	}
+
+	cmd.Target = Target{Name: method + " " + rawURL, Method: method, URL: rawURL, Body: body}
+	if len(headers) > 0 {
+		cmd.Target.Headers = headers
+	}
+	return cmd, nil
+}
+
+// splitShellWords separa os argumentos como um shell POSIX: aspas simples
+// e duplas, barra invertida, continuação de linha e $'...' do bash.
+func splitShellWords(s string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s):
+			i++
+			if s[i] != '\n' {
+				word.WriteByte(s[i])
+				inWord = true
+			}
+		case c == '\'':
+			end := strings.IndexByte(s[i+1:], '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated quote in curl command")
+			}
+			word.WriteString(s[i+1 : i+1+end])
+			i += end + 1
+			inWord = true
+		case c == '$' && i+1 < len(s) && s[i+1] == '\'':
+			// ANSI-C quoting usado pelo "Copy as cURL (bash)" do Chrome
+			i += 2
+			for ; i < len(s) && s[i] != '\''; i++ {
+				if s[i] == '\\' && i+1 < len(s) {
+					i++
+					switch s[i] {
+					case 'n':
+						word.WriteByte('\n')
+					case 't':
+						word.WriteByte('\t')
+					case 'r':
+						word.WriteByte('\r')
+					default:
+						word.WriteByte(s[i])
+					}
+					continue
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated quote in curl command")
+			}
+			inWord = true
+		case c == '"':
+			i++
+			for ; i < len(s) && s[i] != '"'; i++ {
+				if s[i] == '\\' && i+1 < len(s) && strings.IndexByte("\"\\$`\n", s[i+1]) >= 0 {
+					i++
+					if s[i] == '\n' {
+						continue
+					}
+				}
+				word.WriteByte(s[i])
+			}
+			if i >= len(s) {
+				return nil, fmt.Errorf("unterminated quote in curl command")
+			}
+			inWord = true
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		default:
+			word.WriteByte(c)
+			inWord = true
+		}
+	}
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
@@ -0,0 +1,129 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"time"
+)
+
+// debugBodyLimit é o tanto do corpo da resposta guardado em Exchange. Do
+// corpo enviado são guardados previewBodyLimit bytes, como no DryRun.
+const debugBodyLimit = 64 << 10
+
+// Exchange é a requisição enviada por DebugRequest e a resposta recebida.
+type Exchange struct {
+	Request    Preview
+	Proto      string
+	Status     string
+	Headers    http.Header
+	Trailers   http.Header
+	Body       []byte // já descomprimido
+	BodySize   int64
+	WireBytes  int64 // bytes recebidos na conexão, antes da descompressão
+	TLSVersion string
+	RemoteAddr string
+	Redirects  int
+	Duration   time.Duration
+	Phases     PhaseTimings
+}
+
+// DebugRequest envia uma única requisição HTTP, montada como a primeira do
+// teste, e guarda tudo o que foi enviado e recebido.
+func DebugRequest(ctx context.Context, config Config) (*Exchange, error) {
+	if configMode(config) != "" {
+		return nil, errors.New("-debug-request only supports HTTP targets")
+	}
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return nil, err
+	}
+	defer client.CloseIdleConnections()
+
+	vars := config.templateVars(1, 1)
+	if len(config.Targets) > 0 {
+		config = config.Targets[0].apply(config)
+	}
+	req, sent, _, err := buildRequest(config, vars)
+	if err != nil {
+		return nil, err
+	}
+	if config.Login != nil {
+		vu := &virtualUser{ID: 1}
+		if err := vu.ensureSession(client, config.Login); err != nil {
+			return nil, err
+		}
+		config.Login.apply(req, vu.token)
+	}
+	if config.Range != nil {
+		start, end := config.Range.window(vars)
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", start, end))
+	}
+
+	// Guardar o corpo enquanto ele é enviado, inclusive em streaming
+	sentBody := &limitedBuffer{limit: previewBodyLimit}
+	req.Body = struct {
+		io.Reader
+		io.Closer
+	}{io.TeeReader(req.Body, sentBody), req.Body}
+
+	exchange := &Exchange{Request: Preview{
+		Method:  req.Method,
+		URL:     req.URL.String(),
+		Headers: req.Header,
+	}}
+	if req.Host != "" && req.Host != req.URL.Host {
+		exchange.Request.Host = req.Host
+	}
+
+	tracer := &requestTracer{}
+	traceCtx, redirects := withRedirectCounter(ctx)
+	req = req.WithContext(httptrace.WithClientTrace(traceCtx, tracer.clientTrace()))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	exchange.Request.Body = sentBody.Bytes()
+	exchange.Request.BodySize = sent.n
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	received := &limitedBuffer{limit: debugBodyLimit}
+	exchange.WireBytes, exchange.BodySize, _, err = readBody(resp, received)
+	exchange.Duration = time.Since(start)
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	exchange.Proto = resp.Proto
+	exchange.Status = resp.Status
+	exchange.Headers = resp.Header
+	exchange.Trailers = resp.Trailer
+	exchange.Body = received.Bytes()
+	exchange.RemoteAddr = tracer.RemoteAddr()
+	exchange.Redirects = *redirects
+	exchange.Phases = tracer.finish()
+	if resp.TLS != nil {
+		exchange.TLSVersion = tls.VersionName(resp.TLS.Version)
+	}
+	return exchange, nil
+}
+
+// limitedBuffer guarda só os primeiros limit bytes, mas aceita todas as
+// escritas para não interromper a cópia.
+type limitedBuffer struct {
+	bytes.Buffer
+	limit int
+}
+
+func (b *limitedBuffer) Write(p []byte) (int, error) {
+	if room := b.limit - b.Len(); room > 0 {
+		b.Buffer.Write(p[:min(len(p), room)])
+	}
+	return len(p), nil
+}
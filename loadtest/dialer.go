@@ -0,0 +1,204 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+type dialFunc func(ctx context.Context, network, addr string) (net.Conn, error)
+
+func newDialContext(config Config) dialFunc {
+	dial := newBaseDialContext(config)
+	if config.Throttle <= 0 {
+		return dial
+	}
+
+	// Simular clientes com banda limitada
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		conn, err := dial(ctx, network, addr)
+		if err != nil {
+			return nil, err
+		}
+		return newThrottledConn(conn, config.Throttle), nil
+	}
+}
+
+func newBaseDialContext(config Config) dialFunc {
+	dialer := &net.Dialer{
+		Timeout:   config.ConnectTimeout,
+		KeepAlive: 30 * time.Second,
+	}
+	if config.DNSServer != "" {
+		dialer.Resolver = newResolver(config.DNSServer)
+	}
+	// Rotacionar os IPs de origem entre as conexões
+	var nextLocal atomic.Uint64
+	localDialer := func() *net.Dialer {
+		if len(config.LocalAddrs) == 0 {
+			return dialer
+		}
+		d := *dialer
+		i := nextLocal.Add(1) - 1
+		d.LocalAddr = &net.TCPAddr{IP: config.LocalAddrs[i%uint64(len(config.LocalAddrs))]}
+		return &d
+	}
+
+	var cache *dnsCache
+	if !config.NoDNSCache {
+		cache = newDNSCache(dialer.Resolver, config.IPFamily, config.DNSCacheTTL)
+		prewarmDNS(cache, config.URL)
+	}
+
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		// Socket Unix: a URL fornece apenas o path e o Host
+		if config.UnixSocket != "" {
+			var unixDialer net.Dialer
+			return unixDialer.DialContext(ctx, "unix", config.UnixSocket)
+		}
+		// Restringir a família de endereços (tcp4/tcp6)
+		if config.IPFamily != "" {
+			network += config.IPFamily
+		}
+		// Conectar a um backend específico mantendo Host/SNI da URL
+		if config.ConnectTo != "" {
+			addr = config.ConnectTo
+		} else if pinned, ok := config.Resolve[addr]; ok {
+			addr = pinned
+		}
+		d := localDialer()
+		if cache != nil {
+			return cache.dial(ctx, d, network, addr)
+		}
+		return d.DialContext(ctx, network, addr)
+	}
+}
+
+// prewarmDNS resolve o host alvo antes do início do teste. Falhas são
+// ignoradas aqui e reaparecem como erros nas próprias requisições.
+func prewarmDNS(cache *dnsCache, rawURL string) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Hostname() == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	cache.lookup(ctx, u.Hostname())
+}
+
+// parseResolve converte entradas no formato do curl (host:port:addr) em um
+// mapa "host:port" -> "addr:port".
+func parseResolve(entries []string) (map[string]string, error) {
+	resolve := make(map[string]string)
+	for _, entry := range entries {
+		parts := strings.SplitN(entry, ":", 3)
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" || parts[2] == "" {
+			return nil, fmt.Errorf("invalid -resolve entry %q (use host:port:addr)", entry)
+		}
+		host, port := parts[0], parts[1]
+		addr := strings.TrimSuffix(strings.TrimPrefix(parts[2], "["), "]")
+		if net.ParseIP(addr) == nil {
+			return nil, fmt.Errorf("invalid -resolve address %q", parts[2])
+		}
+		resolve[net.JoinHostPort(host, port)] = net.JoinHostPort(addr, port)
+	}
+	return resolve, nil
+}
+
+// newResolver direciona todas as consultas DNS para o servidor informado,
+// ignorando a configuração do sistema.
+func newResolver(server string) *net.Resolver {
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// remoteIP identifica o IP de destino de um resultado, inclusive quando a
+// conexão falhou antes de ser estabelecida.
+func remoteIP(result Result) string {
+	addr := result.RemoteAddr
+	var opErr *net.OpError
+	if addr == "" && errors.As(result.Error, &opErr) && opErr.Addr != nil {
+		addr = opErr.Addr.String()
+	}
+	if host, _, err := net.SplitHostPort(addr); err == nil {
+		addr = host
+	}
+	if net.ParseIP(addr) == nil {
+		return ""
+	}
+	return addr
+}
+
+func addressFamily(addr string) string {
+	host, _, err := net.SplitHostPort(addr)
+	if err != nil {
+		host = addr
+	}
+	ip := net.ParseIP(host)
+	switch {
+	case ip == nil:
+		return ""
+	case ip.To4() != nil:
+		return "IPv4"
+	default:
+		return "IPv6"
+	}
+}
+
+// parseLocalAddrs aceita uma lista separada por vírgulas de IPs ou nomes
+// de interfaces de rede.
+func parseLocalAddrs(list, family string) ([]net.IP, error) {
+	if list == "" {
+		return nil, nil
+	}
+	var ips []net.IP
+	for _, value := range strings.Split(list, ",") {
+		ip, err := parseLocalAddr(strings.TrimSpace(value), family)
+		if err != nil {
+			return nil, err
+		}
+		ips = append(ips, ip)
+	}
+	return ips, nil
+}
+
+// parseLocalAddr aceita um IP ou o nome de uma interface de rede. Para
+// interfaces, usa o primeiro endereço compatível com a família escolhida.
+func parseLocalAddr(value, family string) (net.IP, error) {
+	if ip := net.ParseIP(value); ip != nil {
+		return ip, nil
+	}
+
+	iface, err := net.InterfaceByName(value)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -local-addr %q: not an IP or interface name", value)
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, fmt.Errorf("reading addresses of %s: %w", value, err)
+	}
+	for _, addr := range addrs {
+		ipNet, ok := addr.(*net.IPNet)
+		if !ok || ipNet.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		isV4 := ipNet.IP.To4() != nil
+		if family == "" || (family == "4" && isV4) || (family == "6" && !isV4) {
+			return ipNet.IP, nil
+		}
+	}
+	return nil, fmt.Errorf("interface %s has no usable address", value)
+}
@@ -0,0 +1,90 @@
+package loadtest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+type dnsEntry struct {
+	addrs   []string
+	expires time.Time
+}
+
+// dnsCache compartilha a resolução de nomes entre todos os workers para que
+// a latência do resolvedor não se misture à latência do alvo.
+// Com ttl igual a zero cada host é resolvido apenas uma vez por execução.
+type dnsCache struct {
+	resolver *net.Resolver
+	network  string // "ip", "ip4" ou "ip6"
+	ttl      time.Duration
+	mu       sync.Mutex
+	entries  map[string]dnsEntry
+	next     map[string]int
+}
+
+func newDNSCache(resolver *net.Resolver, family string, ttl time.Duration) *dnsCache {
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &dnsCache{
+		resolver: resolver,
+		network:  "ip" + family,
+		ttl:      ttl,
+		entries:  make(map[string]dnsEntry),
+		next:     make(map[string]int),
+	}
+}
+
+func (c *dnsCache) lookup(ctx context.Context, host string) ([]string, error) {
+	c.mu.Lock()
+	entry, ok := c.entries[host]
+	c.mu.Unlock()
+	if ok && (c.ttl == 0 || time.Now().Before(entry.expires)) {
+		return entry.addrs, nil
+	}
+
+	ips, err := c.resolver.LookupIP(ctx, c.network, host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, 0, len(ips))
+	for _, ip := range ips {
+		addrs = append(addrs, ip.String())
+	}
+
+	c.mu.Lock()
+	c.entries[host] = dnsEntry{addrs: addrs, expires: time.Now().Add(c.ttl)}
+	c.mu.Unlock()
+	return addrs, nil
+}
+
+// pick escolhe o próximo endereço em round-robin, distribuindo as conexões
+// entre todos os registros A/AAAA.
+func (c *dnsCache) pick(host string, addrs []string) string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	addr := addrs[c.next[host]%len(addrs)]
+	c.next[host]++
+	return addr
+}
+
+// dial resolve o host pelo cache e conecta ao próximo endereço da rotação.
+// Não há fallback para os demais IPs: uma falha é atribuída ao nó escolhido.
+func (c *dnsCache) dial(ctx context.Context, dialer *net.Dialer, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil || net.ParseIP(host) != nil {
+		return dialer.DialContext(ctx, network, addr)
+	}
+
+	addrs, err := c.lookup(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	if len(addrs) == 0 {
+		return nil, &net.DNSError{Err: "no addresses found", Name: host, IsNotFound: true}
+	}
+
+	return dialer.DialContext(ctx, network, net.JoinHostPort(c.pick(host, addrs), port))
+}
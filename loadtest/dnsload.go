@@ -0,0 +1,223 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strings"
+	"text/template"
+	"time"
+
+	"golang.org/x/net/dns/dnsmessage"
+)
+
+// DNSQueryOptions configura o modo DNS: cada requisição é uma consulta
+// enviada diretamente ao resolvedor (-dns-server ou o do sistema).
+type DNSQueryOptions struct {
+	Name   string
+	Type   dnsmessage.Type
+	Server string // ip:porta
+	name   *template.Template
+}
+
+var dnsTypes = map[string]dnsmessage.Type{
+	"A":     dnsmessage.TypeA,
+	"AAAA":  dnsmessage.TypeAAAA,
+	"CNAME": dnsmessage.TypeCNAME,
+	"MX":    dnsmessage.TypeMX,
+	"NS":    dnsmessage.TypeNS,
+	"PTR":   dnsmessage.TypePTR,
+	"SOA":   dnsmessage.TypeSOA,
+	"SRV":   dnsmessage.TypeSRV,
+	"TXT":   dnsmessage.TypeTXT,
+	"ANY":   dnsmessage.TypeALL,
+}
+
+func newDNSQueryOptions(name, qtype, server string) (*DNSQueryOptions, error) {
+	t, ok := dnsTypes[strings.ToUpper(qtype)]
+	if !ok {
+		return nil, fmt.Errorf("invalid -dns-type %q (use A, AAAA, CNAME, MX, NS, PTR, SOA, SRV, TXT or ANY)", qtype)
+	}
+	if server == "" {
+		var err error
+		if server, err = systemNameserver(); err != nil {
+			return nil, err
+		}
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(server, "53")
+	}
+	// O nome aceita templates, ex.: {{seq}}.example.com para evitar o cache
+	tmpl, err := parseTemplate("dns-query", name)
+	if err != nil {
+		return nil, err
+	}
+	return &DNSQueryOptions{Name: name, Type: t, Server: server, name: tmpl}, nil
+}
+
+// systemNameserver lê o primeiro nameserver de /etc/resolv.conf.
+func systemNameserver() (string, error) {
+	data, err := os.ReadFile("/etc/resolv.conf")
+	if err != nil {
+		return "", fmt.Errorf("no -dns-server given and reading /etc/resolv.conf failed: %w", err)
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		fields := strings.Fields(line)
+		if len(fields) >= 2 && fields[0] == "nameserver" {
+			return fields[1], nil
+		}
+	}
+	return "", fmt.Errorf("no nameserver found in /etc/resolv.conf; use -dns-server")
+}
+
+type DNSStats struct {
+	Queries   int
+	Timeouts  int
+	Truncated int
+	RCodes    map[string]int // NOERROR, NXDOMAIN, SERVFAIL...
+}
+
+var errDNSTimeout = errors.New("DNS query timed out")
+
+type dnsResult struct {
+	rcode     string
+	truncated bool
+	timeout   bool
+}
+
+func (s *DNSStats) add(result Result) {
+	if result.DNS == nil {
+		return
+	}
+	if s.RCodes == nil {
+		s.RCodes = make(map[string]int)
+	}
+	s.Queries++
+	if result.DNS.timeout {
+		s.Timeouts++
+	}
+	if result.DNS.truncated {
+		s.Truncated++
+	}
+	if result.DNS.rcode != "" {
+		s.RCodes[result.DNS.rcode]++
+	}
+}
+
+func executeDNSLoadTest(config Config) Report {
+	report := runRequests(config, func(vars templateVars, _ *virtualUser, results chan<- Result) {
+		results <- makeDNSQuery(config, vars)
+	})
+	report.Mode = "dns"
+	return report
+}
+
+func makeDNSQuery(config Config, vars templateVars) Result {
+	result := Result{DNS: &dnsResult{}, RemoteAddr: config.DNSQuery.Server}
+	fail := func(err error) Result {
+		result.Error = err
+		result.ErrorKind = ClassifyError(err)
+		return result
+	}
+
+	name := config.DNSQuery.Name
+	if config.DNSQuery.name != nil {
+		rendered, err := renderTemplate(config.DNSQuery.name, vars)
+		if err != nil {
+			return fail(err)
+		}
+		name = rendered
+	}
+	if !strings.HasSuffix(name, ".") {
+		name += "."
+	}
+	qname, err := dnsmessage.NewName(name)
+	if err != nil {
+		return fail(fmt.Errorf("invalid query name %q: %w", name, err))
+	}
+
+	id := uint16(vars.random().IntN(1 << 16))
+	query := dnsmessage.Message{
+		Header:    dnsmessage.Header{ID: id, RecursionDesired: true},
+		Questions: []dnsmessage.Question{{Name: qname, Type: config.DNSQuery.Type, Class: dnsmessage.ClassINET}},
+	}
+	packet, err := query.Pack()
+	if err != nil {
+		return fail(err)
+	}
+
+	ctx, cancel := context.WithTimeout(config.context(), config.Timeout)
+	defer cancel()
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", config.DNSQuery.Server)
+	if err != nil {
+		return fail(err)
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	start := time.Now()
+	if _, err := conn.Write(packet); err != nil {
+		return fail(err)
+	}
+	result.BytesSent = int64(len(packet))
+
+	buf := make([]byte, 4096)
+	var reply dnsmessage.Message
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() {
+				// Mensagem fixa: a porta de origem mudaria a cada consulta
+				result.DNS.timeout = true
+				return fail(errDNSTimeout)
+			}
+			return fail(err)
+		}
+		if err := reply.Unpack(buf[:n]); err != nil {
+			return fail(fmt.Errorf("decoding DNS response: %w", err))
+		}
+		result.BytesRead = int64(n)
+		// Ignorar respostas atrasadas de outras consultas
+		if reply.Header.ID == id {
+			break
+		}
+	}
+	result.Duration = time.Since(start)
+
+	result.DNS.rcode = dnsRCodeName(reply.Header.RCode)
+	result.DNS.truncated = reply.Header.Truncated
+	// NXDOMAIN é uma resposta válida; falhas do servidor contam como erro
+	switch reply.Header.RCode {
+	case dnsmessage.RCodeSuccess, dnsmessage.RCodeNameError:
+	default:
+		result.Error = fmt.Errorf("DNS %s", result.DNS.rcode)
+		result.StatusCode = 502
+	}
+	return result
+}
+
+// dnsRCodeName usa os mnemônicos da RFC 1035 exibidos por dig e afins.
+func dnsRCodeName(rcode dnsmessage.RCode) string {
+	switch rcode {
+	case dnsmessage.RCodeSuccess:
+		return "NOERROR"
+	case dnsmessage.RCodeFormatError:
+		return "FORMERR"
+	case dnsmessage.RCodeServerFailure:
+		return "SERVFAIL"
+	case dnsmessage.RCodeNameError:
+		return "NXDOMAIN"
+	case dnsmessage.RCodeNotImplemented:
+		return "NOTIMP"
+	case dnsmessage.RCodeRefused:
+		return "REFUSED"
+	default:
+		return fmt.Sprintf("RCODE%d", rcode)
+	}
+}
@@ -0,0 +1,195 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// previewBodyLimit é o tanto do corpo guardado em Preview.Body.
+const previewBodyLimit = 4 << 10
+
+// Preview é a primeira requisição do teste, montada sem ser enviada.
+type Preview struct {
+	Mode     string // como Report.Mode
+	Method   string // método HTTP, método gRPC, comando Redis...
+	URL      string
+	Host     string // Host enviado quando difere do da URL
+	Headers  http.Header
+	Body     []byte
+	BodySize int64    // tamanho total do corpo, -1 quando desconhecido
+	Target   string   // host:porta ao qual a conexão seria feita
+	Addrs    []string // endereços resolvidos para Target
+}
+
+// DryRun monta a primeira requisição do teste e resolve o endereço de
+// destino sem enviar carga. Tokens OAuth2 e Kerberos são obtidos, o que
+// também confere as credenciais; o login do -login-url não é feito.
+func DryRun(ctx context.Context, config Config) (*Preview, error) {
+	vars := config.templateVars(1, 1)
+	preview := &Preview{Mode: configMode(config), URL: config.URL, BodySize: -1}
+	var err error
+
+	switch preview.Mode {
+	case "":
+		if len(config.Targets) > 0 {
+			config = config.Targets[0].apply(config)
+		}
+		req, _, _, err := buildRequest(config, vars)
+		if err != nil {
+			return nil, err
+		}
+		defer req.Body.Close()
+		preview.Method = req.Method
+		preview.URL = req.URL.String()
+		preview.Headers = req.Header
+		if req.Host != "" && req.Host != req.URL.Host {
+			preview.Host = req.Host
+		}
+		preview.BodySize = req.ContentLength
+		if preview.Body, err = io.ReadAll(io.LimitReader(req.Body, previewBodyLimit)); err != nil {
+			return nil, fmt.Errorf("reading request body: %w", err)
+		}
+	case "grpc":
+		preview.Method = config.GRPC.Call
+		tmpl, err := parseTemplate("data", config.GRPC.Data)
+		if err != nil {
+			return nil, err
+		}
+		data, err := renderTemplate(tmpl, vars)
+		if err != nil {
+			return nil, err
+		}
+		preview.Body = []byte(data)
+	case "tcp":
+		preview.Body = config.TCP.Payload
+	case "udp":
+		preview.Body = config.UDP.Payload
+	case "dns":
+		preview.Method = strings.TrimPrefix(config.DNSQuery.Type.String(), "Type")
+		preview.URL = config.DNSQuery.Name
+		if config.DNSQuery.name != nil {
+			if preview.URL, err = renderTemplate(config.DNSQuery.name, vars); err != nil {
+				return nil, err
+			}
+		}
+	case "mqtt":
+		preview.Method = "PUBLISH " + config.MQTT.Topic
+		if config.MQTT.topic != nil {
+			topic, err := renderTemplate(config.MQTT.topic, vars)
+			if err != nil {
+				return nil, err
+			}
+			preview.Method = "PUBLISH " + topic
+		}
+		body, err := newRequestBody(config, vars)
+		if err != nil {
+			return nil, err
+		}
+		if preview.Body, err = io.ReadAll(io.LimitReader(body.reader, previewBodyLimit)); err != nil {
+			return nil, err
+		}
+		preview.BodySize = body.length
+	case "redis":
+		preview.Method = config.Redis.Commands[0]
+		if tmpl := config.Redis.commands[0]; tmpl != nil {
+			if preview.Method, err = renderTemplate(tmpl, vars); err != nil {
+				return nil, err
+			}
+		}
+	}
+	if preview.BodySize < 0 && preview.Mode != "" {
+		preview.BodySize = int64(len(preview.Body))
+	}
+
+	if preview.Target, err = dialTarget(config, preview.URL); err != nil {
+		return nil, err
+	}
+	if preview.Addrs, err = resolveTarget(ctx, config, preview.Target); err != nil {
+		return nil, fmt.Errorf("resolving %s: %w", preview.Target, err)
+	}
+	return preview, nil
+}
+
+// configMode devolve o modo que executeLoadTest escolheria para config.
+func configMode(config Config) string {
+	switch {
+	case config.GRPC != nil:
+		return "grpc"
+	case config.TCP != nil:
+		return "tcp"
+	case config.UDP != nil:
+		return "udp"
+	case config.DNSQuery != nil:
+		return "dns"
+	case config.MQTT != nil:
+		return "mqtt"
+	case config.Redis != nil:
+		return "redis"
+	}
+	return ""
+}
+
+// dialTarget aplica -unix-socket, -connect-to e -resolve ao host da URL,
+// como o dialer faz durante o teste.
+func dialTarget(config Config, rawURL string) (string, error) {
+	switch {
+	case config.UnixSocket != "":
+		return config.UnixSocket, nil
+	case config.DNSQuery != nil:
+		return config.DNSQuery.Server, nil
+	case config.Redis != nil:
+		return config.Redis.Addr, nil
+	case config.ConnectTo != "":
+		return config.ConnectTo, nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	port := u.Port()
+	if port == "" {
+		port = map[string]string{"http": "80", "https": "443", "grpcs": "443", "mqtt": "1883"}[u.Scheme]
+	}
+	addr := net.JoinHostPort(u.Hostname(), port)
+	if pinned, ok := config.Resolve[addr]; ok {
+		return pinned, nil
+	}
+	return addr, nil
+}
+
+// resolveTarget consulta o -dns-server ou o resolvedor do sistema,
+// respeitando -ipv4 e -ipv6.
+func resolveTarget(ctx context.Context, config Config, target string) ([]string, error) {
+	if config.UnixSocket != "" || target == "" {
+		return nil, nil
+	}
+	host, _, err := net.SplitHostPort(target)
+	if err != nil {
+		return nil, err
+	}
+	if net.ParseIP(host) != nil {
+		return []string{host}, nil
+	}
+	resolver := net.DefaultResolver
+	if config.DNSServer != "" {
+		resolver = newResolver(config.DNSServer)
+	}
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+	network := "ip" + config.IPFamily
+	ips, err := resolver.LookupIP(ctx, network, host)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]string, len(ips))
+	for i, ip := range ips {
+		addrs[i] = ip.String()
+	}
+	return addrs, nil
+}
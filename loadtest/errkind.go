@@ -0,0 +1,111 @@
+package loadtest
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"syscall"
+)
+
+// ErrorKind classifica as falhas de transporte, em que não houve resposta
+// do servidor. Antes elas eram contadas como códigos HTTP inventados (503,
+// 452, 522...), misturados aos que o servidor realmente devolveu.
+type ErrorKind int
+
+const (
+	ErrorNone ErrorKind = iota
+	ErrorDNS
+	ErrorConnRefused
+	ErrorConnReset
+	ErrorConnClosed
+	ErrorConnectTimeout
+	ErrorTLS
+	ErrorTLSTimeout
+	ErrorResponseTimeout // sem os headers da resposta dentro do timeout
+	ErrorTimeout
+	ErrorTooManyRedirects
+	ErrorOther
+)
+
+var errorKindNames = []string{
+	ErrorNone:             "",
+	ErrorDNS:              "dns",
+	ErrorConnRefused:      "conn_refused",
+	ErrorConnReset:        "conn_reset",
+	ErrorConnClosed:       "conn_closed",
+	ErrorConnectTimeout:   "connect_timeout",
+	ErrorTLS:              "tls",
+	ErrorTLSTimeout:       "tls_timeout",
+	ErrorResponseTimeout:  "response_timeout",
+	ErrorTimeout:          "timeout",
+	ErrorTooManyRedirects: "too_many_redirects",
+	ErrorOther:            "other",
+}
+
+func (k ErrorKind) String() string {
+	if k < 0 || int(k) >= len(errorKindNames) {
+		return fmt.Sprintf("ErrorKind(%d)", int(k))
+	}
+	return errorKindNames[k]
+}
+
+// MarshalText grava o nome no JSON, que é o que aparece nos relatórios.
+func (k ErrorKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+func (k *ErrorKind) UnmarshalText(text []byte) error {
+	for kind, name := range errorKindNames {
+		if name == string(text) {
+			*k = ErrorKind(kind)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown error kind %q", text)
+}
+
+// ClassifyError identifica o tipo da falha pelos tipos de erro da biblioteca
+// padrão, sem depender do texto da mensagem.
+func ClassifyError(err error) ErrorKind {
+	if err == nil {
+		return ErrorNone
+	}
+	var (
+		dnsErr           *net.DNSError
+		redirectErr      *redirectLimitError
+		verifyErr        *tls.CertificateVerificationError
+		unknownAuthority x509.UnknownAuthorityError
+		hostnameErr      x509.HostnameError
+		invalidCert      x509.CertificateInvalidError
+		recordErr        tls.RecordHeaderError
+		alertErr         tls.AlertError
+		opErr            *net.OpError
+		netErr           net.Error
+	)
+	switch {
+	case errors.As(err, &dnsErr):
+		return ErrorDNS
+	case errors.As(err, &redirectErr):
+		return ErrorTooManyRedirects
+	case errors.As(err, &verifyErr), errors.As(err, &unknownAuthority), errors.As(err, &hostnameErr),
+		errors.As(err, &invalidCert), errors.As(err, &recordErr), errors.As(err, &alertErr):
+		return ErrorTLS
+	case errors.Is(err, syscall.ECONNREFUSED):
+		return ErrorConnRefused
+	case errors.Is(err, syscall.ECONNRESET), errors.Is(err, syscall.EPIPE):
+		return ErrorConnReset
+	case errors.As(err, &opErr) && opErr.Op == "dial" && opErr.Timeout():
+		return ErrorConnectTimeout
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, os.ErrDeadlineExceeded),
+		errors.As(err, &netErr) && netErr.Timeout():
+		return ErrorTimeout
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF), errors.Is(err, net.ErrClosed):
+		return ErrorConnClosed
+	}
+	return ErrorOther
+}
@@ -0,0 +1,123 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// ErrorRules é o arquivo do -error-rules: categorias definidas pelo usuário
+// e as regras que colocam cada falha em uma delas, por exemplo separando as
+// falhas de infraestrutura (conexão recusada, reset) das da aplicação (5xx).
+type ErrorRules struct {
+	Categories map[string]ErrorCategoryRule `json:"categories"`
+	Rules      []ErrorRule                  `json:"rules"`
+}
+
+// ErrorCategoryRule define a gravidade da categoria e, opcionalmente, o
+// percentual máximo de requisições nela antes de o teste falhar.
+type ErrorCategoryRule struct {
+	Severity string  `json:"severity"`
+	MaxRate  float64 `json:"max_rate"` // percentual; 0 = sem limite
+}
+
+// ErrorRule coloca na categoria as falhas que atendem a todos os critérios
+// preenchidos. A primeira regra que combina vence.
+type ErrorRule struct {
+	Kind     []ErrorKind `json:"kind"`    // conn_reset, dns, timeout...
+	Status   []string    `json:"status"`  // códigos (503), classes (5xx) ou intervalos (500-503), como no -expect-status
+	Message  string      `json:"message"` // expressão regular sobre a mensagem do erro
+	Category string      `json:"category"`
+
+	status  StatusSet
+	message *regexp.Regexp
+}
+
+// ErrorCategory é a contagem de uma categoria no relatório.
+type ErrorCategory struct {
+	Severity string
+	Count    int
+}
+
+// Severities são as gravidades aceitas, da menor para a maior.
+var Severities = []string{"info", "warning", "critical"}
+
+// LoadErrorRules lê e valida o arquivo do -error-rules.
+func LoadErrorRules(path string) (*ErrorRules, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading error rules: %w", err)
+	}
+	var rules ErrorRules
+	if err := json.Unmarshal(data, &rules); err != nil {
+		return nil, fmt.Errorf("parsing error rules %s: %w", path, err)
+	}
+	if len(rules.Rules) == 0 {
+		return nil, fmt.Errorf("%s: no rules", path)
+	}
+	for name, category := range rules.Categories {
+		if category.Severity == "" {
+			category.Severity = "warning"
+		}
+		if !slices.Contains(Severities, category.Severity) {
+			return nil, fmt.Errorf("%s: category %q: invalid severity %q (use %s)", path, name, category.Severity, strings.Join(Severities, ", "))
+		}
+		if category.MaxRate < 0 || category.MaxRate > 100 {
+			return nil, fmt.Errorf("%s: category %q: max_rate must be between 0 and 100", path, name)
+		}
+		rules.Categories[name] = category
+	}
+	for i := range rules.Rules {
+		rule := &rules.Rules[i]
+		if _, ok := rules.Categories[rule.Category]; !ok {
+			return nil, fmt.Errorf("%s: rule %d: unknown category %q", path, i+1, rule.Category)
+		}
+		if len(rule.Kind) == 0 && len(rule.Status) == 0 && rule.Message == "" {
+			return nil, fmt.Errorf("%s: rule %d: set at least one of kind, status or message", path, i+1)
+		}
+		if len(rule.Status) > 0 {
+			if rule.status, err = ParseStatusSet(strings.Join(rule.Status, ",")); err != nil {
+				return nil, fmt.Errorf("%s: rule %d: %w", path, i+1, err)
+			}
+		}
+		if rule.Message != "" {
+			if rule.message, err = regexp.Compile(rule.Message); err != nil {
+				return nil, fmt.Errorf("%s: rule %d: invalid message pattern: %w", path, i+1, err)
+			}
+		}
+	}
+	return &rules, nil
+}
+
+// Categorize devolve a categoria do resultado, ou "" se ele não é uma falha
+// ou nenhuma regra combina. Falhas são os erros e as respostas 4xx e 5xx;
+// com -expect-status (expected) só os erros, porque as respostas fora dos
+// códigos esperados já viraram erro e as esperadas são sucesso.
+func (r *ErrorRules) Categorize(result Result, expected StatusSet) string {
+	if result.Error == nil && (expected != nil || result.StatusCode < 400) {
+		return ""
+	}
+	for _, rule := range r.Rules {
+		if rule.matches(result) {
+			return rule.Category
+		}
+	}
+	return ""
+}
+
+func (rule ErrorRule) matches(result Result) bool {
+	if len(rule.Kind) > 0 && !slices.Contains(rule.Kind, result.ErrorKind) {
+		return false
+	}
+	// Falhas sem resposta não têm código e não combinam com nenhum
+	if rule.status != nil && (result.StatusCode == 0 || !rule.status.Contains(result.StatusCode)) {
+		return false
+	}
+	if rule.message != nil && (result.Error == nil || !rule.message.MatchString(result.Error.Error())) {
+		return false
+	}
+	return true
+}
@@ -0,0 +1,86 @@
+package loadtest
+
+import (
+	"errors"
+	"flag"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Com -expect-status, as respostas esperadas são sucesso e não podem cair
+// nas categorias do -error-rules; as inesperadas continuam sendo falhas.
+func TestErrorRulesWithExpectStatus(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	rules := `{
+		"categories": {"client": {"severity": "info", "max_rate": 1}, "app": {"severity": "critical"}},
+		"rules": [{"status": ["4xx"], "category": "client"}, {"status": ["5xx"], "category": "app"}]
+	}`
+	if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	build := Flags(fs)
+	if err := fs.Parse([]string{"-url", "http://example.com", "-requests", "3", "-expect-status", "404", "-error-rules", path}); err != nil {
+		t.Fatal(err)
+	}
+	config, err := build()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	results := make(chan Result, 3)
+	results <- Result{StatusCode: 404, Duration: time.Millisecond}
+	results <- Result{StatusCode: 404, Duration: time.Millisecond}
+	results <- Result{StatusCode: 500, Duration: time.Millisecond, Error: errors.New("unexpected status 500")}
+	close(results)
+	var firstSend atomic.Int64
+	report := collectResults(config, results, time.Now(), &firstSend)
+
+	if got := report.ErrorCategories["client"].Count; got != 0 {
+		t.Errorf("client category = %d, want 0 (404 is expected)", got)
+	}
+	if got := report.ErrorCategories["app"].Count; got != 1 {
+		t.Errorf("app category = %d, want 1", got)
+	}
+}
+
+// As regras aceitam a mesma sintaxe de status do -expect-status, inclusive
+// intervalos, e falhas sem resposta não combinam com nenhum código.
+func TestErrorRulesStatusSyntax(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.json")
+	rules := `{
+		"categories": {"gateway": {}, "other": {}},
+		"rules": [{"status": ["502-504"], "category": "gateway"}, {"status": ["5xx", "429"], "category": "other"}]
+	}`
+	if err := os.WriteFile(path, []byte(rules), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadErrorRules(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, tc := range []struct {
+		result Result
+		want   string
+	}{
+		{Result{StatusCode: 503}, "gateway"},
+		{Result{StatusCode: 500}, "other"},
+		{Result{StatusCode: 429}, "other"},
+		{Result{StatusCode: 404}, ""},
+		{Result{Error: errors.New("connection refused"), ErrorKind: ErrorConnRefused}, ""},
+	} {
+		if got := loaded.Categorize(tc.result, nil); got != tc.want {
+			t.Errorf("Categorize(%d, %v) = %q, want %q", tc.result.StatusCode, tc.result.Error, got, tc.want)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte(`{"categories": {"x": {}}, "rules": [{"status": ["5x"], "category": "x"}]}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadErrorRules(path); err == nil {
+		t.Error("invalid status 5x was accepted")
+	}
+}
@@ -0,0 +1,45 @@
+package loadtest
+
+// Resultado do handshake Expect: 100-continue de uma requisição
+type ContinueOutcome int
+
+const (
+	ContinueNotSent  ContinueOutcome = iota
+	ContinueReceived                 // servidor enviou 100 Continue antes do corpo
+	ContinueRejected                 // resposta final antes do corpo ser enviado
+	ContinueTimedOut                 // sem resposta no -continue-timeout, corpo enviado mesmo assim
+)
+
+type ContinueStats struct {
+	Sent     int
+	Received int
+	Rejected int
+	TimedOut int
+}
+
+func (s *ContinueStats) add(outcome ContinueOutcome) {
+	switch outcome {
+	case ContinueNotSent:
+		return
+	case ContinueReceived:
+		s.Received++
+	case ContinueRejected:
+		s.Rejected++
+	case ContinueTimedOut:
+		s.TimedOut++
+	}
+	s.Sent++
+}
+
+// continueOutcome classifica a requisição a partir do 100 recebido e de
+// quantos bytes do corpo chegaram a ser lidos pelo transport.
+func continueOutcome(got100 bool, bodySent int64) ContinueOutcome {
+	switch {
+	case got100:
+		return ContinueReceived
+	case bodySent == 0:
+		return ContinueRejected
+	default:
+		return ContinueTimedOut
+	}
+}
@@ -0,0 +1,67 @@
+package loadtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// stringList permite flags repetíveis, ex.: -resolve a -resolve b
+type stringList []string
+
+func (s *stringList) String() string {
+	return strings.Join(*s, ",")
+}
+
+func (s *stringList) Set(value string) error {
+	*s = append(*s, value)
+	return nil
+}
+
+// parseHeaders interpreta headers no formato 'key1:value1,key2:value2'.
+func parseHeaders(value string) map[string]string {
+	headersMap := make(map[string]string)
+	if value != "" {
+		pairs := strings.Split(value, ",")
+		for _, pair := range pairs {
+			kv := strings.SplitN(pair, ":", 2)
+			if len(kv) == 2 {
+				headersMap[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+			}
+		}
+	}
+	return headersMap
+}
+
+// parseByteSize interpreta tamanhos como 512, 10KB, 5MB ou 1GB (base 1024).
+func parseByteSize(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"gb", 1 << 30},
+		{"mb", 1 << 20},
+		{"kb", 1 << 10},
+		{"b", 1},
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(value))
+	multiplier := int64(1)
+	for _, unit := range units {
+		if strings.HasSuffix(lower, unit.suffix) {
+			lower = strings.TrimSuffix(lower, unit.suffix)
+			multiplier = unit.size
+			break
+		}
+	}
+
+	number, err := strconv.ParseFloat(strings.TrimSpace(lower), 64)
+	if err != nil || number < 0 {
+		return 0, fmt.Errorf("invalid size %q (use e.g. 512, 10KB, 5MB, 1GB)", value)
+	}
+	return int64(number * float64(multiplier)), nil
+}
@@ -0,0 +1,153 @@
+package loadtest
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// Intervalos no estilo do curl: [1-100], [001-100] ou [0-100:10]
+var globRangePattern = regexp.MustCompile(`^(\d+)-(\d+)(?::(\d+))?$`)
+
+// urlGlob representa uma URL com intervalos [a-b] e listas {x,y,z}. As
+// combinações são enumeradas por índice, sem gerar todas em memória.
+type urlGlob struct {
+	parts []globPart
+}
+
+type globPart struct {
+	literal        string
+	values         []string // lista {x,y,z}
+	lo, hi, step   int64    // intervalo [lo-hi:step]
+	width          int      // zeros à esquerda, ex.: [001-100]
+	isList, isSpan bool
+}
+
+// parseURLGlob retorna nil quando a URL não tem padrões de expansão.
+// Colchetes que não formam um intervalo (ex.: hosts IPv6 [::1]) e chaves sem
+// vírgula são mantidos como texto, assim como as ações {{...}} de template.
+func parseURLGlob(raw string) (*urlGlob, error) {
+	glob := &urlGlob{}
+	var literal strings.Builder
+	flush := func() {
+		if literal.Len() > 0 {
+			glob.parts = append(glob.parts, globPart{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(raw); i++ {
+		switch {
+		case strings.HasPrefix(raw[i:], "{{"):
+			end := strings.Index(raw[i:], "}}")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated template action in URL")
+			}
+			literal.WriteString(raw[i : i+end+2])
+			i += end + 1
+		case raw[i] == '\\' && i+1 < len(raw) && strings.ContainsRune("[]{}", rune(raw[i+1])):
+			literal.WriteByte(raw[i+1])
+			i++
+		case raw[i] == '[':
+			end := strings.IndexByte(raw[i:], ']')
+			if end < 0 {
+				literal.WriteByte(raw[i])
+				continue
+			}
+			part, ok, err := parseGlobRange(raw[i+1 : i+end])
+			if err != nil {
+				return nil, err
+			}
+			if !ok {
+				literal.WriteByte(raw[i])
+				continue
+			}
+			flush()
+			glob.parts = append(glob.parts, part)
+			i += end
+		case raw[i] == '{':
+			end := strings.IndexByte(raw[i:], '}')
+			if end < 0 || !strings.Contains(raw[i:i+end], ",") {
+				literal.WriteByte(raw[i])
+				continue
+			}
+			flush()
+			glob.parts = append(glob.parts, globPart{values: strings.Split(raw[i+1:i+end], ","), isList: true})
+			i += end
+		default:
+			literal.WriteByte(raw[i])
+		}
+	}
+	flush()
+
+	for _, part := range glob.parts {
+		if part.isList || part.isSpan {
+			return glob, nil
+		}
+	}
+	return nil, nil
+}
+
+func parseGlobRange(spec string) (globPart, bool, error) {
+	m := globRangePattern.FindStringSubmatch(spec)
+	if m == nil {
+		return globPart{}, false, nil
+	}
+	lo, _ := strconv.ParseInt(m[1], 10, 64)
+	hi, _ := strconv.ParseInt(m[2], 10, 64)
+	step := int64(1)
+	if m[3] != "" {
+		step, _ = strconv.ParseInt(m[3], 10, 64)
+	}
+	if hi < lo || step <= 0 {
+		return globPart{}, false, fmt.Errorf("invalid URL range [%s]", spec)
+	}
+	part := globPart{lo: lo, hi: hi, step: step, isSpan: true}
+	if len(m[1]) > 1 && m[1][0] == '0' {
+		part.width = len(m[1])
+	}
+	return part, true, nil
+}
+
+func (p globPart) size() int64 {
+	switch {
+	case p.isList:
+		return int64(len(p.values))
+	case p.isSpan:
+		return (p.hi-p.lo)/p.step + 1
+	default:
+		return 1
+	}
+}
+
+// size é o número de URLs distintas geradas pelo padrão.
+func (g *urlGlob) size() int64 {
+	total := int64(1)
+	for _, part := range g.parts {
+		total *= part.size()
+	}
+	return total
+}
+
+// expand devolve a URL de índice i, variando primeiro o último padrão,
+// na mesma ordem em que o curl enumera as combinações.
+func (g *urlGlob) expand(i int64) string {
+	i %= g.size()
+	values := make([]string, len(g.parts))
+	for j := len(g.parts) - 1; j >= 0; j-- {
+		part := g.parts[j]
+		n := part.size()
+		index := i % n
+		i /= n
+		switch {
+		case part.isList:
+			values[j] = part.values[index]
+		case part.isSpan:
+			values[j] = fmt.Sprintf("%0*d", part.width, part.lo+index*part.step)
+		default:
+			values[j] = part.literal
+		}
+	}
+	return strings.Join(values, "")
+}
@@ -0,0 +1,263 @@
+package loadtest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/bufbuild/protocompile"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// GRPCOptions descreve o método chamado no modo gRPC (-call).
+type GRPCOptions struct {
+	ProtoFiles  []string
+	ImportPaths []string
+	Call        string // pkg.Service/Method
+	Data        string // mensagem de entrada em JSON, aceita templates
+	// Nas chamadas com streaming do cliente, -data pode ser um array JSON;
+	// as mensagens são enviadas StreamCount vezes com StreamInterval entre elas
+	StreamCount    int
+	StreamInterval time.Duration
+}
+
+// grpcCall reúne o que é preparado uma única vez para todas as chamadas.
+type grpcCall struct {
+	conn       *grpc.ClientConn
+	method     protoreflect.MethodDescriptor
+	fullMethod string
+	data       *template.Template
+}
+
+// parseGRPCTarget aceita grpc://host:port (texto puro) e grpcs://host:port (TLS).
+func parseGRPCTarget(rawURL string) (addr string, useTLS bool, err error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return "", false, fmt.Errorf("invalid gRPC target %q (use grpc://host:port or grpcs://host:port)", rawURL)
+	}
+	switch u.Scheme {
+	case "grpc":
+		return u.Host, false, nil
+	case "grpcs":
+		return u.Host, true, nil
+	default:
+		return "", false, fmt.Errorf("invalid gRPC target %q (use grpc://host:port or grpcs://host:port)", rawURL)
+	}
+}
+
+// splitGRPCMethod separa "pkg.Service/Method" (ou "pkg.Service.Method").
+func splitGRPCMethod(call string) (service, method string, err error) {
+	call = strings.TrimPrefix(call, "/")
+	if service, method, ok := strings.Cut(call, "/"); ok && service != "" && method != "" {
+		return service, method, nil
+	}
+	if i := strings.LastIndex(call, "."); i > 0 && i < len(call)-1 {
+		return call[:i], call[i+1:], nil
+	}
+	return "", "", fmt.Errorf("invalid -call %q (use pkg.Service/Method)", call)
+}
+
+// loadProtoMethod compila os arquivos .proto e localiza o método chamado.
+func loadProtoMethod(opts *GRPCOptions) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitGRPCMethod(opts.Call)
+	if err != nil {
+		return nil, err
+	}
+
+	compiler := protocompile.Compiler{
+		Resolver: protocompile.WithStandardImports(&protocompile.SourceResolver{ImportPaths: opts.ImportPaths}),
+	}
+	files, err := compiler.Compile(context.Background(), opts.ProtoFiles...)
+	if err != nil {
+		return nil, fmt.Errorf("compiling proto files: %w", err)
+	}
+
+	desc, err := files.AsResolver().FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found in proto files", serviceName)
+	}
+	return findServiceMethod(desc, serviceName, methodName)
+}
+
+func findServiceMethod(desc protoreflect.Descriptor, serviceName, methodName string) (protoreflect.MethodDescriptor, error) {
+	service, ok := desc.(protoreflect.ServiceDescriptor)
+	if !ok {
+		return nil, fmt.Errorf("%s is not a service", serviceName)
+	}
+	method := service.Methods().ByName(protoreflect.Name(methodName))
+	if method == nil {
+		return nil, fmt.Errorf("method %s not found in service %s", methodName, serviceName)
+	}
+	return method, nil
+}
+
+func newGRPCCall(config Config) (*grpcCall, error) {
+	addr, useTLS, err := parseGRPCTarget(config.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	creds := insecure.NewCredentials()
+	if useTLS {
+		tlsConfig := config.TLSConfig.Clone()
+		if config.HostHeader != "" {
+			tlsConfig.ServerName = hostWithoutPort(config.HostHeader)
+		}
+		creds = credentials.NewTLS(tlsConfig)
+	}
+	dialOpts := []grpc.DialOption{grpc.WithTransportCredentials(creds)}
+	if config.HostHeader != "" {
+		dialOpts = append(dialOpts, grpc.WithAuthority(config.HostHeader))
+	}
+	conn, err := grpc.NewClient(addr, dialOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	// Sem arquivos .proto o schema vem da reflection do servidor
+	var method protoreflect.MethodDescriptor
+	if len(config.GRPC.ProtoFiles) > 0 {
+		method, err = loadProtoMethod(config.GRPC)
+	} else {
+		method, err = loadReflectedMethod(conn, config.GRPC.Call, config.Timeout)
+	}
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	data := config.GRPC.Data
+	if data == "" {
+		data = "{}"
+	}
+	tmpl, err := template.New("data").Funcs(templateFuncs).Option("missingkey=error").Parse(data)
+	if err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("parsing data template: %w", err)
+	}
+
+	return &grpcCall{
+		conn:       conn,
+		method:     method,
+		fullMethod: fmt.Sprintf("/%s/%s", method.Parent().FullName(), method.Name()),
+		data:       tmpl,
+	}, nil
+}
+
+func executeGRPCLoadTest(config Config) (Report, error) {
+	call, err := newGRPCCall(config)
+	if err != nil {
+		return Report{}, err
+	}
+	defer call.conn.Close()
+
+	// Abrir a conexão HTTP/2 antes da medição
+	call.conn.Connect()
+
+	report := runRequests(config, func(vars templateVars, _ *virtualUser, results chan<- Result) {
+		makeGRPCRequest(call, config, vars, results)
+	})
+	report.Mode = "grpc"
+	return report, nil
+}
+
+func makeGRPCRequest(call *grpcCall, config Config, vars templateVars, results chan<- Result) {
+	inputs, err := buildGRPCMessages(call, vars)
+	if err != nil {
+		results <- Result{
+			StatusCode: int(status.Code(err)),
+			Error:      fmt.Errorf("building request message: %w", err),
+		}
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(config.context(), config.Timeout)
+	defer cancel()
+	ctx = metadata.NewOutgoingContext(ctx, grpcMetadata(config, vars))
+
+	if call.method.IsStreamingClient() || call.method.IsStreamingServer() {
+		results <- runGRPCStream(ctx, call, config.GRPC, inputs)
+		return
+	}
+
+	output := dynamicpb.NewMessage(call.method.Output())
+	start := time.Now()
+	err = call.conn.Invoke(ctx, call.fullMethod, inputs[0], output)
+	duration := time.Since(start)
+
+	results <- Result{
+		StatusCode: int(status.Code(err)),
+		Duration:   duration,
+		Error:      err,
+		Proto:      "gRPC",
+	}
+}
+
+// buildGRPCMessages renderiza o -data e o converte nas mensagens de entrada.
+// Um array JSON gera várias mensagens, usadas nas chamadas com streaming.
+func buildGRPCMessages(call *grpcCall, vars templateVars) ([]proto.Message, error) {
+	data, err := renderTemplate(call.data, vars)
+	if err != nil {
+		return nil, err
+	}
+
+	raws := []json.RawMessage{json.RawMessage(data)}
+	if strings.HasPrefix(strings.TrimSpace(data), "[") {
+		if err := json.Unmarshal([]byte(data), &raws); err != nil {
+			return nil, err
+		}
+		if len(raws) == 0 {
+			return nil, fmt.Errorf("-data array has no messages")
+		}
+	}
+	if len(raws) > 1 && !call.method.IsStreamingClient() {
+		return nil, fmt.Errorf("%s accepts a single request message", call.method.FullName())
+	}
+
+	messages := make([]proto.Message, len(raws))
+	for i, raw := range raws {
+		message := dynamicpb.NewMessage(call.method.Input())
+		if err := protojson.Unmarshal(raw, message); err != nil {
+			return nil, err
+		}
+		messages[i] = message
+	}
+	return messages, nil
+}
+
+// grpcMetadata converte os -headers (inclusive os com templates) em metadata.
+func grpcMetadata(config Config, vars templateVars) metadata.MD {
+	md := metadata.MD{}
+	// Em ordem fixa, para o -seed repetir os valores sorteados
+	for _, k := range slices.Sorted(maps.Keys(config.Headers)) {
+		v := config.Headers[k]
+		if tmpl, ok := config.HeaderTemplates[k]; ok {
+			if rendered, err := renderTemplate(tmpl, vars); err == nil {
+				v = rendered
+			}
+		}
+		md.Append(strings.ToLower(k), v)
+	}
+	live := http.Header{}
+	if err := config.LiveHeaders.apply(live, vars); err == nil {
+		for k, values := range live {
+			md.Set(strings.ToLower(k), values...)
+		}
+	}
+	return md
+}
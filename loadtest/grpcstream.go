@@ -0,0 +1,111 @@
+package loadtest
+
+import (
+	"context"
+	"errors"
+	"io"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/dynamicpb"
+)
+
+// StreamStats resume as chamadas gRPC com streaming.
+type StreamStats struct {
+	Streams          int
+	MessagesSent     int
+	MessagesReceived int
+	MessageLatency   PhaseStats // intervalo até cada mensagem recebida
+	StreamDuration   PhaseStats
+}
+
+func (s *StreamStats) add(result Result) {
+	if !result.Stream {
+		return
+	}
+	s.Streams++
+	s.MessagesSent += result.MessagesSent
+	s.MessagesReceived += len(result.MessageGaps)
+	for _, gap := range result.MessageGaps {
+		s.MessageLatency.add(gap)
+	}
+	s.StreamDuration.add(result.Duration)
+}
+
+// runGRPCStream executa uma chamada client-streaming, server-streaming ou
+// bidirecional. As mensagens são enviadas enquanto as respostas são lidas
+// em paralelo; a latência por mensagem é o intervalo desde a mensagem
+// anterior (ou desde o início do stream, para a primeira).
+func runGRPCStream(ctx context.Context, call *grpcCall, opts *GRPCOptions, inputs []proto.Message) Result {
+	desc := &grpc.StreamDesc{
+		StreamName:    string(call.method.Name()),
+		ClientStreams: call.method.IsStreamingClient(),
+		ServerStreams: call.method.IsStreamingServer(),
+	}
+
+	start := time.Now()
+	stream, err := call.conn.NewStream(ctx, desc, call.fullMethod)
+	if err != nil {
+		return Result{StatusCode: int(status.Code(err)), Duration: time.Since(start), Error: err, Proto: "gRPC", Stream: true}
+	}
+
+	sendErr := make(chan error, 1)
+	sent := 0
+	go func() {
+		// Sem streaming do cliente a única mensagem é enviada uma vez
+		repeat := 1
+		if desc.ClientStreams {
+			repeat = max(opts.StreamCount, 1)
+		}
+		for i := 0; i < repeat; i++ {
+			for _, input := range inputs {
+				if sent > 0 && opts.StreamInterval > 0 {
+					time.Sleep(opts.StreamInterval)
+				}
+				if err := stream.SendMsg(input); err != nil {
+					// io.EOF indica que o servidor encerrou o stream; o status
+					// real aparece no RecvMsg
+					if err == io.EOF {
+						err = nil
+					}
+					sendErr <- err
+					return
+				}
+				sent++
+			}
+		}
+		sendErr <- stream.CloseSend()
+	}()
+
+	var gaps []time.Duration
+	last := start
+	for {
+		output := dynamicpb.NewMessage(call.method.Output())
+		err = stream.RecvMsg(output)
+		if err != nil {
+			break
+		}
+		now := time.Now()
+		gaps = append(gaps, now.Sub(last))
+		last = now
+	}
+	duration := time.Since(start)
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+	if sErr := <-sendErr; err == nil {
+		err = sErr
+	}
+
+	return Result{
+		StatusCode:   int(status.Code(err)),
+		Duration:     duration,
+		Error:        err,
+		Proto:        "gRPC",
+		Stream:       true,
+		MessagesSent: sent,
+		MessageGaps:  gaps,
+	}
+}
@@ -0,0 +1,106 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Estruturas do formato HAR 1.2 usadas na conversão.
+type harFile struct {
+	Log struct {
+		Entries []harEntry `json:"entries"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	StartedDateTime time.Time `json:"startedDateTime"`
+	Request         struct {
+		Method  string `json:"method"`
+		URL     string `json:"url"`
+		Headers []struct {
+			Name  string `json:"name"`
+			Value string `json:"value"`
+		} `json:"headers"`
+		PostData *struct {
+			MimeType string `json:"mimeType"`
+			Text     string `json:"text"`
+		} `json:"postData"`
+	} `json:"request"`
+}
+
+// Headers que o client gera sozinho ou que não fazem sentido em um replay.
+var HARSkippedHeaders = map[string]bool{
+	"host":              true,
+	"content-length":    true,
+	"connection":        true,
+	"keep-alive":        true,
+	"transfer-encoding": true,
+	"upgrade":           true,
+	"te":                true,
+}
+
+// loadHARTargets converte as requisições de um HAR exportado pelo navegador
+// em alvos, guardando o instante de cada uma em relação à primeira.
+func loadHARTargets(path string) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading HAR file: %w", err)
+	}
+	var har harFile
+	if err := json.Unmarshal(data, &har); err != nil {
+		return nil, fmt.Errorf("parsing HAR file: %w", err)
+	}
+
+	var targets []Target
+	var first time.Time
+	for _, entry := range har.Log.Entries {
+		req := entry.Request
+		// Ignorar data:, blob:, extensões do navegador e afins
+		if !strings.HasPrefix(req.URL, "http://") && !strings.HasPrefix(req.URL, "https://") {
+			continue
+		}
+		if first.IsZero() {
+			first = entry.StartedDateTime
+		}
+
+		target := Target{
+			Name:     req.Method + " " + req.URL,
+			Method:   req.Method,
+			URL:      req.URL,
+			OffsetMS: float64(entry.StartedDateTime.Sub(first)) / float64(time.Millisecond),
+		}
+		headers := make(map[string]string)
+		for _, h := range req.Headers {
+			name := h.Name
+			// Pseudo-headers do HTTP/2 (:authority, :path...)
+			if strings.HasPrefix(name, ":") || HARSkippedHeaders[strings.ToLower(name)] {
+				continue
+			}
+			if previous, ok := headers[name]; ok {
+				sep := ", "
+				if strings.EqualFold(name, "Cookie") {
+					sep = "; "
+				}
+				h.Value = previous + sep + h.Value
+			}
+			headers[name] = h.Value
+		}
+		if req.PostData != nil {
+			target.Body = req.PostData.Text
+			if req.PostData.MimeType != "" && !hasHeader(headers, "Content-Type") {
+				headers["Content-Type"] = req.PostData.MimeType
+			}
+		}
+		if len(headers) > 0 {
+			target.Headers = headers
+		}
+		targets = append(targets, target)
+	}
+	if len(targets) == 0 {
+		return nil, fmt.Errorf("HAR file %s has no HTTP requests", path)
+	}
+	return targets, nil
+}
@@ -0,0 +1,122 @@
+package loadtest
+
+import (
+	"math"
+	"math/bits"
+	"slices"
+	"time"
+)
+
+// histogramSubBits define quantos baldes há em cada potência de dois: com
+// 2^7 = 128 baldes o erro relativo dos percentis fica abaixo de 1%.
+const histogramSubBits = 7
+
+// Histogram acumula as latências em baldes de largura proporcional ao
+// valor, com memória limitada (no máximo alguns milhares de baldes)
+// independentemente do número de requisições. Os percentis saem dos
+// baldes; a média e o desvio padrão, das somas exatas.
+type Histogram struct {
+	Count      int64
+	Sum        float64       // soma das durações, em nanossegundos
+	SumSquares float64       // soma dos quadrados, para o desvio padrão
+	Buckets    map[int]int64 // índice do balde -> requisições
+}
+
+// NewHistogram cria um histograma vazio.
+func NewHistogram() *Histogram {
+	return &Histogram{Buckets: make(map[int]int64)}
+}
+
+// Add registra uma duração.
+func (h *Histogram) Add(d time.Duration) {
+	if d < 0 {
+		d = 0
+	}
+	ns := float64(d)
+	h.Count++
+	h.Sum += ns
+	h.SumSquares += ns * ns
+	h.Buckets[bucketIndex(uint64(d))]++
+}
+
+// Merge soma outro histograma a este.
+func (h *Histogram) Merge(other *Histogram) {
+	if other == nil {
+		return
+	}
+	h.Count += other.Count
+	h.Sum += other.Sum
+	h.SumSquares += other.SumSquares
+	for index, count := range other.Buckets {
+		h.Buckets[index] += count
+	}
+}
+
+// Mean é a média exata das durações.
+func (h *Histogram) Mean() time.Duration {
+	if h == nil || h.Count == 0 {
+		return 0
+	}
+	return time.Duration(h.Sum / float64(h.Count))
+}
+
+// StdDeviation é o desvio padrão populacional das durações.
+func (h *Histogram) StdDeviation() time.Duration {
+	if h == nil || h.Count == 0 {
+		return 0
+	}
+	mean := h.Sum / float64(h.Count)
+	variance := h.SumSquares/float64(h.Count) - mean*mean
+	if variance < 0 {
+		variance = 0
+	}
+	return time.Duration(math.Sqrt(variance))
+}
+
+// Percentile devolve o valor do balde em que cai o percentil, com o mesmo
+// critério de posição da função Percentile sobre as durações ordenadas.
+func (h *Histogram) Percentile(percentile float64) time.Duration {
+	if h == nil || h.Count == 0 {
+		return 0
+	}
+	rank := int64(float64(h.Count) * percentile / 100)
+	if rank >= h.Count {
+		rank = h.Count - 1
+	}
+	indexes := make([]int, 0, len(h.Buckets))
+	for index := range h.Buckets {
+		indexes = append(indexes, index)
+	}
+	slices.Sort(indexes)
+	var seen int64
+	for _, index := range indexes {
+		seen += h.Buckets[index]
+		if seen > rank {
+			return bucketValue(index)
+		}
+	}
+	return bucketValue(indexes[len(indexes)-1])
+}
+
+// bucketIndex: valores abaixo de 128ns têm um balde cada; acima disso, cada
+// potência de dois é dividida em 128 baldes pelos 7 bits seguintes ao mais
+// significativo.
+func bucketIndex(v uint64) int {
+	const sub = 1 << histogramSubBits
+	if v < sub {
+		return int(v)
+	}
+	shift := bits.Len64(v) - histogramSubBits - 1
+	return (shift+1)*sub + int(v>>shift) - sub
+}
+
+// bucketValue é o meio do intervalo coberto pelo balde.
+func bucketValue(index int) time.Duration {
+	const sub = 1 << histogramSubBits
+	if index < sub {
+		return time.Duration(index)
+	}
+	shift := index/sub - 1
+	lower := uint64(index%sub+sub) << shift
+	return time.Duration(lower + (uint64(1)<<shift-1)/2)
+}
@@ -0,0 +1,76 @@
+package loadtest
+
+import (
+	"math"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Os percentis do histograma ficam a menos de 1% dos calculados sobre as
+// durações ordenadas, e a média é exata.
+func TestHistogramPercentiles(t *testing.T) {
+	h := NewHistogram()
+	var durations []time.Duration
+	for i := 1; i <= 10000; i++ {
+		d := time.Duration(i) * 137 * time.Microsecond
+		h.Add(d)
+		durations = append(durations, d)
+	}
+	for _, p := range []float64{50, 90, 95, 99, 100} {
+		want := Percentile(durations, p)
+		got := h.Percentile(p)
+		if diff := math.Abs(float64(got-want)) / float64(want); diff > 0.01 {
+			t.Errorf("P%v = %v, want %v (±1%%)", p, got, want)
+		}
+	}
+	if got, want := h.Mean(), 10001*137*time.Microsecond/2; got != want {
+		t.Errorf("Mean() = %v, want %v", got, want)
+	}
+	if len(h.Buckets) > 2000 {
+		t.Errorf("%d buckets for 10000 samples", len(h.Buckets))
+	}
+}
+
+func TestHistogramMerge(t *testing.T) {
+	a, b, all := NewHistogram(), NewHistogram(), NewHistogram()
+	for i := 1; i <= 100; i++ {
+		d := time.Duration(i) * time.Millisecond
+		if i%2 == 0 {
+			a.Add(d)
+		} else {
+			b.Add(d)
+		}
+		all.Add(d)
+	}
+	a.Merge(b)
+	for _, p := range []float64{50, 95, 99} {
+		if a.Percentile(p) != all.Percentile(p) {
+			t.Errorf("merged P%v = %v, want %v", p, a.Percentile(p), all.Percentile(p))
+		}
+	}
+	if a.StdDeviation() != all.StdDeviation() {
+		t.Errorf("merged StdDeviation() = %v, want %v", a.StdDeviation(), all.StdDeviation())
+	}
+}
+
+// O relatório não guarda mais uma duração por requisição.
+func TestCollectResultsKeepsNoRawDurations(t *testing.T) {
+	results := make(chan Result, 1000)
+	for i := range 1000 {
+		results <- Result{StatusCode: 200, Duration: time.Duration(i+1) * time.Millisecond}
+	}
+	close(results)
+	var firstSend atomic.Int64
+	report := collectResults(Config{}, results, time.Now(), &firstSend)
+
+	if report.Durations != nil {
+		t.Errorf("Durations has %d entries, want none", len(report.Durations))
+	}
+	if report.Samples() != 1000 {
+		t.Errorf("Samples() = %d, want 1000", report.Samples())
+	}
+	if p50 := report.Percentile(50); p50 < 495*time.Millisecond || p50 > 506*time.Millisecond {
+		t.Errorf("P50 = %v, want about 501ms", p50)
+	}
+}
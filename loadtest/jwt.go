@@ -0,0 +1,104 @@
+package loadtest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+type JWTOptions struct {
+	Key       string // segredo (HS*) ou arquivo PEM da chave privada (RS*, ES*, PS*, EdDSA)
+	Algorithm string
+	Claims    string // JSON literal ou @arquivo
+	TTL       time.Duration
+}
+
+// JWTMinter assina um JWT novo por requisição, com jti, iat e exp próprios.
+type JWTMinter struct {
+	method jwt.SigningMethod
+	key    any
+	claims map[string]any
+	ttl    time.Duration
+}
+
+func newJWTMinter(opts JWTOptions) (*JWTMinter, error) {
+	if opts.Key == "" {
+		return nil, nil
+	}
+
+	method := jwt.GetSigningMethod(opts.Algorithm)
+	if method == nil {
+		return nil, fmt.Errorf("unsupported -jwt-alg %q", opts.Algorithm)
+	}
+
+	key, err := loadJWTKey(method, opts.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := make(map[string]any)
+	if opts.Claims != "" {
+		data := []byte(opts.Claims)
+		if strings.HasPrefix(opts.Claims, "@") {
+			if data, err = os.ReadFile(opts.Claims[1:]); err != nil {
+				return nil, fmt.Errorf("reading JWT claims: %w", err)
+			}
+		}
+		if err := json.Unmarshal(data, &claims); err != nil {
+			return nil, fmt.Errorf("parsing JWT claims: %w", err)
+		}
+	}
+
+	return &JWTMinter{method: method, key: key, claims: claims, ttl: opts.TTL}, nil
+}
+
+func loadJWTKey(method jwt.SigningMethod, value string) (any, error) {
+	if _, ok := method.(*jwt.SigningMethodHMAC); ok {
+		secret, err := resolveSecret(value)
+		if err != nil {
+			return nil, err
+		}
+		return []byte(secret), nil
+	}
+
+	data, err := os.ReadFile(value)
+	if err != nil {
+		return nil, fmt.Errorf("reading JWT signing key: %w", err)
+	}
+	switch method.(type) {
+	case *jwt.SigningMethodRSA, *jwt.SigningMethodRSAPSS:
+		return jwt.ParseRSAPrivateKeyFromPEM(data)
+	case *jwt.SigningMethodECDSA:
+		return jwt.ParseECPrivateKeyFromPEM(data)
+	case *jwt.SigningMethodEd25519:
+		return jwt.ParseEdPrivateKeyFromPEM(data)
+	default:
+		return nil, fmt.Errorf("unsupported JWT signing method %s", method.Alg())
+	}
+}
+
+func (m *JWTMinter) mint() (string, error) {
+	now := time.Now()
+	claims := jwt.MapClaims{}
+	for k, v := range m.claims {
+		claims[k] = v
+	}
+	claims["jti"] = newJTI()
+	claims["iat"] = now.Unix()
+	claims["nbf"] = now.Unix()
+	claims["exp"] = now.Add(m.ttl).Unix()
+
+	return jwt.NewWithClaims(m.method, claims).SignedString(m.key)
+}
+
+func newJTI() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
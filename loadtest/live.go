@@ -0,0 +1,168 @@
+package loadtest
+
+import (
+	"maps"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// LiveStats acompanha o teste enquanto ele roda, para o painel web (-ui).
+// Mantém apenas uma janela recente de latências e erros.
+type LiveStats struct {
+	mu        sync.Mutex
+	start     time.Time
+	planned   int
+	total     int
+	errors    int
+	status    map[int]int
+	kinds     map[ErrorKind]int
+	recent    []time.Duration // últimas latências, em anel
+	next      int
+	perSecond map[int64]int // requisições concluídas por segundo
+	feed      []liveError
+	done      bool
+}
+
+type liveError struct {
+	Time    time.Time `json:"time"`
+	Status  int       `json:"status"`
+	Kind    ErrorKind `json:"kind,omitempty"`
+	Message string    `json:"message"`
+}
+
+// label é o código de status ou, sem resposta, o tipo da falha.
+func (e liveError) label() string {
+	if e.Kind != ErrorNone && e.Status == 0 {
+		return e.Kind.String()
+	}
+	return strconv.Itoa(e.Status)
+}
+
+type LiveSnapshot struct {
+	Elapsed float64           `json:"elapsed_s"`
+	Planned int               `json:"planned"`
+	Total   int               `json:"total"`
+	Errors  int               `json:"errors"`
+	RPS     float64           `json:"rps"` // último segundo completo
+	P50     float64           `json:"p50_ms"`
+	P90     float64           `json:"p90_ms"`
+	P95     float64           `json:"p95_ms"`
+	P99     float64           `json:"p99_ms"`
+	Status  map[int]int       `json:"status"`
+	Kinds   map[ErrorKind]int `json:"error_kinds"`
+	Feed    []liveError       `json:"errors_feed"`
+	Done    bool              `json:"done"`
+}
+
+const (
+	liveWindow   = 1000 // latências usadas nos percentis
+	liveFeedSize = 50
+)
+
+func NewLiveStats(planned int) *LiveStats {
+	return &LiveStats{
+		start:     time.Now(),
+		planned:   planned,
+		status:    make(map[int]int),
+		kinds:     make(map[ErrorKind]int),
+		perSecond: make(map[int64]int),
+	}
+}
+
+func (l *LiveStats) add(result Result) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	l.total++
+	if result.hasStatus() {
+		l.status[result.StatusCode]++
+	}
+	if result.ErrorKind != ErrorNone {
+		l.kinds[result.ErrorKind]++
+	}
+	l.perSecond[now.Unix()]++
+	if result.Duration > 0 {
+		if len(l.recent) < liveWindow {
+			l.recent = append(l.recent, result.Duration)
+		} else {
+			l.recent[l.next] = result.Duration
+			l.next = (l.next + 1) % liveWindow
+		}
+	}
+	if result.Error != nil {
+		l.errors++
+		l.feed = append(l.feed, liveError{Time: now, Status: result.StatusCode, Kind: result.ErrorKind, Message: result.Error.Error()})
+		if len(l.feed) > liveFeedSize {
+			l.feed = l.feed[1:]
+		}
+	}
+}
+
+func (l *LiveStats) finish() {
+	l.mu.Lock()
+	l.done = true
+	l.mu.Unlock()
+}
+
+func (l *LiveStats) Snapshot() LiveSnapshot {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	s := LiveSnapshot{
+		Elapsed: now.Sub(l.start).Seconds(),
+		Planned: l.planned,
+		Total:   l.total,
+		Errors:  l.errors,
+		RPS:     float64(l.perSecond[now.Unix()-1]),
+		Status:  make(map[int]int, len(l.status)),
+		Kinds:   maps.Clone(l.kinds),
+		Feed:    append([]liveError{}, l.feed...),
+		Done:    l.done,
+	}
+	for code, count := range l.status {
+		s.Status[code] = count
+	}
+	// Descartar os segundos que já saíram do gráfico
+	for sec := range l.perSecond {
+		if sec < now.Unix()-5 {
+			delete(l.perSecond, sec)
+		}
+	}
+
+	durations := append([]time.Duration(nil), l.recent...)
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	ms := func(p float64) float64 {
+		return float64(Percentile(durations, p)) / float64(time.Millisecond)
+	}
+	s.P50, s.P90, s.P95, s.P99 = ms(50), ms(90), ms(95), ms(99)
+	return s
+}
+
+// StopSignal interrompe o teste antes do fim: as requisições em andamento
+// terminam e as restantes não são enviadas.
+type StopSignal struct {
+	ch   chan struct{}
+	once sync.Once
+}
+
+func NewStopSignal() *StopSignal {
+	return &StopSignal{ch: make(chan struct{})}
+}
+
+func (s *StopSignal) Stop() {
+	s.once.Do(func() { close(s.ch) })
+}
+
+func (s *StopSignal) Stopped() bool {
+	if s == nil {
+		return false
+	}
+	select {
+	case <-s.ch:
+		return true
+	default:
+		return false
+	}
+}
@@ -0,0 +1,910 @@
+// Package loadtest é o motor do teste de carga: recebe uma Config, dispara
+// as requisições no protocolo escolhido e devolve o Report agregado.
+package loadtest
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"maps"
+	mathrand "math/rand/v2"
+	"net"
+	"net/http"
+	"net/http/httptrace"
+	"slices"
+	"sort"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	"golang.org/x/oauth2"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+type Result struct {
+	StatusCode   int
+	Duration     time.Duration
+	Error        error
+	ErrorKind    ErrorKind // falha de transporte, com StatusCode 0 quando não houve resposta
+	Phases       PhaseTimings
+	Proto        string
+	TLSVersion   string
+	RemoteAddr   string
+	ConnReused   bool
+	BytesRead    int64
+	BytesDecoded int64
+	Compressed   bool
+	Redirects    int
+	BytesSent    int64
+	Continue     ContinueOutcome
+	Trailers     http.Header
+	Conditional  bool
+	Range        RangeOutcome
+	Stream       bool
+	MessagesSent int
+	MessageGaps  []time.Duration
+	SSE          *sseResult
+	TCP          *tcpResult
+	UDP          *udpResult
+	DNS          *dnsResult
+	Redis        *redisResult
+}
+
+// hasStatus informa se houve um código de status (HTTP, gRPC ou do
+// protocolo) em vez de uma falha de transporte.
+func (r Result) hasStatus() bool {
+	return r.ErrorKind == ErrorNone || r.StatusCode != 0
+}
+
+type Config struct {
+	URL                   string
+	Requests              int
+	MaxDuration           time.Duration // -max-duration: para de enviar depois desse tempo, mesmo sem chegar a Requests
+	Concurrency           int
+	Timeout               time.Duration
+	Method                string
+	Headers               map[string]string
+	Body                  string
+	Format                string // "plain", "json", "csv"
+	HTTPVersion           string // "auto", "1.1", "2", "h2c"
+	TLSConfig             *tls.Config
+	ConnectTo             string // endereço host:port usado na conexão TCP
+	HostHeader            string
+	Resolve               map[string]string // "host:port" -> "addr:port"
+	DNSServer             string
+	IPFamily              string // "", "4" ou "6"
+	UnixSocket            string
+	DisableKeepAlive      bool
+	Compression           string // "gzip", "br", "none"
+	MaxRedirects          int
+	NoFollow              bool
+	Prewarm               bool
+	NoDNSCache            bool
+	DNSCacheTTL           time.Duration // 0 = resolver uma única vez
+	LocalAddrs            []net.IP      // rotacionados entre as conexões
+	ConnectTimeout        time.Duration
+	TLSTimeout            time.Duration
+	ResponseHeaderTimeout time.Duration
+	MaxIdleConns          int // 0 = acompanha a concorrência
+	MaxIdleConnsPerHost   int // 0 = acompanha a concorrência
+	MaxConnsPerHost       int // 0 = sem limite
+	IdleConnTimeout       time.Duration
+	WriteBufferSize       int
+	ReadBufferSize        int
+	Throttle              int64 // bytes por segundo por conexão, 0 = sem limite
+	BasicAuth             *BasicAuth
+	BearerToken           string
+	OAuth2                oauth2.TokenSource
+	NTLM                  *NTLMAuth
+	Kerberos              *KerberosAuth
+	JWT                   *JWTMinter
+	Login                 *LoginOptions
+	Form                  []FormField
+	BodyFile              string
+	BodySize              int64
+	Chunked               bool
+	ChunkSize             int
+	ChunkDelay            time.Duration
+	CompressBody          bool
+	ExpectContinue        bool
+	ContinueTimeout       time.Duration
+	Trailers              http.Header
+	BodyTemplate          *template.Template // -body com ações {{...}}, renderizado por requisição
+	URLTemplate           *template.Template
+	HeaderTemplates       map[string]*template.Template
+	Query                 []QueryParam
+	HeaderRotations       []HeaderRotation
+	URLGlob               *urlGlob        // padrões [1-100] e {a,b} da URL
+	CacheValidators       *validatorStore // -cache-validate
+	Range                 *RangeOptions
+	GRPC                  *GRPCOptions // modo gRPC (-call)
+	SSE                   *SSEOptions
+	TCP                   *TCPOptions         // modo TCP (-url tcp://host:port)
+	UDP                   *UDPOptions         // modo UDP (-url udp://host:port)
+	DNSQuery              *DNSQueryOptions    // modo DNS (-dns-query)
+	MQTT                  *MQTTOptions        // modo MQTT (-url mqtt://host:port)
+	Redis                 *RedisOptions       // modo RESP (-url redis://host:port)
+	Targets               []Target            // requisições em rodízio (-targets, -postman, -har)
+	PreserveTiming        bool                // respeitar os intervalos originais entre os alvos
+	RequestLog            *RequestLogger      // log NDJSON de cada requisição (-request-log)
+	Live                  *LiveStats          // estatísticas parciais para o painel (-ui)
+	Stop                  *StopSignal         // interrompe o teste antes do fim
+	Control               *ConcurrencyControl // muda a concorrência durante o teste
+	LiveHeaders           *LiveHeaders        // headers trocados durante o teste (-watch)
+	Checkpoint            *Checkpointer       // grava o progresso para o -resume
+	TUI                   bool                // painel de terminal no lugar da linha de progresso
+	Quiet                 bool                // sem linha de progresso (-quiet e modo serve)
+	Progress              string              // "line" (padrão) ou "json"
+	BodySizes             []int64             // -body-sizes: repete o teste para cada tamanho
+	Labels                map[string]string   // -label, copiados para Report.Metadata
+	ErrorRules            *ErrorRules         // -error-rules: categorias de falha do usuário
+	SuccessStatus         string              // -success-status, gravado no relatório
+	ExpectStatus          StatusSet           // -expect-status: as demais respostas contam como erro
+	Seed                  uint64              // -seed; 0 sorteia uma semente no Run
+	SeqStart              int64               // -seq-start: a primeira requisição é SeqStart+1
+
+	ctx   context.Context    // ctx do Run; cancelá-lo aborta as requisições em andamento
+	abort context.CancelFunc // cancela ctx, usado pelo Ctrl+C do painel
+}
+
+type Report struct {
+	TotalTime          time.Duration // tempo de relógio do teste, com a preparação e a agregação
+	SendWindow         time.Duration // do envio da primeira requisição à chegada da última resposta
+	TotalRequests      int
+	StatusCodes        map[int]int       // respostas do servidor
+	SuccessStatus      string            `json:",omitempty"` // códigos contados como sucesso no resumo; "" = 2xx
+	ErrorKinds         map[ErrorKind]int // falhas sem resposta, por tipo
+	Errors             int
+	Latencies          *Histogram      `json:",omitempty"` // latências em baldes, para os percentis
+	Durations          []time.Duration `json:",omitempty"` // só em relatórios antigos, antes do Latencies
+	MinDuration        time.Duration
+	MaxDuration        time.Duration
+	AvgDuration        time.Duration
+	RPS                float64
+	StdDeviation       time.Duration
+	ErrorDetails       map[string]ErrorDetail
+	ErrorCategories    map[string]ErrorCategory `json:",omitempty"` // falhas agrupadas pelo -error-rules
+	Phases             PhaseBreakdown
+	Protocols          map[string]int
+	TLSVersions        map[string]int
+	IPFamilies         map[string]int
+	NewConns           int
+	ReusedConns        int
+	BytesRead          int64
+	BytesDecoded       int64
+	Compressed         int
+	Redirects          int
+	AvgRedirects       float64
+	PerIP              map[string]*IPStats
+	BytesSent          int64
+	UploadRate         float64 // bytes por segundo durante o envio dos corpos
+	Continue           ContinueStats
+	Trailers           TrailerStats
+	Cache              CacheStats
+	Range              RangeStats
+	Mode               string // "" (HTTP), "grpc", "tcp", "udp", "dns", "mqtt" ou "redis"
+	Streams            StreamStats
+	SSE                SSEStats
+	TCP                TCPStats
+	UDP                UDPStats
+	DNS                DNSStats
+	MQTT               MQTTStats
+	Redis              RedisStats
+	Stopped            bool // interrompido antes de enviar todas as requisições
+	Aborted            bool // cancelado pelo ctx do Run (Ctrl+C): as requisições em andamento foram descartadas
+	MaxDurationReached bool // parado pelo -max-duration antes de enviar todas as requisições
+	ConcurrencyChanges []ConcurrencyChange
+	Timeline           []TimelineEvent // mudanças do -watch aplicadas durante o teste
+	Seed               uint64          // semente dos valores aleatórios, para repetir o teste com -seed
+	BodySize           int64           `json:",omitempty"` // corpo sintético do -body-size ou da rodada do -body-sizes
+	Metadata           Metadata
+}
+
+type IPStats struct {
+	Requests int
+	Errors   int
+	Latency  PhaseStats
+}
+
+type ErrorDetail struct {
+	Count   int
+	Message string
+	Code    int       // Código HTTP associado ao erro, se aplicável
+	Kind    ErrorKind `json:",omitempty"`
+}
+
+// Run executa o teste descrito por config. Cancelar ctx interrompe o teste:
+// as requisições restantes não são enviadas, as em andamento são canceladas
+// e descartadas, e o relatório parcial é devolvido com Stopped e Aborted
+// marcados. O botão de parar do painel (config.Stop) deixa as requisições
+// em andamento terminarem.
+func Run(ctx context.Context, config Config) (*Report, error) {
+	if config.Stop == nil {
+		config.Stop = NewStopSignal()
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	config.ctx, config.abort = ctx, cancel
+	for config.Seed == 0 {
+		config.Seed = mathrand.Uint64()
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			config.Stop.Stop()
+		case <-done:
+		}
+	}()
+
+	began := time.Now()
+	report, err := executeLoadTest(config)
+	if err != nil {
+		return nil, err
+	}
+	report.TotalTime = time.Since(began)
+	report.Metadata = NewMetadata(config.Labels)
+	report.Seed = config.Seed
+	report.BodySize = config.BodySize
+	report.SuccessStatus = config.SuccessStatus
+	report.Aborted = report.Stopped && ctx.Err() != nil
+	if config.Checkpoint != nil {
+		config.Checkpoint.save(report)
+	}
+	return &report, nil
+}
+
+// context devolve o ctx do Run, ou Background quando a Config é usada
+// fora dele (pré-aquecimento, -dry-run, -debug-request).
+func (c Config) context() context.Context {
+	if c.ctx == nil {
+		return context.Background()
+	}
+	return c.ctx
+}
+
+// canceled informa se a requisição falhou porque o ctx do Run foi
+// cancelado, e não por causa do servidor.
+func canceled(err error) bool {
+	return errors.Is(err, context.Canceled) || status.Code(err) == codes.Canceled
+}
+
+func executeLoadTest(config Config) (Report, error) {
+	if config.GRPC != nil {
+		return executeGRPCLoadTest(config)
+	}
+	if config.TCP != nil {
+		return executeTCPLoadTest(config), nil
+	}
+	if config.UDP != nil {
+		return executeUDPLoadTest(config), nil
+	}
+	if config.DNSQuery != nil {
+		return executeDNSLoadTest(config), nil
+	}
+	if config.MQTT != nil {
+		return executeMQTTLoadTest(config)
+	}
+	if config.Redis != nil {
+		return executeRedisLoadTest(config), nil
+	}
+
+	// Um único client por execução para que as conexões sejam reaproveitadas
+	client, err := newHTTPClient(config)
+	if err != nil {
+		return Report{}, err
+	}
+	defer client.CloseIdleConnections()
+
+	if config.Range != nil && config.Range.Mode != "fixed" && config.Range.Total == 0 {
+		total, err := discoverContentLength(client, config)
+		if err != nil {
+			return Report{}, err
+		}
+		config.Range.Total = total
+	}
+
+	if config.Prewarm && !config.DisableKeepAlive {
+		opened := prewarmConnections(client, config)
+		slog.Info("connections pre-warmed", "count", opened)
+	}
+
+	var schedule *targetSchedule
+	if config.PreserveTiming && len(config.Targets) > 0 {
+		schedule = newTargetSchedule(config.Targets)
+	}
+
+	return runRequests(config, func(vars templateVars, vu *virtualUser, results chan<- Result) {
+		if schedule != nil {
+			schedule.wait(vars.Seq)
+		}
+		makeRequest(client, config, vars, vu, results)
+	}), nil
+}
+
+// minPipelineBuffer é o menor buffer do canal de resultados.
+const minPipelineBuffer = 256
+
+// runRequests dispara config.Requests chamadas de `do`, no máximo
+// config.Concurrency ao mesmo tempo, e agrega os resultados.
+func runRequests(config Config, do func(templateVars, *virtualUser, chan<- Result)) Report {
+	// O coletor agrega cada resultado ao recebê-lo; o canal só absorve
+	// rajadas, então o buffer acompanha a concorrência e não o -requests
+	results := make(chan Result, min(config.Requests, max(config.Concurrency*2, minPipelineBuffer)))
+	start := time.Now()
+	// Contador único do teste: cada requisição recebe o próximo seq, qualquer
+	// que seja o worker que a envia
+	var seq atomic.Int64
+	seq.Store(config.SeqStart)
+	var firstSend atomic.Int64 // UnixNano do envio da primeira requisição
+
+	// Mostrar progresso: os workers só incrementam o contador, lido a cada
+	// atualização da tela até finished ser fechado
+	var completed atomic.Int64
+	finished := make(chan struct{})
+	var tuiDone chan struct{}
+	switch {
+	case config.TUI:
+		tuiDone = make(chan struct{})
+		go runTUI(config, &completed, finished, tuiDone)
+	case config.Quiet:
+	default:
+		// A taxa, os erros e os percentis vêm das estatísticas ao vivo
+		if config.Live == nil {
+			config.Live = NewLiveStats(config.Requests)
+		}
+		if config.Progress == "json" {
+			go showJSONProgress(config.Live, finished)
+		} else {
+			go showProgress(config.Live, finished, StdoutIsTerminal())
+		}
+	}
+
+	// O -max-duration para o teste como o botão do painel: as requisições
+	// em andamento terminam, limitadas pelo -timeout
+	var maxDurationReached atomic.Bool
+	if config.MaxDuration > 0 {
+		if config.Stop == nil {
+			config.Stop = NewStopSignal()
+		}
+		timer := time.AfterFunc(config.MaxDuration, func() {
+			maxDurationReached.Store(true)
+			config.Stop.Stop()
+		})
+		defer timer.Stop()
+	}
+
+	// Agregar enquanto as requisições são disparadas, para o painel ao vivo
+	collected := make(chan Report, 1)
+	go func() {
+		collected <- collectResults(config, results, start, &firstSend)
+	}()
+
+	// Cada worker é um usuário virtual com sua própria sessão
+	pool := newWorkerPool(config.Requests, config.Concurrency, func(vu *virtualUser) {
+		if !config.Stop.Stopped() {
+			if firstSend.Load() == 0 {
+				firstSend.CompareAndSwap(0, time.Now().UnixNano())
+			}
+			do(config.templateVars(seq.Add(1), vu.ID), vu, results)
+		}
+		completed.Add(1)
+	})
+	go func() {
+		<-pool.done
+		close(results)
+		close(finished)
+	}()
+
+	controlDone := make(chan struct{})
+	var changes chan []ConcurrencyChange
+	if config.Control != nil {
+		changes = make(chan []ConcurrencyChange, 1)
+		go func() {
+			changes <- config.Control.run(pool, config.Concurrency, config.Requests, start, controlDone)
+		}()
+	}
+
+	report := <-collected
+	close(controlDone)
+	if changes != nil {
+		report.ConcurrencyChanges = <-changes
+	}
+	if tuiDone != nil {
+		<-tuiDone
+	}
+	report.Stopped = config.Stop.Stopped()
+	report.MaxDurationReached = report.Stopped && maxDurationReached.Load()
+	if config.Live != nil {
+		config.Live.finish()
+	}
+	// Linha final, com todos os resultados já agregados
+	switch {
+	case config.TUI || config.Quiet:
+	case config.Progress == "json":
+		WriteProgressLine(config.Live.progressUpdate(true))
+	default:
+		PrintProgress(config.Live.progressUpdate(true), StdoutIsTerminal())
+	}
+	return report
+}
+
+func makeRequest(client *http.Client, config Config, vars templateVars, vu *virtualUser, results chan<- Result) {
+	if len(config.Targets) > 0 {
+		config = config.Targets[(vars.Seq-1)%int64(len(config.Targets))].apply(config)
+	}
+	req, sent, loggedBody, err := buildRequest(config, vars)
+	if err != nil {
+		results <- Result{
+			Error:     err,
+			ErrorKind: ClassifyError(err),
+		}
+		return
+	}
+	if config.Login != nil {
+		if err := vu.ensureSession(client, config.Login); err != nil {
+			results <- Result{
+				Error:     err,
+				ErrorKind: ClassifyError(err),
+			}
+			return
+		}
+		config.Login.apply(req, vu.token)
+	}
+
+	conditional := config.CacheValidators != nil && config.CacheValidators.apply(req)
+	var rangeStart, rangeEnd int64
+	if config.Range != nil {
+		rangeStart, rangeEnd = config.Range.window(vars)
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-%d", rangeStart, rangeEnd))
+	}
+
+	tracer := &requestTracer{}
+	ctx, redirects := withRedirectCounter(req.Context())
+	var sse *sseSession
+	if config.SSE != nil {
+		ctx, sse = newSSESession(ctx, config.Timeout)
+	}
+	req = req.WithContext(httptrace.WithClientTrace(ctx, tracer.clientTrace()))
+
+	start := time.Now()
+	resp, err := client.Do(req)
+	duration := time.Since(start)
+
+	if err != nil {
+		kind := tracer.refine(ClassifyError(err))
+		if config.RequestLog != nil {
+			config.RequestLog.write(req, loggedBody, start, 0, kind, duration, err)
+		}
+		results <- Result{
+			ErrorKind:  kind,
+			Error:      err,
+			Duration:   duration,
+			Phases:     tracer.finish(),
+			RemoteAddr: tracer.RemoteAddr(),
+			ConnReused: tracer.Reused(),
+			Redirects:  *redirects,
+		}
+		return
+	}
+
+	defer resp.Body.Close()
+	// Sessão expirada: o próximo uso deste VU refaz o login
+	if config.Login != nil && resp.StatusCode == http.StatusUnauthorized {
+		vu.invalidate()
+	}
+	if config.CacheValidators != nil {
+		config.CacheValidators.update(req, resp)
+	}
+	var rangeOutcome RangeOutcome
+	if config.Range != nil {
+		rangeOutcome = checkRange(resp, rangeStart, rangeEnd)
+	}
+	var tlsVersion string
+	if resp.TLS != nil {
+		tlsVersion = tls.VersionName(resp.TLS.Version)
+	}
+	var expect ContinueOutcome
+	if config.ExpectContinue {
+		expect = continueOutcome(tracer.Got100Continue(), sent.n)
+	}
+	var (
+		wire, decoded int64
+		compressed    bool
+		events        *sseResult
+	)
+	if sse != nil {
+		var result sseResult
+		result, err = sse.read(resp.Body, start, config.SSE.Duration)
+		events = &result
+		duration = time.Since(start)
+	} else {
+		// Ler o corpo completo para medir o tempo de transferência
+		wire, decoded, compressed, err = readBody(resp, io.Discard)
+	}
+	kind := ClassifyError(err)
+	// Fora do -expect-status a resposta conta como erro, mas sem tipo de
+	// falha de transporte: o servidor respondeu
+	if err == nil && config.ExpectStatus != nil && !config.ExpectStatus.Contains(resp.StatusCode) {
+		err = fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	if config.RequestLog != nil {
+		config.RequestLog.write(req, loggedBody, start, resp.StatusCode, kind, duration, err)
+	}
+	results <- Result{
+		StatusCode:   resp.StatusCode,
+		Duration:     duration,
+		Error:        err,
+		ErrorKind:    kind,
+		Phases:       tracer.finish(),
+		Proto:        resp.Proto,
+		TLSVersion:   tlsVersion,
+		RemoteAddr:   tracer.RemoteAddr(),
+		ConnReused:   tracer.Reused(),
+		BytesRead:    wire,
+		BytesDecoded: decoded,
+		Compressed:   compressed,
+		Redirects:    *redirects,
+		BytesSent:    sent.n,
+		Continue:     expect,
+		Trailers:     resp.Trailer,
+		Conditional:  conditional,
+		Range:        rangeOutcome,
+		SSE:          events,
+	}
+}
+
+// buildRequest monta a requisição HTTP de vars, com corpo, URL, headers e
+// autenticação. A sessão do -login-url é aplicada depois, por usuário virtual.
+func buildRequest(config Config, vars templateVars) (*http.Request, *countingReader, *string, error) {
+	body, err := newRequestBody(config, vars)
+	var loggedBody *string
+	if err == nil && config.RequestLog != nil {
+		loggedBody, err = captureBody(&body)
+	}
+	if err == nil && config.CompressBody {
+		body, err = gzipBody(body)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	// Contar os bytes efetivamente enviados (após compressão)
+	sent := &countingReader{r: body.reader}
+	body.reader = sent
+
+	url := config.URL
+	if config.URLGlob != nil {
+		// As requisições percorrem as URLs expandidas em ordem
+		url = config.URLGlob.expand(vars.Seq - 1)
+		if config.URLTemplate != nil {
+			var tmpl *template.Template
+			if tmpl, err = parseTemplate("url", url); err == nil {
+				url, err = renderTemplate(tmpl, vars)
+			}
+		}
+	} else if config.URLTemplate != nil {
+		url, err = renderTemplate(config.URLTemplate, vars)
+	}
+	if err == nil && len(config.Query) > 0 {
+		url, err = appendQuery(url, config.Query, vars)
+	}
+	var req *http.Request
+	if err == nil {
+		req, err = http.NewRequestWithContext(config.context(), config.Method, url, body.reader)
+	}
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	req.ContentLength = body.length
+	if config.Chunked {
+		req.Body = &chunkedReader{r: req.Body, size: config.ChunkSize, delay: config.ChunkDelay}
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	}
+	if len(config.Trailers) > 0 {
+		// No HTTP/1.1 trailers só existem em corpos chunked
+		req.Trailer = config.Trailers.Clone()
+		req.ContentLength = -1
+		req.TransferEncoding = []string{"chunked"}
+	}
+
+	// Adicionar headers
+	if body.contentType != "" {
+		req.Header.Set("Content-Type", body.contentType)
+	}
+	if config.CompressBody {
+		req.Header.Set("Content-Encoding", "gzip")
+	}
+	if config.ExpectContinue {
+		req.Header.Set("Expect", "100-continue")
+	}
+	// Em ordem fixa: os templates dividem o gerador da requisição, e a
+	// ordem aleatória do map mudaria os valores de um mesmo -seed
+	for _, k := range slices.Sorted(maps.Keys(config.Headers)) {
+		v := config.Headers[k]
+		if tmpl, ok := config.HeaderTemplates[k]; ok {
+			rendered, err := renderTemplate(tmpl, vars)
+			if err != nil {
+				return nil, nil, nil, err
+			}
+			v = rendered
+		}
+		req.Header.Add(k, v)
+	}
+	for _, rotation := range config.HeaderRotations {
+		req.Header.Set(rotation.Name, rotation.value(vars))
+	}
+	if err := config.LiveHeaders.apply(req.Header, vars); err != nil {
+		return nil, nil, nil, err
+	}
+	if config.HostHeader != "" {
+		req.Host = config.HostHeader
+	}
+	if err := applyAuth(req, config); err != nil {
+		return nil, nil, nil, err
+	}
+	if encoding, _ := acceptEncoding(config.Compression); encoding != "" && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", encoding)
+	}
+	if config.SSE != nil && req.Header.Get("Accept") == "" {
+		req.Header.Set("Accept", "text/event-stream")
+	}
+	return req, sent, loggedBody, nil
+}
+
+// collectResults agrega os resultados até o canal ser fechado, alimentando
+// o painel ao vivo (config.Live) e os checkpoints (config.Checkpoint).
+func collectResults(config Config, results chan Result, startTime time.Time, firstSend *atomic.Int64) Report {
+	live, checkpoint, rules := config.Live, config.Checkpoint, config.ErrorRules
+	report := Report{
+		StatusCodes:  make(map[int]int),
+		ErrorKinds:   make(map[ErrorKind]int),
+		Latencies:    NewHistogram(),
+		MinDuration:  time.Hour,
+		ErrorDetails: make(map[string]ErrorDetail),
+		Protocols:    make(map[string]int),
+		TLSVersions:  make(map[string]int),
+		IPFamilies:   make(map[string]int),
+		PerIP:        make(map[string]*IPStats),
+	}
+
+	var lastResponse time.Time
+	for result := range results {
+		lastResponse = time.Now()
+		if canceled(result.Error) {
+			continue // abortada no meio pelo ctx do Run
+		}
+		report.TotalRequests++
+		if live != nil {
+			live.add(result)
+		}
+
+		// Incrementar contagem do código de status; falhas sem resposta
+		// contam pelo tipo
+		if result.hasStatus() {
+			report.StatusCodes[result.StatusCode]++
+		}
+		if result.ErrorKind != ErrorNone {
+			report.ErrorKinds[result.ErrorKind]++
+		}
+
+		if rules != nil {
+			if name := rules.Categorize(result, config.ExpectStatus); name != "" {
+				if report.ErrorCategories == nil {
+					report.ErrorCategories = make(map[string]ErrorCategory)
+				}
+				category := report.ErrorCategories[name]
+				category.Severity = rules.Categories[name].Severity
+				category.Count++
+				report.ErrorCategories[name] = category
+			}
+		}
+
+		// Registrar erro se existir
+		if result.Error != nil {
+			report.Errors++
+			errMsg := result.Error.Error()
+			detail, exists := report.ErrorDetails[errMsg]
+			if !exists {
+				detail = ErrorDetail{
+					Message: errMsg,
+					Code:    result.StatusCode,
+					Kind:    result.ErrorKind,
+				}
+			}
+			detail.Count++
+			report.ErrorDetails[errMsg] = detail
+		}
+
+		// Protocolo negociado
+		if result.Proto != "" {
+			report.Protocols[result.Proto]++
+		}
+		if result.TLSVersion != "" {
+			report.TLSVersions[result.TLSVersion]++
+		}
+		if family := addressFamily(result.RemoteAddr); family != "" {
+			report.IPFamilies[family]++
+		}
+
+		// Bytes enviados e recebidos
+		report.BytesSent += result.BytesSent
+
+		report.BytesRead += result.BytesRead
+		report.BytesDecoded += result.BytesDecoded
+		if result.Compressed {
+			report.Compressed++
+		}
+
+		report.Redirects += result.Redirects
+		report.Continue.add(result.Continue)
+		report.Trailers.add(result.Trailers)
+		report.Cache.add(result)
+		report.Range.add(result.Range)
+		report.Streams.add(result)
+		report.SSE.add(result.SSE)
+		report.TCP.add(result)
+		report.UDP.add(result)
+		report.DNS.add(result)
+		report.Redis.add(result)
+
+		// Latência e erros por IP de destino
+		if ip := remoteIP(result); ip != "" {
+			stats, ok := report.PerIP[ip]
+			if !ok {
+				stats = &IPStats{}
+				report.PerIP[ip] = stats
+			}
+			stats.Requests++
+			if result.Error != nil {
+				stats.Errors++
+			} else {
+				stats.Latency.add(result.Duration)
+			}
+		}
+
+		// Conexões novas versus reaproveitadas
+		if result.RemoteAddr != "" {
+			if result.ConnReused {
+				report.ReusedConns++
+			} else {
+				report.NewConns++
+			}
+		}
+
+		// Agregar fases da requisição
+		report.Phases.add(result.Phases)
+
+		// Processar duração
+		if result.Duration > 0 {
+			report.Latencies.Add(result.Duration)
+			if result.Duration < report.MinDuration {
+				report.MinDuration = result.Duration
+			}
+			if result.Duration > report.MaxDuration {
+				report.MaxDuration = result.Duration
+			}
+		}
+
+		if checkpoint != nil && checkpoint.due() {
+			report.summarize(startTime, firstSend, lastResponse)
+			checkpoint.save(report)
+		}
+	}
+
+	report.summarize(startTime, firstSend, lastResponse)
+	return report
+}
+
+// Window é o tempo sobre o qual a vazão é calculada: a janela de envio ou,
+// em relatórios sem ela, o tempo total.
+func (report Report) Window() time.Duration {
+	if report.SendWindow > 0 {
+		return report.SendWindow
+	}
+	return report.TotalTime
+}
+
+// HasLatency informa se há latências para mostrar. Sem nenhuma resposta do
+// servidor as durações são as das falhas (conexão recusada, timeout) e não
+// descrevem o alvo, então mínimo, máximo e média ficam como n/a.
+func (report Report) HasLatency() bool {
+	return report.Samples() > 0 && report.Responded()
+}
+
+// Samples é o número de durações medidas.
+func (report Report) Samples() int64 {
+	if report.Latencies != nil {
+		return report.Latencies.Count
+	}
+	return int64(len(report.Durations))
+}
+
+// Percentile calcula o percentil pelo histograma ou, em relatórios antigos,
+// pelas durações guardadas.
+func (report Report) Percentile(percentile float64) time.Duration {
+	if report.Latencies != nil {
+		return report.Latencies.Percentile(percentile)
+	}
+	return Percentile(report.Durations, percentile)
+}
+
+// Responded informa se alguma requisição teve resposta do servidor, mesmo
+// que com um código fora do -expect-status. As falhas sem resposta não
+// entram em StatusCodes; relatórios anteriores aos ErrorKinds contavam essas
+// falhas com códigos inventados, e para eles vale haver algum sucesso.
+func (report Report) Responded() bool {
+	if report.ErrorKinds == nil {
+		return report.Errors < report.TotalRequests
+	}
+	for _, count := range report.StatusCodes {
+		if count > 0 {
+			return true
+		}
+	}
+	return false
+}
+
+// MarshalJSON grava null nas latências quando não há nenhuma medida, em
+// vez das durações das falhas ou do time.Hour usado como mínimo inicial. O
+// relatório em si guarda os valores acumulados, que os checkpoints e o Merge
+// continuam somando.
+func (report Report) MarshalJSON() ([]byte, error) {
+	type plain Report
+	if report.HasLatency() {
+		return json.Marshal(plain(report))
+	}
+	return json.Marshal(struct {
+		plain
+		MinDuration  *time.Duration
+		MaxDuration  *time.Duration
+		AvgDuration  *time.Duration
+		StdDeviation *time.Duration
+	}{plain: plain(report)})
+}
+
+// summarize calcula as métricas derivadas dos totais agregados até agora.
+func (report *Report) summarize(startTime time.Time, firstSend *atomic.Int64, lastResponse time.Time) {
+	report.TotalTime = time.Since(startTime)
+	if first := firstSend.Load(); first != 0 && lastResponse.UnixNano() > first {
+		report.SendWindow = lastResponse.Sub(time.Unix(0, first))
+	}
+
+	report.AvgDuration = report.Latencies.Mean()
+
+	if report.TotalRequests > 0 {
+		report.AvgRedirects = float64(report.Redirects) / float64(report.TotalRequests)
+	}
+
+	// Vazão de upload considerando apenas o tempo de envio dos corpos
+	upload := report.Phases.RequestUpload
+	if uploadTime := upload.Avg * time.Duration(upload.Count); uploadTime > 0 {
+		report.UploadRate = float64(report.BytesSent) / uploadTime.Seconds()
+	}
+
+	// Calcular RPS sobre a janela de envio, sem a preparação e a agregação
+	report.RPS = float64(report.TotalRequests) / report.Window().Seconds()
+	report.StdDeviation = report.Latencies.StdDeviation()
+}
+
+func Percentile(durations []time.Duration, percentile float64) time.Duration {
+	if len(durations) == 0 {
+		return 0
+	}
+
+	sort.Slice(durations, func(i, j int) bool {
+		return durations[i] < durations[j]
+	})
+	index := int(float64(len(durations)) * percentile / 100)
+	if index >= len(durations) {
+		index = len(durations) - 1
+	}
+	return durations[index]
+}
@@ -0,0 +1,40 @@
+package loadtest
+
+import (
+	"errors"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// Um checkpoint gravado antes do primeiro sucesso não pode zerar o mínimo
+// acumulado, que as respostas seguintes nunca mais baixariam.
+func TestCheckpointBeforeFirstSuccessKeepsMinimum(t *testing.T) {
+	checkpoint := &Checkpointer{Path: filepath.Join(t.TempDir(), "checkpoint.json")}
+	results := make(chan Result, 3)
+	results <- Result{Duration: 5 * time.Millisecond, Error: errors.New("connection refused"), ErrorKind: ErrorConnRefused}
+	results <- Result{StatusCode: 200, Duration: 20 * time.Millisecond}
+	results <- Result{StatusCode: 200, Duration: 10 * time.Millisecond}
+	close(results)
+
+	var firstSend atomic.Int64
+	report := collectResults(Config{Checkpoint: checkpoint}, results, time.Now(), &firstSend)
+
+	if !report.HasLatency() {
+		t.Fatal("HasLatency() = false with two successful requests")
+	}
+	if report.MinDuration != 5*time.Millisecond {
+		t.Errorf("MinDuration = %v, want 5ms", report.MinDuration)
+	}
+	if report.MaxDuration != 20*time.Millisecond {
+		t.Errorf("MaxDuration = %v, want 20ms", report.MaxDuration)
+	}
+	saved, err := ReadCheckpoint(checkpoint.Path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := saved.Segments[0].MinDuration; got != 5*time.Millisecond {
+		t.Errorf("checkpoint MinDuration = %v, want 5ms", got)
+	}
+}
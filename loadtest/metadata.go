@@ -0,0 +1,80 @@
+package loadtest
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"sort"
+	"strings"
+)
+
+// Metadata identifica a execução: rótulos passados com -label e dados
+// capturados do ambiente (commit e branch do git, hostname).
+type Metadata struct {
+	Labels    map[string]string
+	GitCommit string
+	GitBranch string
+	Hostname  string
+}
+
+// NewMetadata captura o commit, a branch e o hostname da máquina atual.
+func NewMetadata(labels map[string]string) Metadata {
+	hostname, _ := os.Hostname()
+	return Metadata{
+		Labels:    labels,
+		GitCommit: gitInfo([]string{"GITHUB_SHA", "CI_COMMIT_SHA", "BUILD_SOURCEVERSION", "GIT_COMMIT"}, "rev-parse", "HEAD"),
+		GitBranch: gitInfo([]string{"GITHUB_HEAD_REF", "GITHUB_REF_NAME", "CI_COMMIT_REF_NAME", "BUILD_SOURCEBRANCHNAME", "GIT_BRANCH"}, "rev-parse", "--abbrev-ref", "HEAD"),
+		Hostname:  hostname,
+	}
+}
+
+// gitInfo vem das variáveis dos CIs mais comuns ou do repositório atual.
+func gitInfo(envs []string, args ...string) string {
+	for _, env := range envs {
+		if value := os.Getenv(env); value != "" {
+			return value
+		}
+	}
+	out, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(out))
+}
+
+// parseLabels interpreta os -label no formato 'chave=valor'.
+func parseLabels(values []string) (map[string]string, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	labels := make(map[string]string, len(values))
+	for _, value := range values {
+		key, val, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid -label %q, expected key=value", value)
+		}
+		labels[key] = strings.TrimSpace(val)
+	}
+	return labels, nil
+}
+
+// List devolve os pares chave/valor na ordem usada pelos relatórios: dados
+// do ambiente primeiro e depois os rótulos em ordem alfabética.
+func (m Metadata) List() [][2]string {
+	var items [][2]string
+	for _, item := range [][2]string{{"hostname", m.Hostname}, {"git_commit", m.GitCommit}, {"git_branch", m.GitBranch}} {
+		if item[1] != "" {
+			items = append(items, item)
+		}
+	}
+	keys := make([]string, 0, len(m.Labels))
+	for key := range m.Labels {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		items = append(items, [2]string{key, m.Labels[key]})
+	}
+	return items
+}
@@ -0,0 +1,196 @@
+package loadtest
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"text/template"
+	"time"
+
+	mqtt "github.com/eclipse/paho.mqtt.golang"
+)
+
+// MQTTOptions configura o modo MQTT: -concurrency clientes ficam conectados
+// durante o teste e cada requisição é uma publicação.
+type MQTTOptions struct {
+	Topic     string
+	QoS       byte
+	Rate      int    // publicações por segundo no total, 0 = sem limite
+	Subscribe string // tópico assinado por todos os clientes
+	topic     *template.Template
+}
+
+func newMQTTOptions(topic string, qos, rate int, subscribe string) (*MQTTOptions, error) {
+	if topic == "" {
+		return nil, fmt.Errorf("-mqtt-topic is required in MQTT mode")
+	}
+	if qos < 0 || qos > 2 {
+		return nil, fmt.Errorf("invalid -mqtt-qos %d (use 0, 1 or 2)", qos)
+	}
+	if rate < 0 {
+		return nil, fmt.Errorf("-mqtt-rate must not be negative")
+	}
+	tmpl, err := parseTemplate("mqtt-topic", topic)
+	if err != nil {
+		return nil, err
+	}
+	return &MQTTOptions{Topic: topic, QoS: byte(qos), Rate: rate, Subscribe: subscribe, topic: tmpl}, nil
+}
+
+type MQTTStats struct {
+	Clients       int
+	ConnectFailed int
+	Connect       PhaseStats
+	Disconnects   int // conexões perdidas durante o teste
+	Received      int // mensagens recebidas via -mqtt-subscribe
+}
+
+var errMQTTNotConnected = errors.New("MQTT client not connected")
+
+// brokerURL converte mqtt:// e mqtts:// para os esquemas do paho.
+func brokerURL(raw string) (string, error) {
+	u, err := url.Parse(raw)
+	if err != nil || u.Host == "" {
+		return "", fmt.Errorf("invalid MQTT broker %q (use mqtt://host:port or mqtts://host:port)", raw)
+	}
+	switch u.Scheme {
+	case "mqtt":
+		return "tcp://" + u.Host, nil
+	case "mqtts":
+		return "ssl://" + u.Host, nil
+	default:
+		return "", fmt.Errorf("invalid MQTT broker %q (use mqtt://host:port or mqtts://host:port)", raw)
+	}
+}
+
+func executeMQTTLoadTest(config Config) (Report, error) {
+	broker, err := brokerURL(config.URL)
+	if err != nil {
+		return Report{}, err
+	}
+
+	var stats MQTTStats
+	var disconnects, received atomic.Int64
+	clients := make([]mqtt.Client, config.Concurrency)
+	connectTimes := make([]time.Duration, config.Concurrency)
+
+	// Conectar todos os clientes antes da medição
+	var wg sync.WaitGroup
+	for i := range clients {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			opts := mqtt.NewClientOptions().
+				AddBroker(broker).
+				SetClientID(fmt.Sprintf("loadtest-%d-%d", time.Now().UnixNano(), i)).
+				SetConnectTimeout(config.Timeout).
+				SetAutoReconnect(false).
+				SetTLSConfig(config.TLSConfig).
+				SetConnectionLostHandler(func(mqtt.Client, error) { disconnects.Add(1) })
+			if config.BasicAuth != nil {
+				opts.SetUsername(config.BasicAuth.Username).SetPassword(config.BasicAuth.Password)
+			}
+
+			client := mqtt.NewClient(opts)
+			start := time.Now()
+			token := client.Connect()
+			if !token.WaitTimeout(config.Timeout) || token.Error() != nil {
+				return
+			}
+			connectTimes[i] = time.Since(start)
+
+			if config.MQTT.Subscribe != "" {
+				token := client.Subscribe(config.MQTT.Subscribe, config.MQTT.QoS, func(mqtt.Client, mqtt.Message) {
+					received.Add(1)
+				})
+				if !token.WaitTimeout(config.Timeout) || token.Error() != nil {
+					client.Disconnect(0)
+					return
+				}
+			}
+			clients[i] = client
+		}()
+	}
+	wg.Wait()
+
+	for i, client := range clients {
+		stats.Clients++
+		if client == nil {
+			stats.ConnectFailed++
+			continue
+		}
+		stats.Connect.add(connectTimes[i])
+	}
+
+	// Cadência global de publicação (-mqtt-rate)
+	var tick <-chan time.Time
+	if config.MQTT.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(config.MQTT.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	report := runRequests(config, func(vars templateVars, vu *virtualUser, results chan<- Result) {
+		if tick != nil {
+			<-tick
+		}
+		results <- publishMQTT(clients[vu.ID], config, vars)
+	})
+
+	for _, client := range clients {
+		if client != nil {
+			client.Disconnect(250)
+		}
+	}
+
+	stats.Disconnects = int(disconnects.Load())
+	stats.Received = int(received.Load())
+	report.MQTT = stats
+	report.Mode = "mqtt"
+	return report, nil
+}
+
+func publishMQTT(client mqtt.Client, config Config, vars templateVars) Result {
+	fail := func(err error) Result {
+		return Result{ErrorKind: ClassifyError(err), Error: err}
+	}
+	if client == nil || !client.IsConnectionOpen() {
+		return fail(errMQTTNotConnected)
+	}
+
+	topic := config.MQTT.Topic
+	if config.MQTT.topic != nil {
+		rendered, err := renderTemplate(config.MQTT.topic, vars)
+		if err != nil {
+			return fail(err)
+		}
+		topic = rendered
+	}
+	// O payload vem do -body (com templates) ou de um corpo sintético
+	body, err := newRequestBody(config, vars)
+	if err != nil {
+		return fail(err)
+	}
+	payload, err := io.ReadAll(body.reader)
+	if closer, ok := body.reader.(io.Closer); ok {
+		closer.Close()
+	}
+	if err != nil {
+		return fail(err)
+	}
+
+	// Latência até o PUBACK (QoS 1), PUBCOMP (QoS 2) ou a escrita (QoS 0)
+	start := time.Now()
+	token := client.Publish(topic, config.MQTT.QoS, false, payload)
+	if !token.WaitTimeout(config.Timeout) {
+		return Result{Error: errors.New("MQTT publish timed out"), ErrorKind: ErrorTimeout}
+	}
+	duration := time.Since(start)
+	if err := token.Error(); err != nil {
+		return Result{ErrorKind: ClassifyError(err), Duration: duration, Error: err}
+	}
+	return Result{StatusCode: 200, Duration: duration, BytesSent: int64(len(payload))}
+}
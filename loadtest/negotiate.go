@@ -0,0 +1,101 @@
+package loadtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Azure/go-ntlmssp"
+	krbclient "github.com/jcmturner/gokrb5/v8/client"
+	krbconfig "github.com/jcmturner/gokrb5/v8/config"
+	"github.com/jcmturner/gokrb5/v8/keytab"
+	"github.com/jcmturner/gokrb5/v8/spnego"
+)
+
+type NTLMAuth struct {
+	Username string // DOMINIO\usuario ou usuario@dominio
+	Password string
+}
+
+func parseNTLMAuth(value string) (*NTLMAuth, error) {
+	if value == "" {
+		return nil, nil
+	}
+	user, pass, ok := strings.Cut(value, ":")
+	if !ok || user == "" {
+		return nil, fmt.Errorf("invalid -ntlm (use DOMAIN\\user:pass)")
+	}
+	pass, err := resolveSecret(pass)
+	if err != nil {
+		return nil, err
+	}
+	return &NTLMAuth{Username: user, Password: pass}, nil
+}
+
+// ntlmTransport envolve o transporte com o handshake NTLM/Negotiate. O NTLM
+// autentica a conexão, então requisições em conexões keep-alive já
+// autenticadas seguem sem repetir o handshake.
+func ntlmTransport(rt http.RoundTripper) http.RoundTripper {
+	return ntlmssp.Negotiator{RoundTripper: rt}
+}
+
+type KerberosOptions struct {
+	Principal  string // usuario@REALM
+	Password   string
+	Keytab     string
+	ConfigFile string
+	SPN        string
+}
+
+type KerberosAuth struct {
+	client *krbclient.Client
+	spn    string
+}
+
+// newKerberosAuth autentica no KDC antes do teste. Cada requisição recebe um
+// token SPNEGO próprio derivado do ticket de serviço em cache.
+func newKerberosAuth(opts KerberosOptions) (*KerberosAuth, error) {
+	if opts.Principal == "" {
+		return nil, nil
+	}
+
+	user, realm, ok := strings.Cut(opts.Principal, "@")
+	if !ok || user == "" || realm == "" {
+		return nil, fmt.Errorf("invalid -kerberos-principal %q (use user@REALM)", opts.Principal)
+	}
+
+	cfg, err := krbconfig.Load(opts.ConfigFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading krb5 config: %w", err)
+	}
+
+	var cl *krbclient.Client
+	switch {
+	case opts.Keytab != "":
+		kt, err := keytab.Load(opts.Keytab)
+		if err != nil {
+			return nil, fmt.Errorf("loading keytab: %w", err)
+		}
+		cl = krbclient.NewWithKeytab(user, realm, kt, cfg)
+	case opts.Password != "":
+		password, err := resolveSecret(opts.Password)
+		if err != nil {
+			return nil, err
+		}
+		cl = krbclient.NewWithPassword(user, realm, password, cfg)
+	default:
+		return nil, fmt.Errorf("-kerberos-password or -kerberos-keytab is required")
+	}
+
+	if err := cl.Login(); err != nil {
+		return nil, fmt.Errorf("kerberos login: %w", err)
+	}
+	return &KerberosAuth{client: cl, spn: opts.SPN}, nil
+}
+
+func (k *KerberosAuth) apply(req *http.Request) error {
+	if err := spnego.SetSPNEGOHeader(k.client, req, k.spn); err != nil {
+		return fmt.Errorf("building SPNEGO token: %w", err)
+	}
+	return nil
+}
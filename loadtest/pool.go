@@ -0,0 +1,94 @@
+package loadtest
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// workerPool executa as requisições do teste com um worker por usuário
+// virtual. Cada worker tira a próxima requisição de um contador até elas
+// acabarem, então o número de goroutines acompanha a concorrência e não o
+// -requests. O ConcurrencyControl muda a concorrência com add e retire.
+type workerPool struct {
+	total  int64
+	taken  atomic.Int64
+	job    func(vu *virtualUser)
+	retire chan struct{}
+	done   chan struct{} // fechado quando o último worker termina
+
+	mu       sync.Mutex
+	active   int
+	finished bool
+	parked   []*virtualUser // VUs retirados, com a sessão preservada
+	nextID   int
+}
+
+// newWorkerPool inicia workers workers para total chamadas de job.
+func newWorkerPool(total, workers int, job func(vu *virtualUser)) *workerPool {
+	p := &workerPool{
+		total:  int64(total),
+		job:    job,
+		retire: make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	for range workers {
+		p.add()
+	}
+	return p
+}
+
+// add inicia mais um worker, reaproveitando um VU retirado antes de criar
+// um novo. Devolve false se as requisições já acabaram.
+func (p *workerPool) add() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.finished {
+		return false
+	}
+	var vu *virtualUser
+	if n := len(p.parked); n > 0 {
+		vu, p.parked = p.parked[n-1], p.parked[:n-1]
+	} else {
+		vu = &virtualUser{ID: p.nextID}
+		p.nextID++
+	}
+	p.active++
+	go p.work(vu)
+	return true
+}
+
+func (p *workerPool) work(vu *virtualUser) {
+	for {
+		select {
+		case <-p.retire:
+			if p.park(vu) {
+				return
+			}
+		default:
+		}
+		if p.taken.Add(1) > p.total {
+			break
+		}
+		p.job(vu)
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.active--
+	if p.active == 0 {
+		p.finished = true
+		close(p.done)
+	}
+}
+
+// park retira o worker do VU. O último worker nunca é retirado, para que as
+// requisições restantes ainda sejam executadas.
+func (p *workerPool) park(vu *virtualUser) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.active == 1 {
+		return false
+	}
+	p.active--
+	p.parked = append(p.parked, vu)
+	return true
+}
@@ -0,0 +1,277 @@
+package loadtest
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// Estruturas do formato Postman Collection v2.1 usadas na conversão.
+type postmanCollection struct {
+	Info     struct{ Name string } `json:"info"`
+	Item     []postmanItem         `json:"item"`
+	Variable []postmanVariable     `json:"variable"`
+	Auth     *postmanAuth          `json:"auth"`
+}
+
+type postmanItem struct {
+	Name    string          `json:"name"`
+	Item    []postmanItem   `json:"item"` // pastas
+	Request *postmanRequest `json:"request"`
+	Auth    *postmanAuth    `json:"auth"`
+}
+
+type postmanRequest struct {
+	Method string            `json:"method"`
+	Header []postmanVariable `json:"header"`
+	URL    json.RawMessage   `json:"url"` // string ou objeto com "raw"
+	Body   *struct {
+		Mode       string            `json:"mode"`
+		Raw        string            `json:"raw"`
+		URLEncoded []postmanVariable `json:"urlencoded"`
+		Options    struct {
+			Raw struct{ Language string } `json:"raw"`
+		} `json:"options"`
+	} `json:"body"`
+	Auth *postmanAuth `json:"auth"`
+}
+
+type postmanVariable struct {
+	Key      string `json:"key"`
+	Value    string `json:"value"`
+	Disabled bool   `json:"disabled"`
+	Enabled  *bool  `json:"enabled"` // usado nos arquivos de ambiente
+}
+
+type postmanAuth struct {
+	Type   string            `json:"type"`
+	Bearer []postmanVariable `json:"bearer"`
+	Basic  []postmanVariable `json:"basic"`
+	APIKey []postmanVariable `json:"apikey"`
+}
+
+type postmanEnvironment struct {
+	Values []postmanVariable `json:"values"`
+}
+
+// Variáveis dinâmicas do Postman com equivalente nas funções de template.
+var postmanDynamicVars = map[string]string{
+	"$guid":              "{{uuidv4}}",
+	"$randomUUID":        "{{uuidv4}}",
+	"$timestamp":         "{{timestamp}}",
+	"$randomInt":         "{{randInt 0 1000}}",
+	"$randomFirstName":   "{{fakeFirstName}}",
+	"$randomLastName":    "{{fakeLastName}}",
+	"$randomFullName":    "{{fakeName}}",
+	"$randomEmail":       "{{fakeEmail}}",
+	"$randomPhoneNumber": "{{fakePhone}}",
+	"$randomLoremWords":  "{{loremWords 3}}",
+}
+
+var postmanVarPattern = regexp.MustCompile(`{{\s*([^{}]+?)\s*}}`)
+
+// loadPostmanTargets converte uma coleção (e opcionalmente um ambiente)
+// em alvos. Variáveis do ambiente têm precedência sobre as da coleção.
+func loadPostmanTargets(collectionPath, environmentPath string) ([]Target, error) {
+	data, err := os.ReadFile(collectionPath)
+	if err != nil {
+		return nil, fmt.Errorf("reading Postman collection: %w", err)
+	}
+	var collection postmanCollection
+	if err := json.Unmarshal(data, &collection); err != nil {
+		return nil, fmt.Errorf("parsing Postman collection: %w", err)
+	}
+
+	vars := make(map[string]string)
+	for _, v := range collection.Variable {
+		if !v.Disabled {
+			vars[v.Key] = v.Value
+		}
+	}
+	if environmentPath != "" {
+		data, err := os.ReadFile(environmentPath)
+		if err != nil {
+			return nil, fmt.Errorf("reading Postman environment: %w", err)
+		}
+		var env postmanEnvironment
+		if err := json.Unmarshal(data, &env); err != nil {
+			return nil, fmt.Errorf("parsing Postman environment: %w", err)
+		}
+		for _, v := range env.Values {
+			if v.Enabled == nil || *v.Enabled {
+				vars[v.Key] = v.Value
+			}
+		}
+	}
+
+	conv := postmanConverter{vars: vars, unresolved: make(map[string]bool)}
+	conv.walk(collection.Item, "", collection.Auth)
+	if conv.err != nil {
+		return nil, conv.err
+	}
+	if len(conv.unresolved) > 0 {
+		names := make([]string, 0, len(conv.unresolved))
+		for name := range conv.unresolved {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		return nil, fmt.Errorf("unresolved Postman variables: %s (pass an environment with -postman-env)", strings.Join(names, ", "))
+	}
+	if len(conv.targets) == 0 {
+		return nil, fmt.Errorf("Postman collection %s has no requests", collectionPath)
+	}
+	return conv.targets, nil
+}
+
+type postmanConverter struct {
+	vars       map[string]string
+	unresolved map[string]bool
+	targets    []Target
+	err        error
+}
+
+func (c *postmanConverter) walk(items []postmanItem, prefix string, auth *postmanAuth) {
+	for _, item := range items {
+		if c.err != nil {
+			return
+		}
+		name := item.Name
+		if prefix != "" {
+			name = prefix + "/" + item.Name
+		}
+		// A autenticação é herdada das pastas e da coleção
+		itemAuth := auth
+		if item.Auth != nil {
+			itemAuth = item.Auth
+		}
+		if item.Request == nil {
+			c.walk(item.Item, name, itemAuth)
+			continue
+		}
+		if item.Request.Auth != nil {
+			itemAuth = item.Request.Auth
+		}
+		target, err := c.convert(name, item.Request, itemAuth)
+		if err != nil {
+			c.err = err
+			return
+		}
+		c.targets = append(c.targets, target)
+	}
+}
+
+func (c *postmanConverter) convert(name string, req *postmanRequest, auth *postmanAuth) (Target, error) {
+	target := Target{Name: name, Method: req.Method, Headers: make(map[string]string)}
+
+	var rawURL string
+	if err := json.Unmarshal(req.URL, &rawURL); err != nil {
+		var obj struct{ Raw string }
+		if err := json.Unmarshal(req.URL, &obj); err != nil {
+			return Target{}, fmt.Errorf("request %q: invalid URL", name)
+		}
+		rawURL = obj.Raw
+	}
+	target.URL = c.resolve(rawURL)
+
+	for _, h := range req.Header {
+		if !h.Disabled {
+			target.Headers[h.Key] = c.resolve(h.Value)
+		}
+	}
+
+	if body := req.Body; body != nil {
+		switch body.Mode {
+		case "", "none":
+		case "raw":
+			target.Body = c.resolve(body.Raw)
+			if body.Options.Raw.Language == "json" && !hasHeader(target.Headers, "Content-Type") {
+				target.Headers["Content-Type"] = "application/json"
+			}
+		case "urlencoded":
+			form := url.Values{}
+			for _, field := range body.URLEncoded {
+				if !field.Disabled {
+					form.Add(field.Key, c.resolve(field.Value))
+				}
+			}
+			target.Body = form.Encode()
+			if !hasHeader(target.Headers, "Content-Type") {
+				target.Headers["Content-Type"] = "application/x-www-form-urlencoded"
+			}
+		default:
+			return Target{}, fmt.Errorf("request %q: body mode %q is not supported", name, body.Mode)
+		}
+	}
+
+	if auth != nil && !hasHeader(target.Headers, "Authorization") {
+		switch auth.Type {
+		case "bearer":
+			target.Headers["Authorization"] = "Bearer " + c.resolve(postmanParam(auth.Bearer, "token"))
+		case "basic":
+			credentials := c.resolve(postmanParam(auth.Basic, "username")) + ":" + c.resolve(postmanParam(auth.Basic, "password"))
+			target.Headers["Authorization"] = "Basic " + base64.StdEncoding.EncodeToString([]byte(credentials))
+		case "apikey":
+			if postmanParam(auth.APIKey, "in") != "query" {
+				target.Headers[c.resolve(postmanParam(auth.APIKey, "key"))] = c.resolve(postmanParam(auth.APIKey, "value"))
+			}
+		}
+	}
+	if len(target.Headers) == 0 {
+		target.Headers = nil
+	}
+	return target, nil
+}
+
+// resolve substitui {{variavel}} pelos valores do ambiente/coleção e as
+// variáveis dinâmicas pelas funções de template equivalentes.
+func (c *postmanConverter) resolve(s string) string {
+	// Valores podem referenciar outras variáveis; limitar a profundidade
+	for range 10 {
+		if !postmanVarPattern.MatchString(s) {
+			break
+		}
+		changed := false
+		s = postmanVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+			name := postmanVarPattern.FindStringSubmatch(match)[1]
+			if value, ok := c.vars[name]; ok {
+				changed = true
+				return value
+			}
+			return match
+		})
+		if !changed {
+			break
+		}
+	}
+	return postmanVarPattern.ReplaceAllStringFunc(s, func(match string) string {
+		name := postmanVarPattern.FindStringSubmatch(match)[1]
+		if replacement, ok := postmanDynamicVars[name]; ok {
+			return replacement
+		}
+		c.unresolved[name] = true
+		return match
+	})
+}
+
+func postmanParam(params []postmanVariable, key string) string {
+	for _, p := range params {
+		if p.Key == key {
+			return p.Value
+		}
+	}
+	return ""
+}
+
+func hasHeader(headers map[string]string, name string) bool {
+	for k := range headers {
+		if strings.EqualFold(k, name) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,57 @@
+package loadtest
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptrace"
+	"sync"
+	"sync/atomic"
+)
+
+// prewarmConnections abre até `Concurrency` conexões keep-alive (TCP+TLS)
+// antes da medição, disparando requisições GET simultâneas.
+func prewarmConnections(client *http.Client, config Config) int {
+	var opened atomic.Int32
+	var wg, inFlight sync.WaitGroup
+	start := make(chan struct{})
+
+	for i := 0; i < config.Concurrency; i++ {
+		wg.Add(1)
+		inFlight.Add(1)
+		go func() {
+			defer wg.Done()
+			req, err := http.NewRequest(http.MethodGet, config.URL, nil)
+			if err != nil {
+				inFlight.Done()
+				return
+			}
+			if config.HostHeader != "" {
+				req.Host = config.HostHeader
+			}
+			trace := &httptrace.ClientTrace{
+				GotConn: func(info httptrace.GotConnInfo) {
+					if !info.Reused {
+						opened.Add(1)
+					}
+				},
+			}
+			req = req.WithContext(httptrace.WithClientTrace(req.Context(), trace))
+
+			<-start
+			resp, err := client.Do(req)
+			// Segurar a conexão até que todas estejam abertas, evitando que
+			// uma conexão liberada seja reaproveitada por outra goroutine
+			inFlight.Done()
+			inFlight.Wait()
+			if err != nil {
+				return
+			}
+			io.Copy(io.Discard, resp.Body)
+			resp.Body.Close()
+		}()
+	}
+
+	close(start)
+	wg.Wait()
+	return int(opened.Load())
+}
@@ -0,0 +1,134 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ProgressUpdate é a linha escrita a cada segundo com -progress json.
+type ProgressUpdate struct {
+	Elapsed   float64 `json:"elapsed_s"`
+	Completed int     `json:"completed"`
+	Planned   int     `json:"planned"`
+	Rate      float64 `json:"rate"`   // último segundo completo; na linha final, a média
+	P95       float64 `json:"p95_ms"` // sobre as latências mais recentes
+	Errors    int     `json:"errors"`
+	Done      bool    `json:"done"`
+}
+
+// WriteProgressLine imprime a atualização como uma linha JSON no stdout.
+func WriteProgressLine(update ProgressUpdate) {
+	data, _ := json.Marshal(update)
+	fmt.Println(string(data))
+}
+
+// showJSONProgress substitui a linha com \r por uma linha JSON por
+// segundo. A linha final, com done, é escrita por runRequests.
+func showJSONProgress(live *LiveStats, finished <-chan struct{}) {
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-finished:
+			return
+		case <-ticker.C:
+			WriteProgressLine(live.progressUpdate(false))
+		}
+	}
+}
+
+func (l *LiveStats) progressUpdate(done bool) ProgressUpdate {
+	s := l.Snapshot()
+	if done && s.Elapsed > 0 {
+		s.RPS = float64(s.Total) / s.Elapsed
+	}
+	return ProgressUpdate{
+		Elapsed:   s.Elapsed,
+		Completed: s.Total,
+		Planned:   s.Planned,
+		Rate:      s.RPS,
+		P95:       s.P95,
+		Errors:    s.Errors,
+		Done:      done,
+	}
+}
+
+const (
+	progressBarWidth = 30
+	// Sem terminal a linha não pode ser reescrita; uma nova a cada intervalo
+	plainProgressInterval = 5 * time.Second
+)
+
+// ProgressBar formata a atualização como barra com percentual, taxa,
+// erros e tempo estimado para o fim.
+func ProgressBar(update ProgressUpdate) string {
+	fraction := 0.0
+	if update.Planned > 0 {
+		fraction = min(float64(update.Completed)/float64(update.Planned), 1)
+	}
+	filled := int(fraction * progressBarWidth)
+	bar := strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled)
+	return fmt.Sprintf("[%s] %5.1f%% (%d/%d) | %.2f req/s | %d errors | ETA %s",
+		bar, fraction*100, update.Completed, update.Planned, update.Rate, update.Errors, update.eta())
+}
+
+// plainProgress é a versão de ProgressBar para logs de CI, sem a barra.
+func plainProgress(update ProgressUpdate) string {
+	percent := 0.0
+	if update.Planned > 0 {
+		percent = float64(update.Completed) / float64(update.Planned) * 100
+	}
+	return fmt.Sprintf("Progress: %.1f%% (%d/%d) | Rate: %.2f req/s | Errors: %d | ETA %s",
+		percent, update.Completed, update.Planned, update.Rate, update.Errors, update.eta())
+}
+
+// eta usa a taxa recente e, enquanto ela não existe, a média desde o início.
+func (u ProgressUpdate) eta() string {
+	remaining := u.Planned - u.Completed
+	if u.Done || remaining <= 0 {
+		return "0s"
+	}
+	rate := u.Rate
+	if rate <= 0 && u.Elapsed > 0 {
+		rate = float64(u.Completed) / u.Elapsed
+	}
+	if rate <= 0 {
+		return "--"
+	}
+	return (time.Duration(float64(remaining)/rate) * time.Second).Round(time.Second).String()
+}
+
+// showProgress redesenha a barra no terminal ou, fora dele, escreve uma
+// linha simples a cada plainProgressInterval. A linha final é escrita por
+// runRequests.
+func showProgress(live *LiveStats, finished <-chan struct{}, tty bool) {
+	interval := plainProgressInterval
+	if tty {
+		interval = 200 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-finished:
+			return
+		case <-ticker.C:
+			PrintProgress(live.progressUpdate(false), tty)
+		}
+	}
+}
+
+// PrintProgress reescreve a barra no terminal (tty) ou escreve uma linha
+// simples; com update.Done a linha é encerrada.
+func PrintProgress(update ProgressUpdate, tty bool) {
+	if tty {
+		fmt.Printf("\r%s\x1b[K", ProgressBar(update))
+		if update.Done {
+			fmt.Println()
+		}
+		return
+	}
+	fmt.Println(plainProgress(update))
+}
@@ -0,0 +1,134 @@
+package loadtest
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"text/template"
+)
+
+// QueryParam é um parâmetro de query adicionado a cada requisição.
+// Valores "rand:" são sorteados e "seq:" são percorridos em ordem usando o
+// seq da requisição; qualquer outro valor é literal e aceita templates.
+type QueryParam struct {
+	Name     string
+	Mode     string // "rand", "seq" ou "" (literal)
+	Values   []string
+	Min, Max int64 // intervalo numérico quando Values está vazio
+	literal  string
+	tmpl     *template.Template
+}
+
+// parseQueryParams interpreta valores de -query no formato
+// 'nome=valor', 'nome=rand:1-1000', 'nome=rand:a|b|c' ou 'nome=seq:1-10'.
+func parseQueryParams(values []string) ([]QueryParam, error) {
+	var params []QueryParam
+	for _, value := range values {
+		name, spec, ok := strings.Cut(value, "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid -query %q (use name=value, name=rand:1-100 or name=seq:a|b|c)", value)
+		}
+		param := QueryParam{Name: name}
+
+		mode, rest, hasMode := strings.Cut(spec, ":")
+		if !hasMode || (mode != "rand" && mode != "seq") {
+			tmpl, err := parseTemplate("query "+name, spec)
+			if err != nil {
+				return nil, err
+			}
+			param.literal, param.tmpl = spec, tmpl
+			params = append(params, param)
+			continue
+		}
+
+		param.Mode = mode
+		lo, hi, isRange, err := parseIntRange(rest)
+		switch {
+		case err != nil:
+			return nil, fmt.Errorf("invalid -query %q: %w", value, err)
+		case isRange:
+			param.Min, param.Max = lo, hi
+		default:
+			param.Values = strings.Split(rest, "|")
+			if slices.Contains(param.Values, "") {
+				return nil, fmt.Errorf("invalid -query %q: empty value in %s:", value, mode)
+			}
+		}
+		params = append(params, param)
+	}
+	return params, nil
+}
+
+// parseIntRange reconhece intervalos como 1-1000 (inclusivo). Um texto que
+// não é um par de números não é intervalo; um par invertido ou largo demais
+// para ser sorteado é um erro.
+func parseIntRange(s string) (lo, hi int64, ok bool, err error) {
+	a, b, found := strings.Cut(s, "-")
+	if !found {
+		return 0, 0, false, nil
+	}
+	lo, errLo := strconv.ParseInt(a, 10, 64)
+	hi, errHi := strconv.ParseInt(b, 10, 64)
+	if errLo != nil || errHi != nil {
+		return 0, 0, false, nil
+	}
+	if hi < lo {
+		return 0, 0, false, fmt.Errorf("range %s is reversed", s)
+	}
+	// O tamanho hi-lo+1 precisa caber em um int64
+	if uint64(hi)-uint64(lo) >= math.MaxInt64 {
+		return 0, 0, false, fmt.Errorf("range %s is too large", s)
+	}
+	return lo, hi, true, nil
+}
+
+func (q QueryParam) value(vars templateVars) (string, error) {
+	if q.Mode == "" {
+		if q.tmpl != nil {
+			return renderTemplate(q.tmpl, vars)
+		}
+		return q.literal, nil
+	}
+
+	size := int64(len(q.Values))
+	if size == 0 {
+		size = q.Max - q.Min + 1
+	}
+	var index int64
+	if q.Mode == "rand" {
+		index = vars.random().Int64N(size)
+	} else {
+		index = (vars.Seq - 1) % size
+	}
+
+	if len(q.Values) > 0 {
+		return q.Values[index], nil
+	}
+	return strconv.FormatInt(q.Min+index, 10), nil
+}
+
+// appendQuery adiciona os parâmetros configurados, na ordem das flags, ao
+// fim da query da URL. A query já existente fica como foi escrita.
+func appendQuery(rawURL string, params []QueryParam, vars templateVars) (string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return "", err
+	}
+	var sb strings.Builder
+	sb.WriteString(u.RawQuery)
+	for _, param := range params {
+		value, err := param.value(vars)
+		if err != nil {
+			return "", err
+		}
+		if sb.Len() > 0 {
+			sb.WriteByte('&')
+		}
+		sb.WriteString(url.QueryEscape(param.Name) + "=" + url.QueryEscape(value))
+	}
+	u.RawQuery = sb.String()
+	return u.String(), nil
+}
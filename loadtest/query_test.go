@@ -0,0 +1,75 @@
+package loadtest
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParseQueryParams(t *testing.T) {
+	tests := []struct {
+		spec    string
+		wantErr string
+		mode    string
+		min     int64
+		max     int64
+		values  []string
+	}{
+		{spec: "a=1", mode: ""},
+		{spec: "a=rand:1-1000", mode: "rand", min: 1, max: 1000},
+		{spec: "a=seq:10-10", mode: "seq", min: 10, max: 10},
+		{spec: "a=rand:x|y|z", mode: "rand", values: []string{"x", "y", "z"}},
+		{spec: "a=seq:v1-beta|v2", mode: "seq", values: []string{"v1-beta", "v2"}},
+		{spec: "a=rand:1-9223372036854775807", mode: "rand", min: 1, max: 9223372036854775807},
+		{spec: "a=rand:5-1", wantErr: "reversed"},
+		{spec: "a=rand:0-9223372036854775807", wantErr: "too large"},
+		{spec: "a=seq:", wantErr: "empty value"},
+		{spec: "a=rand:x||y", wantErr: "empty value"},
+		{spec: "=1", wantErr: "use name=value"},
+		{spec: "a", wantErr: "use name=value"},
+	}
+	for _, tc := range tests {
+		t.Run(tc.spec, func(t *testing.T) {
+			params, err := parseQueryParams([]string{tc.spec})
+			if tc.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tc.wantErr) {
+					t.Fatalf("err = %v, want it to mention %q", err, tc.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := params[0]
+			if p.Mode != tc.mode || p.Min != tc.min || p.Max != tc.max || strings.Join(p.Values, "|") != strings.Join(tc.values, "|") {
+				t.Errorf("got mode=%q min=%d max=%d values=%v", p.Mode, p.Min, p.Max, p.Values)
+			}
+		})
+	}
+}
+
+// Os parâmetros vão para o fim da query, sem reordenar nem reescapar a que
+// o usuário escreveu.
+func TestAppendQueryKeepsExistingQuery(t *testing.T) {
+	params, err := parseQueryParams([]string{"z=seq:a|b", "page=seq:1-3", "q=a b"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		url  string
+		seq  int64
+		want string
+	}{
+		{"http://example.com/search", 1, "http://example.com/search?z=a&page=1&q=a+b"},
+		{"http://example.com/search?b=2&a=1&x=%7e", 2, "http://example.com/search?b=2&a=1&x=%7e&z=b&page=2&q=a+b"},
+		{"http://example.com/?", 4, "http://example.com/?z=b&page=1&q=a+b"},
+	}
+	for _, tc := range tests {
+		got, err := appendQuery(tc.url, params, templateVars{Seq: tc.seq})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if got != tc.want {
+			t.Errorf("appendQuery(%q) = %q, want %q", tc.url, got, tc.want)
+		}
+	}
+}
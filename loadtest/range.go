@@ -0,0 +1,129 @@
+package loadtest
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+)
+
+// RangeOptions configura requisições Range com janelas de bytes fixas,
+// sorteadas ou em varredura sequencial pelo recurso.
+type RangeOptions struct {
+	Mode   string // "fixed", "random" ou "sweep"
+	Size   int64  // tamanho da janela
+	Offset int64  // início da janela no modo fixed
+	Total  int64  // tamanho do recurso, descoberto via HEAD quando 0
+}
+
+// Resultado da verificação da resposta a uma requisição Range
+type RangeOutcome int
+
+const (
+	RangeNotSent        RangeOutcome = iota
+	RangePartial                     // 206 com o Content-Range pedido
+	RangeIgnored                     // 200 com o recurso completo
+	RangeMismatch                    // 206 com Content-Range diferente do pedido
+	RangeNotSatisfiable              // 416
+)
+
+type RangeStats struct {
+	Sent           int
+	Partial        int
+	Ignored        int
+	Mismatch       int
+	NotSatisfiable int
+}
+
+func (s *RangeStats) add(outcome RangeOutcome) {
+	switch outcome {
+	case RangeNotSent:
+		return
+	case RangePartial:
+		s.Partial++
+	case RangeIgnored:
+		s.Ignored++
+	case RangeMismatch:
+		s.Mismatch++
+	case RangeNotSatisfiable:
+		s.NotSatisfiable++
+	}
+	s.Sent++
+}
+
+func validateRangeOptions(opts *RangeOptions) error {
+	switch opts.Mode {
+	case "fixed", "random", "sweep":
+	default:
+		return fmt.Errorf("invalid -range-mode %q (use fixed, random or sweep)", opts.Mode)
+	}
+	if opts.Size <= 0 {
+		return fmt.Errorf("-range-size must be greater than zero")
+	}
+	return nil
+}
+
+// discoverContentLength descobre o tamanho do recurso com um HEAD, necessário
+// para sortear ou varrer janelas sem ultrapassar o fim do arquivo.
+func discoverContentLength(client *http.Client, config Config) (int64, error) {
+	req, err := http.NewRequest(http.MethodHead, config.URL, nil)
+	if err != nil {
+		return 0, err
+	}
+	if config.HostHeader != "" {
+		req.Host = config.HostHeader
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("discovering resource size: %w", err)
+	}
+	resp.Body.Close()
+	if resp.ContentLength <= 0 {
+		return 0, fmt.Errorf("server did not report Content-Length for %s; set -range-total", config.URL)
+	}
+	return resp.ContentLength, nil
+}
+
+// window calcula o intervalo [start, end] (inclusivo) da requisição seq.
+func (o *RangeOptions) window(vars templateVars) (start, end int64) {
+	switch o.Mode {
+	case "random":
+		if o.Total > o.Size {
+			start = vars.random().Int64N(o.Total - o.Size + 1)
+		}
+	case "sweep":
+		windows := max((o.Total+o.Size-1)/o.Size, 1)
+		start = ((vars.Seq - 1) % windows) * o.Size
+	default:
+		start = o.Offset
+	}
+	end = start + o.Size - 1
+	if o.Total > 0 && end >= o.Total {
+		end = o.Total - 1
+	}
+	return start, end
+}
+
+// checkRange confere se o servidor respondeu exatamente a janela pedida.
+func checkRange(resp *http.Response, start, end int64) RangeOutcome {
+	switch resp.StatusCode {
+	case http.StatusPartialContent:
+		// Content-Range: bytes start-end/total
+		spec := strings.TrimPrefix(resp.Header.Get("Content-Range"), "bytes ")
+		span, _, _ := strings.Cut(spec, "/")
+		first, last, ok := strings.Cut(span, "-")
+		if !ok {
+			return RangeMismatch
+		}
+		gotStart, err1 := strconv.ParseInt(first, 10, 64)
+		gotEnd, err2 := strconv.ParseInt(last, 10, 64)
+		if err1 != nil || err2 != nil || gotStart != start || gotEnd != end {
+			return RangeMismatch
+		}
+		return RangePartial
+	case http.StatusRequestedRangeNotSatisfiable:
+		return RangeNotSatisfiable
+	default:
+		return RangeIgnored
+	}
+}
@@ -0,0 +1,40 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+type redirectCountKey struct{}
+
+// withRedirectCounter anexa ao contexto um contador de redirecionamentos,
+// preservado pelo http.Client em toda a cadeia.
+func withRedirectCounter(ctx context.Context) (context.Context, *int) {
+	count := new(int)
+	return context.WithValue(ctx, redirectCountKey{}, count), count
+}
+
+// redirectLimitError é devolvido quando a cadeia passa do -max-redirects.
+type redirectLimitError struct {
+	limit int
+}
+
+func (e *redirectLimitError) Error() string {
+	return fmt.Sprintf("stopped after %d redirects", e.limit)
+}
+
+func redirectPolicy(config Config) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if config.NoFollow {
+			return http.ErrUseLastResponse
+		}
+		if len(via) > config.MaxRedirects {
+			return &redirectLimitError{limit: config.MaxRedirects}
+		}
+		if count, ok := req.Context().Value(redirectCountKey{}).(*int); ok {
+			*count = len(via)
+		}
+		return nil
+	}
+}
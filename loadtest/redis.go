@@ -0,0 +1,318 @@
+package loadtest
+
+import (
+	"bufio"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// RedisOptions configura o modo RESP: cada requisição envia um comando,
+// alternando entre os -redis-command informados.
+type RedisOptions struct {
+	Commands []string
+	Addr     string
+	Username string
+	Password string
+	DB       int
+	TLS      bool
+	commands []*template.Template
+}
+
+func newRedisOptions(rawURL string, commands []string) (*RedisOptions, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" || (u.Scheme != "redis" && u.Scheme != "rediss") {
+		return nil, fmt.Errorf("invalid Redis URL %q (use redis://[user:pass@]host:port[/db] or rediss://)", rawURL)
+	}
+	opts := &RedisOptions{Addr: u.Host, TLS: u.Scheme == "rediss"}
+	if u.Port() == "" {
+		opts.Addr = net.JoinHostPort(u.Hostname(), "6379")
+	}
+	if u.User != nil {
+		opts.Username = u.User.Username()
+		opts.Password, _ = u.User.Password()
+		// redis://:senha@host usa apenas a senha (AUTH legado)
+		if _, hasPass := u.User.Password(); !hasPass {
+			opts.Username, opts.Password = "", opts.Username
+		}
+	}
+	if db := strings.Trim(u.Path, "/"); db != "" {
+		if opts.DB, err = strconv.Atoi(db); err != nil {
+			return nil, fmt.Errorf("invalid Redis database %q", db)
+		}
+	}
+
+	if len(commands) == 0 {
+		commands = []string{"PING"}
+	}
+	for i, command := range commands {
+		if strings.TrimSpace(command) == "" {
+			return nil, fmt.Errorf("empty -redis-command")
+		}
+		tmpl, err := parseTemplate(fmt.Sprintf("redis-command-%d", i), command)
+		if err != nil {
+			return nil, err
+		}
+		opts.Commands = append(opts.Commands, command)
+		opts.commands = append(opts.commands, tmpl)
+	}
+	return opts, nil
+}
+
+type RedisCommandStats struct {
+	Count     int
+	Errors    int
+	Misses    int // respostas nulas (ex.: GET de chave inexistente)
+	Latency   PhaseStats
+	P50       time.Duration
+	P90       time.Duration
+	P99       time.Duration
+	durations []time.Duration
+}
+
+type RedisStats struct {
+	Commands map[string]*RedisCommandStats // por comando: GET, SET...
+}
+
+type redisResult struct {
+	command string
+	miss    bool
+}
+
+// errRedisReply representa uma resposta de erro do servidor (-ERR ...).
+type errRedisReply struct {
+	message string
+}
+
+func (e errRedisReply) Error() string {
+	return "redis: " + e.message
+}
+
+func (s *RedisStats) add(result Result) {
+	if result.Redis == nil {
+		return
+	}
+	if s.Commands == nil {
+		s.Commands = make(map[string]*RedisCommandStats)
+	}
+	stats, ok := s.Commands[result.Redis.command]
+	if !ok {
+		stats = &RedisCommandStats{}
+		s.Commands[result.Redis.command] = stats
+	}
+	stats.Count++
+	if result.Error != nil {
+		stats.Errors++
+		return
+	}
+	if result.Redis.miss {
+		stats.Misses++
+	}
+	stats.Latency.add(result.Duration)
+	stats.durations = append(stats.durations, result.Duration)
+}
+
+func (s *RedisStats) finish() {
+	for _, stats := range s.Commands {
+		stats.P50 = Percentile(stats.durations, 50)
+		stats.P90 = Percentile(stats.durations, 90)
+		stats.P99 = Percentile(stats.durations, 99)
+	}
+}
+
+// redisConn é a conexão de um usuário virtual, reaproveitada entre comandos.
+type redisConn struct {
+	conn   net.Conn
+	reader *bufio.Reader
+}
+
+func executeRedisLoadTest(config Config) Report {
+	dial := newDialContext(config)
+	// Cada usuário virtual mantém sua própria conexão, como um pool fixo
+	conns := make([]*redisConn, config.Concurrency)
+
+	report := runRequests(config, func(vars templateVars, vu *virtualUser, results chan<- Result) {
+		results <- makeRedisRequest(dial, config, &conns[vu.ID], vars)
+	})
+	for _, c := range conns {
+		if c != nil {
+			c.conn.Close()
+		}
+	}
+
+	report.Redis.finish()
+	report.Mode = "redis"
+	return report
+}
+
+func makeRedisRequest(dial dialFunc, config Config, slot **redisConn, vars templateVars) Result {
+	index := int((vars.Seq - 1) % int64(len(config.Redis.commands)))
+	command := config.Redis.Commands[index]
+	if tmpl := config.Redis.commands[index]; tmpl != nil {
+		rendered, err := renderTemplate(tmpl, vars)
+		if err != nil {
+			return Result{ErrorKind: ClassifyError(err), Error: err}
+		}
+		command = rendered
+	}
+	args := strings.Fields(command)
+	if len(args) == 0 {
+		err := fmt.Errorf("redis command rendered empty")
+		return Result{ErrorKind: ClassifyError(err), Error: err}
+	}
+	result := Result{Redis: &redisResult{command: strings.ToUpper(args[0])}}
+
+	if *slot == nil {
+		c, err := dialRedis(dial, config)
+		if err != nil {
+			result.Error = err
+			result.ErrorKind = ClassifyError(err)
+			return result
+		}
+		*slot = c
+	}
+	c := *slot
+	result.RemoteAddr = c.conn.RemoteAddr().String()
+	c.conn.SetDeadline(time.Now().Add(config.Timeout))
+
+	start := time.Now()
+	reply, err := c.do(args...)
+	result.Duration = time.Since(start)
+	var replyErr errRedisReply
+	switch {
+	case errors.As(err, &replyErr):
+		// Erro do comando; a conexão continua válida
+		result.Error = err
+		result.StatusCode = 502
+	case err != nil:
+		// Falha de rede ou de protocolo: descartar a conexão
+		c.conn.Close()
+		*slot = nil
+		result.Error = err
+		result.ErrorKind = ClassifyError(err)
+	default:
+		result.StatusCode = 200
+		result.Redis.miss = reply == nil
+	}
+	return result
+}
+
+func dialRedis(dial dialFunc, config Config) (*redisConn, error) {
+	ctx, cancel := context.WithTimeout(config.context(), config.Timeout)
+	defer cancel()
+
+	conn, err := dial(ctx, "tcp", config.Redis.Addr)
+	if err != nil {
+		return nil, err
+	}
+	if config.Redis.TLS {
+		tlsConfig := config.TLSConfig.Clone()
+		if tlsConfig.ServerName == "" {
+			tlsConfig.ServerName, _, _ = net.SplitHostPort(config.Redis.Addr)
+		}
+		tlsConn := tls.Client(conn, tlsConfig)
+		if err := tlsConn.HandshakeContext(ctx); err != nil {
+			conn.Close()
+			return nil, err
+		}
+		conn = tlsConn
+	}
+	c := &redisConn{conn: conn, reader: bufio.NewReader(conn)}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	// Autenticação e seleção do banco não entram nas estatísticas
+	if config.Redis.Password != "" {
+		args := []string{"AUTH", config.Redis.Password}
+		if config.Redis.Username != "" {
+			args = []string{"AUTH", config.Redis.Username, config.Redis.Password}
+		}
+		if _, err := c.do(args...); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	if config.Redis.DB != 0 {
+		if _, err := c.do("SELECT", strconv.Itoa(config.Redis.DB)); err != nil {
+			conn.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// do envia um comando como array RESP e lê a resposta. Respostas nulas
+// retornam nil.
+func (c *redisConn) do(args ...string) (any, error) {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%d\r\n", len(args))
+	for _, arg := range args {
+		fmt.Fprintf(&sb, "$%d\r\n%s\r\n", len(arg), arg)
+	}
+	if _, err := io.WriteString(c.conn, sb.String()); err != nil {
+		return nil, err
+	}
+	return c.readReply()
+}
+
+func (c *redisConn) readReply() (any, error) {
+	line, err := c.reader.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\r\n")
+	if line == "" {
+		return nil, fmt.Errorf("redis: empty reply")
+	}
+
+	switch line[0] {
+	case '+':
+		return line[1:], nil
+	case '-':
+		return nil, errRedisReply{message: line[1:]}
+	case ':':
+		return strconv.ParseInt(line[1:], 10, 64)
+	case '$':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid bulk length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		buf := make([]byte, n+2)
+		if _, err := io.ReadFull(c.reader, buf); err != nil {
+			return nil, err
+		}
+		return string(buf[:n]), nil
+	case '*':
+		n, err := strconv.Atoi(line[1:])
+		if err != nil {
+			return nil, fmt.Errorf("redis: invalid array length %q", line)
+		}
+		if n < 0 {
+			return nil, nil
+		}
+		items := make([]any, n)
+		for i := range items {
+			if items[i], err = c.readReply(); err != nil {
+				var replyErr errRedisReply
+				if !errors.As(err, &replyErr) {
+					return nil, err
+				}
+			}
+		}
+		return items, nil
+	default:
+		return nil, fmt.Errorf("redis: unexpected reply %q", line)
+	}
+}
@@ -0,0 +1,102 @@
+package loadtest
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"google.golang.org/grpc"
+	reflectionpb "google.golang.org/grpc/reflection/grpc_reflection_v1"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// loadReflectedMethod descobre o schema do método pela API de reflection do
+// servidor (grpc.reflection.v1), dispensando os arquivos .proto.
+func loadReflectedMethod(conn *grpc.ClientConn, call string, timeout time.Duration) (protoreflect.MethodDescriptor, error) {
+	serviceName, methodName, err := splitGRPCMethod(call)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	stream, err := reflectionpb.NewServerReflectionClient(conn).ServerReflectionInfo(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("opening reflection stream: %w", err)
+	}
+	defer stream.CloseSend()
+
+	files := make(map[string]*descriptorpb.FileDescriptorProto)
+	fetch := func(req *reflectionpb.ServerReflectionRequest) error {
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+		resp, err := stream.Recv()
+		if err != nil {
+			return fmt.Errorf("server reflection: %w", err)
+		}
+		if errResp := resp.GetErrorResponse(); errResp != nil {
+			return fmt.Errorf("server reflection: %s", errResp.GetErrorMessage())
+		}
+		for _, raw := range resp.GetFileDescriptorResponse().GetFileDescriptorProto() {
+			file := &descriptorpb.FileDescriptorProto{}
+			if err := proto.Unmarshal(raw, file); err != nil {
+				return fmt.Errorf("decoding reflected descriptor: %w", err)
+			}
+			files[file.GetName()] = file
+		}
+		return nil
+	}
+
+	err = fetch(&reflectionpb.ServerReflectionRequest{
+		MessageRequest: &reflectionpb.ServerReflectionRequest_FileContainingSymbol{FileContainingSymbol: serviceName},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// O servidor pode omitir dependências já enviadas; buscar as que faltam
+	for missing := missingDependencies(files); len(missing) > 0; missing = missingDependencies(files) {
+		for _, name := range missing {
+			err := fetch(&reflectionpb.ServerReflectionRequest{
+				MessageRequest: &reflectionpb.ServerReflectionRequest_FileByFilename{FileByFilename: name},
+			})
+			if err != nil {
+				return nil, err
+			}
+			if files[name] == nil {
+				return nil, fmt.Errorf("server reflection did not return %s", name)
+			}
+		}
+	}
+
+	set := &descriptorpb.FileDescriptorSet{}
+	for _, file := range files {
+		set.File = append(set.File, file)
+	}
+	registry, err := protodesc.NewFiles(set)
+	if err != nil {
+		return nil, fmt.Errorf("building reflected descriptors: %w", err)
+	}
+
+	desc, err := registry.FindDescriptorByName(protoreflect.FullName(serviceName))
+	if err != nil {
+		return nil, fmt.Errorf("service %s not found via server reflection", serviceName)
+	}
+	return findServiceMethod(desc, serviceName, methodName)
+}
+
+func missingDependencies(files map[string]*descriptorpb.FileDescriptorProto) []string {
+	var missing []string
+	for _, file := range files {
+		for _, dep := range file.GetDependency() {
+			if files[dep] == nil {
+				missing = append(missing, dep)
+			}
+		}
+	}
+	return missing
+}
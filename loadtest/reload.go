@@ -0,0 +1,60 @@
+package loadtest
+
+import (
+	"maps"
+	"net/http"
+	"slices"
+	"sync/atomic"
+	"text/template"
+	"time"
+)
+
+// TimelineEvent é uma mudança aplicada com o teste em andamento.
+type TimelineEvent struct {
+	At     time.Duration // desde o início do teste
+	Change string
+}
+
+// LiveHeaders troca headers de um teste em andamento (-watch). Os valores
+// aceitam templates e sobrescrevem os do -headers com o mesmo nome.
+type LiveHeaders struct {
+	current atomic.Pointer[liveHeaderSet]
+}
+
+type liveHeaderSet struct {
+	values    map[string]string
+	templates map[string]*template.Template
+}
+
+// Set substitui os headers aplicados às próximas requisições.
+func (h *LiveHeaders) Set(headers map[string]string) error {
+	templates, err := parseHeaderTemplates(headers)
+	if err != nil {
+		return err
+	}
+	h.current.Store(&liveHeaderSet{values: headers, templates: templates})
+	return nil
+}
+
+func (h *LiveHeaders) apply(header http.Header, vars templateVars) error {
+	if h == nil {
+		return nil
+	}
+	set := h.current.Load()
+	if set == nil {
+		return nil
+	}
+	// Em ordem fixa, para o -seed repetir os valores sorteados
+	for _, k := range slices.Sorted(maps.Keys(set.values)) {
+		v := set.values[k]
+		if tmpl, ok := set.templates[k]; ok {
+			rendered, err := renderTemplate(tmpl, vars)
+			if err != nil {
+				return err
+			}
+			v = rendered
+		}
+		header.Set(k, v)
+	}
+	return nil
+}
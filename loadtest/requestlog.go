@@ -0,0 +1,174 @@
+package loadtest
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Corpos maiores que isso não são gravados no log de requisições.
+const maxLoggedBody = 1 << 20
+
+// requestLogEntry é uma linha do log NDJSON (-request-log). Os campos de
+// requisição seguem o formato do -targets para que o log possa ser
+// reproduzido com -replay.
+type requestLogEntry struct {
+	Time        time.Time         `json:"time"`
+	OffsetMS    float64           `json:"offset_ms"`
+	Method      string            `json:"method"`
+	URL         string            `json:"url"`
+	Headers     map[string]string `json:"headers,omitempty"`
+	Body        string            `json:"body,omitempty"`
+	BodyOmitted bool              `json:"body_omitted,omitempty"` // corpo binário, em streaming ou grande demais
+	Status      int               `json:"status"`
+	DurationMS  float64           `json:"duration_ms"`
+	Error       string            `json:"error,omitempty"`
+	ErrorKind   ErrorKind         `json:"error_kind,omitempty"`
+}
+
+type RequestLogger struct {
+	mu    sync.Mutex
+	file  *os.File
+	w     *bufio.Writer
+	start time.Time
+}
+
+func NewRequestLogger(path string) (*RequestLogger, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("creating request log: %w", err)
+	}
+	return &RequestLogger{file: file, w: bufio.NewWriter(file), start: time.Now()}, nil
+}
+
+// captureBody lê corpos em memória para o log e devolve um leitor novo no
+// lugar do consumido. Retorna nil quando o corpo não pode ser gravado.
+func captureBody(body *requestBody) (*string, error) {
+	if body.streaming || body.length > maxLoggedBody {
+		return nil, nil
+	}
+	data, err := io.ReadAll(body.reader)
+	if err != nil {
+		return nil, err
+	}
+	body.reader = bytes.NewReader(data)
+	if !utf8.Valid(data) {
+		return nil, nil
+	}
+	s := string(data)
+	return &s, nil
+}
+
+func (l *RequestLogger) write(req *http.Request, body *string, start time.Time, status int, kind ErrorKind, duration time.Duration, err error) {
+	entry := requestLogEntry{
+		Time:       start,
+		OffsetMS:   float64(start.Sub(l.start)) / float64(time.Millisecond),
+		Method:     req.Method,
+		URL:        req.URL.String(),
+		Status:     status,
+		ErrorKind:  kind,
+		DurationMS: float64(duration) / float64(time.Millisecond),
+	}
+	if len(req.Header) > 0 {
+		entry.Headers = make(map[string]string, len(req.Header))
+		for name, values := range req.Header {
+			entry.Headers[name] = strings.Join(values, ", ")
+		}
+	}
+	switch {
+	case body != nil:
+		entry.Body = *body
+	case req.ContentLength != 0:
+		entry.BodyOmitted = true
+	}
+	if err != nil {
+		entry.Error = err.Error()
+	}
+
+	data, _ := json.Marshal(entry)
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.w.Write(data)
+	l.w.WriteByte('\n')
+}
+
+func (l *RequestLogger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.w.Flush(); err != nil {
+		l.file.Close()
+		return err
+	}
+	return l.file.Close()
+}
+
+// loadReplayTargets lê um log de requisições e devolve os alvos na ordem em
+// que foram enviados, com os instantes divididos por speed.
+func loadReplayTargets(path string, speed float64) ([]Target, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("opening request log: %w", err)
+	}
+	defer file.Close()
+
+	var entries []requestLogEntry
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 64*1024), 4*maxLoggedBody)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var entry requestLogEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("parsing request log line %d: %w", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading request log: %w", err)
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("request log %s is empty", path)
+	}
+
+	// O log é gravado na ordem de conclusão; o replay segue a de envio
+	sort.SliceStable(entries, func(i, j int) bool { return entries[i].OffsetMS < entries[j].OffsetMS })
+
+	omitted := 0
+	targets := make([]Target, len(entries))
+	for i, entry := range entries {
+		if entry.BodyOmitted {
+			omitted++
+		}
+		headers := entry.Headers
+		for name := range headers {
+			if HARSkippedHeaders[strings.ToLower(name)] {
+				delete(headers, name)
+			}
+		}
+		targets[i] = Target{
+			Name:    entry.Method + " " + entry.URL,
+			Method:  entry.Method,
+			URL:     entry.URL,
+			Headers: headers,
+			Body:    entry.Body,
+		}
+		if speed > 0 {
+			targets[i].OffsetMS = entry.OffsetMS / speed
+		}
+	}
+	if omitted > 0 {
+		slog.Warn("logged requests had no recorded body and are replayed without one", "count", omitted)
+	}
+	return targets, nil
+}
@@ -0,0 +1,74 @@
+package loadtest
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// HeaderRotation alterna o valor de um header entre as linhas de um arquivo
+// (ex.: User-Agents ou chaves de API), em ordem ou sorteado por requisição.
+type HeaderRotation struct {
+	Name   string
+	Random bool
+	Values []string
+}
+
+// parseHeaderRotations interpreta valores de -header-file no formato
+// 'Nome:arquivo' (round-robin) ou 'Nome:arquivo:random'.
+func parseHeaderRotations(values []string) ([]HeaderRotation, error) {
+	var rotations []HeaderRotation
+	for _, value := range values {
+		name, path, ok := strings.Cut(value, ":")
+		if !ok || name == "" || path == "" {
+			return nil, fmt.Errorf("invalid -header-file %q (use Name:file or Name:file:random)", value)
+		}
+		rotation := HeaderRotation{Name: strings.TrimSpace(name)}
+		if trimmed, isRandom := strings.CutSuffix(path, ":random"); isRandom {
+			rotation.Random, path = true, trimmed
+		}
+
+		lines, err := readLines(path)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) == 0 {
+			return nil, fmt.Errorf("header file %s has no values", path)
+		}
+		rotation.Values = lines
+		rotations = append(rotations, rotation)
+	}
+	return rotations, nil
+}
+
+// readLines lê as linhas não vazias de um arquivo, ignorando comentários (#).
+func readLines(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var lines []string
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return lines, nil
+}
+
+// value escolhe o valor da requisição; o round-robin segue o seq global.
+func (h HeaderRotation) value(vars templateVars) string {
+	if h.Random {
+		return h.Values[vars.random().IntN(len(h.Values))]
+	}
+	return h.Values[(vars.Seq-1)%int64(len(h.Values))]
+}
@@ -0,0 +1,26 @@
+package loadtest
+
+import (
+	mathrand "math/rand/v2"
+)
+
+// globalRand é o gerador usado fora de um teste com semente (-dry-run e
+// -debug-request sem -seed, por exemplo).
+var globalRand = mathrand.New(runtimeSource{})
+
+// runtimeSource usa o gerador global do math/rand, seguro entre goroutines.
+type runtimeSource struct{}
+
+func (runtimeSource) Uint64() uint64 { return mathrand.Uint64() }
+
+// templateVars monta as variáveis da requisição seq. Com Seed, cada
+// requisição tem um gerador próprio derivado da semente e do seq: os valores
+// sorteados não dependem da ordem em que as goroutines rodam, e a mesma
+// semente repete a mesma sequência de requisições.
+func (c Config) templateVars(seq int64, workerID int) templateVars {
+	vars := templateVars{Seq: seq, WorkerID: workerID}
+	if c.Seed != 0 {
+		vars.rand = mathrand.New(mathrand.NewPCG(c.Seed, uint64(seq)))
+	}
+	return vars
+}
@@ -0,0 +1,35 @@
+package loadtest
+
+import (
+	"testing"
+)
+
+// Com a mesma semente, vários headers com funções aleatórias recebem
+// sempre os mesmos valores, qualquer que seja a ordem do map de headers.
+func TestSeedRepeatsTemplatedHeaders(t *testing.T) {
+	config, err := parseFlags(t, "-url", "http://example.com", "-requests", "1", "-seed", "7",
+		"-headers", "A:{{randInt 1 1000000}},B:{{uuidv4}},C:{{fakeName}},D:{{randInt 1 1000000}}")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var first map[string]string
+	for range 20 {
+		req, _, _, err := buildRequest(config, config.templateVars(3, 1))
+		if err != nil {
+			t.Fatal(err)
+		}
+		got := map[string]string{}
+		for _, name := range []string{"A", "B", "C", "D"} {
+			got[name] = req.Header.Get(name)
+		}
+		if first == nil {
+			first = got
+			continue
+		}
+		for name, value := range got {
+			if value != first[name] {
+				t.Fatalf("header %s = %q, want %q with the same seed", name, value, first[name])
+			}
+		}
+	}
+}
@@ -0,0 +1,118 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+)
+
+type LoginOptions struct {
+	URL         string
+	Method      string
+	Body        string
+	Headers     map[string]string
+	Extract     string // "json:caminho.do.token", "header:Nome" ou "cookie:Nome"
+	TokenHeader string
+}
+
+// virtualUser representa um slot de concorrência. Cada VU mantém sua própria
+// sessão de login, reaproveitada nas requisições seguintes.
+type virtualUser struct {
+	ID    int
+	token string
+}
+
+func validateLoginOptions(opts *LoginOptions) error {
+	kind, name, ok := strings.Cut(opts.Extract, ":")
+	if !ok || name == "" || (kind != "json" && kind != "header" && kind != "cookie") {
+		return fmt.Errorf("invalid -login-extract %q (use json:path, header:Name or cookie:Name)", opts.Extract)
+	}
+	return nil
+}
+
+// ensureSession faz o login do VU caso ele ainda não tenha uma sessão válida.
+func (vu *virtualUser) ensureSession(client *http.Client, opts *LoginOptions) error {
+	if vu.token != "" {
+		return nil
+	}
+
+	req, err := http.NewRequest(opts.Method, opts.URL, strings.NewReader(opts.Body))
+	if err != nil {
+		return fmt.Errorf("building login request: %w", err)
+	}
+	for k, v := range opts.Headers {
+		req.Header.Add(k, v)
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("login request: %w", err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("reading login response: %w", err)
+	}
+	if resp.StatusCode >= 400 {
+		return fmt.Errorf("login failed with status %d", resp.StatusCode)
+	}
+
+	token, err := extractToken(resp, body, opts.Extract)
+	if err != nil {
+		return err
+	}
+	vu.token = token
+	return nil
+}
+
+// invalidate descarta a sessão para que o próximo uso refaça o login.
+func (vu *virtualUser) invalidate() {
+	vu.token = ""
+}
+
+func extractToken(resp *http.Response, body []byte, extract string) (string, error) {
+	kind, name, _ := strings.Cut(extract, ":")
+	switch kind {
+	case "header":
+		if token := resp.Header.Get(name); token != "" {
+			return token, nil
+		}
+	case "cookie":
+		for _, cookie := range resp.Cookies() {
+			if cookie.Name == name {
+				return cookie.Value, nil
+			}
+		}
+	case "json":
+		var data any
+		if err := json.Unmarshal(body, &data); err != nil {
+			return "", fmt.Errorf("login response is not JSON: %w", err)
+		}
+		for _, key := range strings.Split(name, ".") {
+			obj, ok := data.(map[string]any)
+			if !ok {
+				data = nil
+				break
+			}
+			data = obj[key]
+		}
+		if token, ok := data.(string); ok && token != "" {
+			return token, nil
+		}
+	}
+	return "", fmt.Errorf("login response has no %s", extract)
+}
+
+func (opts *LoginOptions) apply(req *http.Request, token string) {
+	kind, name, _ := strings.Cut(opts.Extract, ":")
+	switch {
+	case kind == "cookie":
+		req.AddCookie(&http.Cookie{Name: name, Value: token})
+	case strings.EqualFold(opts.TokenHeader, "Authorization"):
+		req.Header.Set("Authorization", "Bearer "+token)
+	default:
+		req.Header.Set(opts.TokenHeader, token)
+	}
+}
@@ -0,0 +1,114 @@
+package loadtest
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// SSEOptions configura o modo Server-Sent Events: cada requisição mantém
+// uma conexão text/event-stream aberta por Duration.
+type SSEOptions struct {
+	Duration time.Duration
+}
+
+// sseSession controla o tempo de vida de uma conexão SSE. O -timeout vale
+// até a chegada dos headers; depois disso a conexão fica aberta até
+// Duration, e um encerramento antes disso conta como conexão derrubada.
+type sseSession struct {
+	cancel   context.CancelFunc
+	timeout  *time.Timer
+	finished atomic.Bool // Duration atingida, encerramento esperado
+}
+
+var errSSEDropped = errors.New("event stream closed before -sse-duration")
+
+type sseResult struct {
+	Events     int
+	FirstEvent time.Duration // do início da requisição até o primeiro evento
+	Gaps       []time.Duration
+	Dropped    bool
+}
+
+func newSSESession(ctx context.Context, timeout time.Duration) (context.Context, *sseSession) {
+	ctx, cancel := context.WithCancel(ctx)
+	s := &sseSession{cancel: cancel}
+	s.timeout = time.AfterFunc(timeout, cancel)
+	return ctx, s
+}
+
+// read consome os eventos até o fim de Duration ou até o stream cair.
+func (s *sseSession) read(body io.Reader, start time.Time, duration time.Duration) (sseResult, error) {
+	s.timeout.Stop()
+	hold := time.AfterFunc(duration, func() {
+		s.finished.Store(true)
+		s.cancel()
+	})
+	defer hold.Stop()
+	defer s.cancel()
+
+	var result sseResult
+	last := start
+	pending := false // há linhas de dados aguardando o despacho do evento
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 64*1024), 1<<20)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			pending = pending || strings.HasPrefix(line, "data")
+			continue
+		}
+		if !pending {
+			continue
+		}
+		// Linha em branco despacha o evento
+		pending = false
+		now := time.Now()
+		if result.Events == 0 {
+			result.FirstEvent = now.Sub(start)
+		} else {
+			result.Gaps = append(result.Gaps, now.Sub(last))
+		}
+		result.Events++
+		last = now
+	}
+
+	if s.finished.Load() {
+		return result, nil
+	}
+	result.Dropped = true
+	err := scanner.Err()
+	if err == nil {
+		err = errSSEDropped
+	}
+	return result, err
+}
+
+type SSEStats struct {
+	Connections int
+	Events      int
+	Dropped     int
+	DropRate    float64
+	FirstEvent  PhaseStats
+	InterEvent  PhaseStats
+}
+
+func (s *SSEStats) add(result *sseResult) {
+	if result == nil {
+		return
+	}
+	s.Connections++
+	s.Events += result.Events
+	if result.Dropped {
+		s.Dropped++
+	}
+	s.DropRate = float64(s.Dropped) / float64(s.Connections) * 100
+	s.FirstEvent.add(result.FirstEvent)
+	for _, gap := range result.Gaps {
+		s.InterEvent.add(gap)
+	}
+}
@@ -0,0 +1,73 @@
+package loadtest
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DefaultSuccessStatus é o conjunto de códigos contados como sucesso no
+// resumo quando -success-status não é informado.
+const DefaultSuccessStatus = "2xx"
+
+// StatusSet é um conjunto de códigos de status escrito como lista de códigos
+// (204), classes (2xx) e intervalos (200-299), separados por vírgula.
+type StatusSet []statusRange
+
+type statusRange struct{ lo, hi int }
+
+// ParseStatusSet interpreta a lista de códigos, classes e intervalos.
+func ParseStatusSet(spec string) (StatusSet, error) {
+	var set StatusSet
+	for _, item := range strings.Split(spec, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		r, err := parseStatusRange(item)
+		if err != nil {
+			return nil, err
+		}
+		set = append(set, r)
+	}
+	if len(set) == 0 {
+		return nil, fmt.Errorf("empty status list %q", spec)
+	}
+	return set, nil
+}
+
+func parseStatusRange(item string) (statusRange, error) {
+	invalid := fmt.Errorf("invalid status %q (use a code like 204, a class like 2xx or a range like 200-299)", item)
+	if len(item) == 3 && strings.EqualFold(item[1:], "xx") {
+		class := int(item[0] - '0')
+		if class < 1 || class > 5 {
+			return statusRange{}, invalid
+		}
+		return statusRange{class * 100, class*100 + 99}, nil
+	}
+	from, to, isRange := strings.Cut(item, "-")
+	lo, err := strconv.Atoi(from)
+	if err != nil {
+		return statusRange{}, invalid
+	}
+	hi := lo
+	if isRange {
+		if hi, err = strconv.Atoi(to); err != nil {
+			return statusRange{}, invalid
+		}
+	}
+	if lo < 100 || hi > 599 || lo > hi {
+		return statusRange{}, invalid
+	}
+	return statusRange{lo, hi}, nil
+}
+
+// Contains informa se o código pertence ao conjunto.
+func (s StatusSet) Contains(code int) bool {
+	for _, r := range s {
+		if code >= r.lo && code <= r.hi {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,64 @@
+package loadtest
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// SweepStep guarda o resultado de uma rodada do -body-sizes.
+type SweepStep struct {
+	Size   int64
+	Report Report
+}
+
+// MarshalJSON grava a rodada com o tamanho e o p95 ao lado do relatório,
+// para o JSON trazer a mesma comparação da tabela do plain. Sem latências
+// medidas o p95 é null.
+func (s SweepStep) MarshalJSON() ([]byte, error) {
+	var p95 *time.Duration
+	if s.Report.HasLatency() {
+		value := s.Report.Percentile(95)
+		p95 = &value
+	}
+	return json.Marshal(struct {
+		Size   int64          `json:"body_size"`
+		P95    *time.Duration `json:"p95"`
+		Report Report         `json:"report"`
+	}{s.Size, p95, s.Report})
+}
+
+// parseByteSizes interpreta uma lista como "1KB,10KB,100KB,1MB".
+func parseByteSizes(value string) ([]int64, error) {
+	var sizes []int64
+	for _, item := range strings.Split(value, ",") {
+		size, err := parseByteSize(item)
+		if err != nil {
+			return nil, err
+		}
+		if size <= 0 {
+			return nil, fmt.Errorf("invalid body size %q in -body-sizes", item)
+		}
+		sizes = append(sizes, size)
+	}
+	return sizes, nil
+}
+
+// FormatByteSize escreve o tamanho na maior unidade que o divide exatamente.
+func FormatByteSize(size int64) string {
+	units := []struct {
+		suffix string
+		size   int64
+	}{
+		{"GB", 1 << 30},
+		{"MB", 1 << 20},
+		{"KB", 1 << 10},
+	}
+	for _, unit := range units {
+		if size >= unit.size && size%unit.size == 0 {
+			return fmt.Sprintf("%d%s", size/unit.size, unit.suffix)
+		}
+	}
+	return fmt.Sprintf("%dB", size)
+}
@@ -0,0 +1,148 @@
+package loadtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+)
+
+// Target é uma requisição de um arquivo de alvos (-targets). As requisições
+// do teste percorrem os alvos em ordem; URL, headers e corpo aceitam
+// templates e os headers de -headers valem para todos.
+type Target struct {
+	Name    string            `json:"name,omitempty"`
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+	// Instante da requisição em relação à primeira, usado com -preserve-timing
+	OffsetMS float64 `json:"offset_ms,omitempty"`
+
+	url     *template.Template
+	body    *template.Template
+	headers map[string]*template.Template
+}
+
+func loadTargets(path string) ([]Target, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading targets file: %w", err)
+	}
+	var targets []Target
+	if err := json.Unmarshal(data, &targets); err != nil {
+		return nil, fmt.Errorf("parsing targets file: %w", err)
+	}
+	return targets, nil
+}
+
+func SaveTargets(path string, targets []Target) error {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false) // manter & e < legíveis nas URLs e corpos
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(targets); err != nil {
+		return err
+	}
+	return os.WriteFile(path, buf.Bytes(), 0o644)
+}
+
+// prepareTargets valida os alvos e pré-compila seus templates.
+func prepareTargets(targets []Target) error {
+	if len(targets) == 0 {
+		return fmt.Errorf("no targets to run")
+	}
+	for i := range targets {
+		t := &targets[i]
+		name := t.Name
+		if name == "" {
+			name = fmt.Sprintf("#%d", i+1)
+		}
+		if t.URL == "" {
+			return fmt.Errorf("target %s has no URL", name)
+		}
+		if t.Method == "" {
+			t.Method = http.MethodGet
+		}
+		t.Method = strings.ToUpper(t.Method)
+
+		var err error
+		if t.url, err = parseTemplate("url", t.URL); err != nil {
+			return fmt.Errorf("target %s: %w", name, err)
+		}
+		if t.body, err = parseTemplate("body", t.Body); err != nil {
+			return fmt.Errorf("target %s: %w", name, err)
+		}
+		if t.headers, err = parseHeaderTemplates(t.Headers); err != nil {
+			return fmt.Errorf("target %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// targetSchedule reproduz os intervalos originais entre os alvos. Cada
+// volta pela lista começa logo após o instante do último alvo.
+type targetSchedule struct {
+	targets []Target
+	span    time.Duration
+	once    sync.Once
+	start   time.Time
+}
+
+func newTargetSchedule(targets []Target) *targetSchedule {
+	s := &targetSchedule{targets: targets}
+	for _, t := range targets {
+		s.span = max(s.span, t.offset())
+	}
+	return s
+}
+
+func (t Target) offset() time.Duration {
+	return time.Duration(t.OffsetMS * float64(time.Millisecond))
+}
+
+// wait bloqueia até o instante da requisição seq na linha do tempo original.
+func (s *targetSchedule) wait(seq int64) {
+	s.once.Do(func() { s.start = time.Now() })
+	n := int64(len(s.targets))
+	pass := (seq - 1) / n
+	at := s.start.Add(time.Duration(pass)*s.span + s.targets[(seq-1)%n].offset())
+	time.Sleep(time.Until(at))
+}
+
+// apply devolve uma cópia da configuração com a requisição do alvo.
+func (t *Target) apply(config Config) Config {
+	config.Method = t.Method
+	config.URL = t.URL
+	config.URLTemplate = t.url
+	config.URLGlob = nil
+	config.Body = t.Body
+	config.BodyTemplate = t.body
+	config.BodyFile = ""
+	config.BodySize = 0
+	config.Form = nil
+
+	headers := make(map[string]string, len(config.Headers)+len(t.Headers))
+	templates := make(map[string]*template.Template, len(config.HeaderTemplates)+len(t.headers))
+	for k, v := range config.Headers {
+		headers[k] = v
+	}
+	for k, v := range config.HeaderTemplates {
+		templates[k] = v
+	}
+	for k, v := range t.Headers {
+		headers[k] = v
+		delete(templates, k)
+	}
+	for k, v := range t.headers {
+		templates[k] = v
+	}
+	config.Headers = headers
+	config.HeaderTemplates = templates
+	return config
+}
@@ -0,0 +1,140 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+)
+
+// TCPOptions configura o modo TCP: cada requisição abre uma conexão, envia
+// o payload (se houver) e lê a resposta.
+type TCPOptions struct {
+	Payload  []byte
+	ReadSize int // bytes esperados de volta
+}
+
+func newTCPOptions(payloadHex, payloadFile string, readSize int) (*TCPOptions, error) {
+	opts := &TCPOptions{}
+	switch {
+	case payloadHex != "" && payloadFile != "":
+		return nil, fmt.Errorf("-tcp-payload and -tcp-payload-file are mutually exclusive")
+	case payloadHex != "":
+		payload, err := hex.DecodeString(strings.ReplaceAll(payloadHex, " ", ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid -tcp-payload: %w", err)
+		}
+		opts.Payload = payload
+	case payloadFile != "":
+		payload, err := os.ReadFile(payloadFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading TCP payload: %w", err)
+		}
+		opts.Payload = payload
+	}
+
+	opts.ReadSize = readSize
+	if readSize < 0 {
+		opts.ReadSize = len(opts.Payload)
+	}
+	return opts, nil
+}
+
+type TCPStats struct {
+	Connections   int
+	Failed        int
+	BytesSent     int64
+	BytesReceived int64
+	EchoMismatch  int // resposta diferente do payload enviado
+	Connect       PhaseStats
+}
+
+func (s *TCPStats) add(result Result) {
+	if result.TCP == nil {
+		return
+	}
+	s.Connections++
+	if result.Error != nil {
+		s.Failed++
+	}
+	s.BytesSent += result.BytesSent
+	s.BytesReceived += result.BytesRead
+	if result.TCP.mismatch {
+		s.EchoMismatch++
+	}
+	s.Connect.add(result.Phases.TCPConnect)
+}
+
+type tcpResult struct {
+	mismatch bool
+}
+
+func executeTCPLoadTest(config Config) Report {
+	addr := strings.TrimPrefix(config.URL, "tcp://")
+	if u, err := url.Parse(config.URL); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+	// O mesmo dialer do modo HTTP: -connect-to, -resolve, -local-addr, -throttle...
+	dial := newDialContext(config)
+
+	report := runRequests(config, func(_ templateVars, _ *virtualUser, results chan<- Result) {
+		results <- makeTCPRequest(dial, config, addr)
+	})
+	report.Mode = "tcp"
+	return report
+}
+
+func makeTCPRequest(dial dialFunc, config Config, addr string) Result {
+	ctx, cancel := context.WithTimeout(config.context(), config.Timeout)
+	defer cancel()
+
+	start := time.Now()
+	conn, err := dial(ctx, "tcp", addr)
+	connect := time.Since(start)
+	if err != nil {
+		return Result{ErrorKind: ClassifyError(err), Duration: connect, Error: err, TCP: &tcpResult{}}
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	result := Result{
+		Phases:     PhaseTimings{TCPConnect: connect},
+		RemoteAddr: conn.RemoteAddr().String(),
+		TCP:        &tcpResult{},
+	}
+	if len(config.TCP.Payload) > 0 {
+		n, err := conn.Write(config.TCP.Payload)
+		result.BytesSent = int64(n)
+		if err != nil {
+			return tcpFailure(result, start, err)
+		}
+	}
+	if config.TCP.ReadSize > 0 {
+		reply := make([]byte, config.TCP.ReadSize)
+		n, err := io.ReadFull(conn, reply)
+		result.BytesRead = int64(n)
+		if err != nil {
+			return tcpFailure(result, start, err)
+		}
+		// Servidores de eco devolvem exatamente o payload
+		if config.TCP.ReadSize == len(config.TCP.Payload) && !bytes.Equal(reply, config.TCP.Payload) {
+			result.TCP.mismatch = true
+		}
+	}
+	result.Duration = time.Since(start)
+	return result
+}
+
+func tcpFailure(result Result, start time.Time, err error) Result {
+	result.Duration = time.Since(start)
+	result.Error = err
+	result.ErrorKind = ClassifyError(err)
+	return result
+}
@@ -0,0 +1,200 @@
+package loadtest
+
+import (
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"maps"
+	mathrand "math/rand/v2"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+	"time"
+)
+
+var (
+	fakeFirstNames = []string{
+		"Ana", "Bruno", "Carla", "Diego", "Eduarda", "Felipe", "Gabriela", "Henrique",
+		"Isabela", "João", "Larissa", "Lucas", "Mariana", "Nicolas", "Olivia", "Pedro",
+		"Rafaela", "Rodrigo", "Sofia", "Thiago", "Valentina", "William", "Yasmin", "Zeca",
+	}
+	fakeLastNames = []string{
+		"Almeida", "Barbosa", "Cardoso", "Costa", "Dias", "Ferreira", "Gomes", "Lima",
+		"Martins", "Melo", "Nunes", "Oliveira", "Pereira", "Ribeiro", "Rocha", "Santos",
+		"Silva", "Souza", "Teixeira", "Vieira",
+	}
+	fakeDomains = []string{"example.com", "example.org", "example.net", "test.com.br", "mail.test"}
+	loremIpsum  = strings.Fields("lorem ipsum dolor sit amet consectetur adipiscing elit sed do eiusmod " +
+		"tempor incididunt ut labore et dolore magna aliqua enim ad minim veniam quis nostrud " +
+		"exercitation ullamco laboris nisi aliquip ex ea commodo consequat duis aute irure in " +
+		"reprehenderit voluptate velit esse cillum fugiat nulla pariatur excepteur sint occaecat " +
+		"cupidatat non proident sunt culpa qui officia deserunt mollit anim id est laborum")
+)
+
+// templateFuncs são as funções disponíveis nos templates de requisição.
+var templateFuncs = template.FuncMap{
+	"timestamp": func() int64 { return time.Now().Unix() },
+	// Substituídas por requisição em renderTemplate
+	"seq":      func() int64 { return 0 },
+	"workerID": func() int { return 0 },
+}
+
+func init() {
+	maps.Copy(templateFuncs, randomFuncs(globalRand))
+}
+
+// randomFuncs são as funções aleatórias dos templates, ligadas ao gerador
+// da requisição para que o -seed as reproduza.
+func randomFuncs(r *mathrand.Rand) template.FuncMap {
+	return template.FuncMap{
+		"fakeFirstName": func() string { return pick(r, fakeFirstNames) },
+		"fakeLastName":  func() string { return pick(r, fakeLastNames) },
+		"fakeName":      func() string { return fakeName(r) },
+		"fakeEmail":     func() string { return fakeEmail(r) },
+		"fakePhone":     func() string { return fakePhone(r) },
+		"loremWords":    func(n int) string { return loremWords(r, n) },
+		"randInt":       func(min, max int) (int, error) { return randInt(r, min, max) },
+		"uuidv4":        func() string { return randomUUID(r) },
+	}
+}
+
+// templateVars identifica a requisição sendo renderizada. O mesmo seq é
+// usado na URL, nos headers e no corpo de uma requisição.
+type templateVars struct {
+	Seq      int64
+	WorkerID int
+	rand     *mathrand.Rand // gerador da requisição com -seed; nil usa o global
+}
+
+func (v templateVars) random() *mathrand.Rand {
+	if v.rand == nil {
+		return globalRand
+	}
+	return v.rand
+}
+
+func pick(r *mathrand.Rand, values []string) string {
+	return values[r.IntN(len(values))]
+}
+
+func fakeName(r *mathrand.Rand) string {
+	return pick(r, fakeFirstNames) + " " + pick(r, fakeLastNames)
+}
+
+// fakeEmail gera endereços variados o suficiente para não colidir com
+// facilidade em cargas longas.
+func fakeEmail(r *mathrand.Rand) string {
+	user := strings.ToLower(removeAccents(pick(r, fakeFirstNames) + "." + pick(r, fakeLastNames)))
+	return fmt.Sprintf("%s%d@%s", user, r.IntN(10000), pick(r, fakeDomains))
+}
+
+// fakePhone gera um celular no formato brasileiro, ex.: +55 11 91234-5678.
+func fakePhone(r *mathrand.Rand) string {
+	return fmt.Sprintf("+55 %d 9%04d-%04d", 11+r.IntN(89), r.IntN(10000), r.IntN(10000))
+}
+
+func loremWords(r *mathrand.Rand, n int) string {
+	words := make([]string, n)
+	for i := range words {
+		words[i] = pick(r, loremIpsum)
+	}
+	return strings.Join(words, " ")
+}
+
+// randInt retorna um inteiro no intervalo [min, max].
+func randInt(r *mathrand.Rand, min, max int) (int, error) {
+	if max < min {
+		return 0, fmt.Errorf("randInt: max %d is less than min %d", max, min)
+	}
+	return min + r.IntN(max-min+1), nil
+}
+
+// randomUUID é o uuidv4 dos templates, tirado do gerador da requisição.
+func randomUUID(r *mathrand.Rand) string {
+	var b [16]byte
+	for i := 0; i < len(b); i += 8 {
+		binary.LittleEndian.PutUint64(b[i:], r.Uint64())
+	}
+	return formatUUIDv4(b)
+}
+
+// UUIDv4 gera um UUID aleatório (RFC 9562, versão 4).
+func UUIDv4() string {
+	var b [16]byte
+	rand.Read(b[:])
+	return formatUUIDv4(b)
+}
+
+func formatUUIDv4(b [16]byte) string {
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:16])
+}
+
+func removeAccents(s string) string {
+	return strings.NewReplacer("á", "a", "ã", "a", "é", "e", "í", "i", "ó", "o", "ç", "c", "ú", "u").Replace(s)
+}
+
+// parseTemplate compila valores que contêm ações de template; valores
+// literais retornam nil e são usados sem processamento.
+func parseTemplate(name, text string) (*template.Template, error) {
+	if !strings.Contains(text, "{{") {
+		return nil, nil
+	}
+	tmpl, err := template.New(name).Funcs(templateFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s template: %w", name, err)
+	}
+	return tmpl, nil
+}
+
+func renderTemplate(tmpl *template.Template, vars templateVars) (string, error) {
+	tmpl, err := tmpl.Clone()
+	if err != nil {
+		return "", err
+	}
+	funcs := template.FuncMap{
+		"seq":      func() int64 { return vars.Seq },
+		"workerID": func() int { return vars.WorkerID },
+	}
+	if vars.rand != nil {
+		maps.Copy(funcs, randomFuncs(vars.rand))
+	}
+	tmpl.Funcs(funcs)
+
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, vars); err != nil {
+		return "", err
+	}
+	return sb.String(), nil
+}
+
+// loadTemplateFile compila um template de corpo externo (-body-template).
+// Diferente do -body, o arquivo é sempre tratado como template.
+func loadTemplateFile(path string) (*template.Template, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading body template: %w", err)
+	}
+	tmpl, err := template.New(filepath.Base(path)).Funcs(templateFuncs).Option("missingkey=error").Parse(string(data))
+	if err != nil {
+		return nil, fmt.Errorf("parsing body template: %w", err)
+	}
+	return tmpl, nil
+}
+
+// parseHeaderTemplates compila apenas os headers que usam templates.
+func parseHeaderTemplates(headers map[string]string) (map[string]*template.Template, error) {
+	templates := make(map[string]*template.Template)
+	for name, value := range headers {
+		tmpl, err := parseTemplate("header "+name, value)
+		if err != nil {
+			return nil, err
+		}
+		if tmpl != nil {
+			templates[name] = tmpl
+		}
+	}
+	return templates, nil
+}
@@ -0,0 +1,121 @@
+package loadtest
+
+import (
+	"fmt"
+	"math"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// parseBandwidth converte valores como 1Mbps, 512Kbps ou 100KB/s em bytes
+// por segundo. Taxas que arredondam para menos de 1 byte por segundo são
+// rejeitadas, pois o zero desligaria o limite.
+func parseBandwidth(value string) (int64, error) {
+	if value == "" {
+		return 0, nil
+	}
+
+	units := []struct {
+		suffix string
+		bytes  float64
+	}{
+		{"gbps", 1e9 / 8},
+		{"mbps", 1e6 / 8},
+		{"kbps", 1e3 / 8},
+		{"bps", 1.0 / 8},
+		{"gb/s", 1 << 30},
+		{"mb/s", 1 << 20},
+		{"kb/s", 1 << 10},
+		{"b/s", 1},
+	}
+
+	lower := strings.ToLower(strings.TrimSpace(value))
+	for _, unit := range units {
+		if strings.HasSuffix(lower, unit.suffix) {
+			number, err := strconv.ParseFloat(strings.TrimSuffix(lower, unit.suffix), 64)
+			if err != nil || !(number > 0) {
+				break
+			}
+			rate := math.Round(number * unit.bytes)
+			if rate < 1 {
+				return 0, fmt.Errorf("invalid -throttle %q: below 1 byte per second", value)
+			}
+			if rate >= math.MaxInt64 {
+				break
+			}
+			return int64(rate), nil
+		}
+	}
+	return 0, fmt.Errorf("invalid -throttle %q (use e.g. 1Mbps, 512Kbps, 100KB/s)", value)
+}
+
+// rateLimiter limita a vazão acumulada de uma direção da conexão.
+type rateLimiter struct {
+	mu    sync.Mutex
+	rate  int64 // bytes por segundo
+	start time.Time
+	total int64
+}
+
+func (l *rateLimiter) wait(n int) {
+	l.mu.Lock()
+	if l.start.IsZero() {
+		l.start = time.Now()
+	}
+	l.total += int64(n)
+	expected := time.Duration(float64(l.total) / float64(l.rate) * float64(time.Second))
+	delay := expected - time.Since(l.start)
+	l.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+}
+
+// chunk evita rajadas: cada operação transfere no máximo ~100ms de banda.
+func (l *rateLimiter) chunk(n int) int {
+	limit := int(l.rate / 10)
+	if limit < 1 {
+		limit = 1
+	}
+	return min(n, limit)
+}
+
+type throttledConn struct {
+	net.Conn
+	read  *rateLimiter
+	write *rateLimiter
+}
+
+func newThrottledConn(conn net.Conn, rate int64) net.Conn {
+	return &throttledConn{
+		Conn:  conn,
+		read:  &rateLimiter{rate: rate},
+		write: &rateLimiter{rate: rate},
+	}
+}
+
+func (c *throttledConn) Read(p []byte) (int, error) {
+	n, err := c.Conn.Read(p[:c.read.chunk(len(p))])
+	if n > 0 {
+		c.read.wait(n)
+	}
+	return n, err
+}
+
+func (c *throttledConn) Write(p []byte) (int, error) {
+	written := 0
+	for written < len(p) {
+		end := written + c.write.chunk(len(p)-written)
+		c.write.wait(end - written)
+		n, err := c.Conn.Write(p[written:end])
+		written += n
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
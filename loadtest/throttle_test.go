@@ -0,0 +1,41 @@
+package loadtest
+
+import "testing"
+
+func TestParseBandwidth(t *testing.T) {
+	tests := []struct {
+		value   string
+		want    int64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"1Mbps", 125000, false},
+		{"512Kbps", 64000, false},
+		{"100KB/s", 102400, false},
+		{"1GB/s", 1 << 30, false},
+		{" 8bps ", 1, false},
+		{"12bps", 2, false}, // 1,5 B/s arredonda para 2
+		{"1.5b/s", 2, false},
+		{"0.0001bps", 0, true},
+		{"3bps", 0, true}, // 0,375 B/s
+		{"0.4b/s", 0, true},
+		{"0Mbps", 0, true},
+		{"-1Mbps", 0, true},
+		{"nanMbps", 0, true},
+		{"infMbps", 0, true},
+		{"1e30GB/s", 0, true},
+		{"fast", 0, true},
+		{"10", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseBandwidth(tt.value)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseBandwidth(%q) error = %v, wantErr %v", tt.value, err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("parseBandwidth(%q) = %d, want %d", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package loadtest
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/youmark/pkcs8"
+	"software.sslmate.com/src/go-pkcs12"
+)
+
+type TLSOptions struct {
+	CertFile string
+	KeyFile  string
+	KeyPass  string
+	CAFile   string
+	Insecure bool
+	MinVer   string
+	MaxVer   string
+	Ciphers  string
+}
+
+func newTLSConfig(opts TLSOptions) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		InsecureSkipVerify: opts.Insecure,
+	}
+
+	var err error
+	if tlsConfig.MinVersion, err = parseTLSVersion(opts.MinVer); err != nil {
+		return nil, err
+	}
+	if tlsConfig.MaxVersion, err = parseTLSVersion(opts.MaxVer); err != nil {
+		return nil, err
+	}
+	if tlsConfig.MinVersion != 0 && tlsConfig.MaxVersion != 0 && tlsConfig.MinVersion > tlsConfig.MaxVersion {
+		return nil, fmt.Errorf("-tls-min %s is greater than -tls-max %s", opts.MinVer, opts.MaxVer)
+	}
+	if tlsConfig.CipherSuites, err = parseCipherSuites(opts.Ciphers); err != nil {
+		return nil, err
+	}
+
+	if opts.CAFile != "" {
+		pool, err := loadCAPool(opts.CAFile)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.CertFile != "" {
+		cert, err := loadClientCertificate(opts)
+		if err != nil {
+			return nil, err
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	return tlsConfig, nil
+}
+
+func parseTLSVersion(version string) (uint16, error) {
+	switch version {
+	case "":
+		return 0, nil // padrão do crypto/tls
+	case "1.0":
+		return tls.VersionTLS10, nil
+	case "1.1":
+		return tls.VersionTLS11, nil
+	case "1.2":
+		return tls.VersionTLS12, nil
+	case "1.3":
+		return tls.VersionTLS13, nil
+	default:
+		return 0, fmt.Errorf("invalid TLS version %q (use 1.0, 1.1, 1.2 or 1.3)", version)
+	}
+}
+
+// parseCipherSuites aceita nomes IANA separados por vírgula. As suítes do
+// TLS 1.3 não são configuráveis no crypto/tls e se aplicam apenas até o 1.2.
+func parseCipherSuites(list string) ([]uint16, error) {
+	if list == "" {
+		return nil, nil
+	}
+
+	known := make(map[string]uint16)
+	for _, suite := range tls.CipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+	for _, suite := range tls.InsecureCipherSuites() {
+		known[suite.Name] = suite.ID
+	}
+
+	var ids []uint16
+	for _, name := range strings.Split(list, ",") {
+		name = strings.TrimSpace(name)
+		id, ok := known[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cipher suite %q", name)
+		}
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+func loadCAPool(path string) (*x509.CertPool, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading CA bundle: %w", err)
+	}
+
+	// Partir das CAs do sistema para que destinos públicos continuem válidos
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("no PEM certificates found in %s", path)
+	}
+	return pool, nil
+}
+
+func loadClientCertificate(opts TLSOptions) (tls.Certificate, error) {
+	// Bundles PKCS#12 trazem certificado e chave no mesmo arquivo
+	ext := strings.ToLower(filepath.Ext(opts.CertFile))
+	if ext == ".p12" || ext == ".pfx" {
+		return loadPKCS12(opts.CertFile, opts.KeyPass)
+	}
+
+	if opts.KeyFile == "" {
+		return tls.Certificate{}, fmt.Errorf("-key is required when -cert is a PEM certificate")
+	}
+
+	certPEM, err := os.ReadFile(opts.CertFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("reading client certificate: %w", err)
+	}
+	keyPEM, err := os.ReadFile(opts.KeyFile)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("reading client key: %w", err)
+	}
+
+	if opts.KeyPass != "" {
+		keyPEM, err = decryptPEMKey(keyPEM, opts.KeyPass)
+		if err != nil {
+			return tls.Certificate{}, err
+		}
+	}
+
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("loading client key pair: %w", err)
+	}
+	return cert, nil
+}
+
+func decryptPEMKey(keyPEM []byte, password string) ([]byte, error) {
+	block, _ := pem.Decode(keyPEM)
+	if block == nil {
+		return nil, fmt.Errorf("client key is not PEM encoded")
+	}
+
+	switch {
+	case block.Type == "ENCRYPTED PRIVATE KEY":
+		// PKCS#8 criptografado (formato padrão do openssl moderno)
+		key, err := pkcs8.ParsePKCS8PrivateKey(block.Bytes, []byte(password))
+		if err != nil {
+			return nil, fmt.Errorf("decrypting client key: %w", err)
+		}
+		der, err := x509.MarshalPKCS8PrivateKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("encoding client key: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), nil
+	case x509.IsEncryptedPEMBlock(block):
+		// Formato legado com cabeçalho Proc-Type: 4,ENCRYPTED
+		der, err := x509.DecryptPEMBlock(block, []byte(password))
+		if err != nil {
+			return nil, fmt.Errorf("decrypting client key: %w", err)
+		}
+		return pem.EncodeToMemory(&pem.Block{Type: block.Type, Bytes: der}), nil
+	default:
+		// Chave não criptografada, a senha é ignorada
+		return keyPEM, nil
+	}
+}
+
+func loadPKCS12(path, password string) (tls.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("reading PKCS#12 bundle: %w", err)
+	}
+
+	key, cert, chain, err := pkcs12.DecodeChain(data, password)
+	if err != nil {
+		return tls.Certificate{}, fmt.Errorf("decoding PKCS#12 bundle: %w", err)
+	}
+
+	tlsCert := tls.Certificate{
+		Certificate: [][]byte{cert.Raw},
+		PrivateKey:  key,
+		Leaf:        cert,
+	}
+	for _, c := range chain {
+		tlsCert.Certificate = append(tlsCert.Certificate, c.Raw)
+	}
+	return tlsCert, nil
+}
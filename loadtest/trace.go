@@ -0,0 +1,250 @@
+package loadtest
+
+import (
+	"crypto/tls"
+	"net/http/httptrace"
+	"sync"
+	"time"
+)
+
+type PhaseTimings struct {
+	DNSLookup       time.Duration
+	TCPConnect      time.Duration
+	TLSHandshake    time.Duration
+	RequestUpload   time.Duration
+	TimeToFirstByte time.Duration
+	ContentTransfer time.Duration
+}
+
+// ConnectionSetup soma o custo de estabelecer uma conexão nova.
+func (t PhaseTimings) ConnectionSetup() time.Duration {
+	return t.DNSLookup + t.TCPConnect + t.TLSHandshake
+}
+
+type PhaseStats struct {
+	Count int
+	Min   time.Duration
+	Max   time.Duration
+	Avg   time.Duration
+	total time.Duration
+}
+
+type PhaseBreakdown struct {
+	DNSLookup       PhaseStats
+	TCPConnect      PhaseStats
+	TLSHandshake    PhaseStats
+	RequestUpload   PhaseStats
+	TimeToFirstByte PhaseStats
+	ContentTransfer PhaseStats
+	ConnectionSetup PhaseStats
+}
+
+func (p *PhaseStats) add(d time.Duration) {
+	if d <= 0 {
+		return
+	}
+	p.Count++
+	p.total += d
+	if p.Min == 0 || d < p.Min {
+		p.Min = d
+	}
+	if d > p.Max {
+		p.Max = d
+	}
+	p.Avg = p.total / time.Duration(p.Count)
+}
+
+func (b *PhaseBreakdown) add(t PhaseTimings) {
+	b.DNSLookup.add(t.DNSLookup)
+	b.TCPConnect.add(t.TCPConnect)
+	b.TLSHandshake.add(t.TLSHandshake)
+	b.RequestUpload.add(t.RequestUpload)
+	b.TimeToFirstByte.add(t.TimeToFirstByte)
+	b.ContentTransfer.add(t.ContentTransfer)
+	b.ConnectionSetup.add(t.ConnectionSetup())
+}
+
+// requestTracer registra os instantes de cada fase de uma requisição.
+// Os callbacks do httptrace podem ser chamados de outras goroutines.
+type requestTracer struct {
+	mu           sync.Mutex
+	dnsStart     time.Time
+	connectStart time.Time
+	tlsStart     time.Time
+	gotConn      time.Time
+	wroteRequest time.Time
+	firstByte    time.Time
+	remoteAddr   string
+	reused       bool
+	got100       bool
+	tlsFailed    bool
+	phases       PhaseTimings
+}
+
+func (t *requestTracer) clientTrace() *httptrace.ClientTrace {
+	return &httptrace.ClientTrace{
+		DNSStart: func(httptrace.DNSStartInfo) {
+			t.mu.Lock()
+			t.dnsStart = time.Now()
+			t.mu.Unlock()
+		},
+		DNSDone: func(httptrace.DNSDoneInfo) {
+			t.mu.Lock()
+			t.phases.DNSLookup = time.Since(t.dnsStart)
+			t.mu.Unlock()
+		},
+		ConnectStart: func(string, string) {
+			t.mu.Lock()
+			t.connectStart = time.Now()
+			t.mu.Unlock()
+		},
+		ConnectDone: func(_, _ string, err error) {
+			t.mu.Lock()
+			if err == nil {
+				t.phases.TCPConnect = time.Since(t.connectStart)
+			}
+			t.mu.Unlock()
+		},
+		TLSHandshakeStart: func() {
+			t.mu.Lock()
+			t.tlsStart = time.Now()
+			t.mu.Unlock()
+		},
+		TLSHandshakeDone: func(_ tls.ConnectionState, err error) {
+			t.mu.Lock()
+			if err == nil {
+				t.phases.TLSHandshake = time.Since(t.tlsStart)
+			}
+			t.tlsFailed = err != nil
+			t.mu.Unlock()
+		},
+		GotConn: func(info httptrace.GotConnInfo) {
+			t.mu.Lock()
+			if info.Conn != nil {
+				t.remoteAddr = info.Conn.RemoteAddr().String()
+			}
+			t.reused = info.Reused
+			t.gotConn = time.Now()
+			t.mu.Unlock()
+		},
+		WroteRequest: func(httptrace.WroteRequestInfo) {
+			t.mu.Lock()
+			t.wroteRequest = time.Now()
+			if !t.gotConn.IsZero() {
+				t.phases.RequestUpload = t.wroteRequest.Sub(t.gotConn)
+			}
+			t.mu.Unlock()
+		},
+		Got100Continue: func() {
+			t.mu.Lock()
+			t.got100 = true
+			t.mu.Unlock()
+		},
+		GotFirstResponseByte: func() {
+			t.mu.Lock()
+			t.firstByte = time.Now()
+			if !t.wroteRequest.IsZero() {
+				t.phases.TimeToFirstByte = t.firstByte.Sub(t.wroteRequest)
+			}
+			t.mu.Unlock()
+		},
+	}
+}
+
+// refine detalha a falha pela fase em que a requisição parou, para os
+// erros que a net/http não expõe com tipo próprio: timeouts sem conexão, no
+// handshake TLS ou esperando os headers, e handshakes TLS recusados.
+func (t *requestTracer) refine(kind ErrorKind) ErrorKind {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	switch {
+	case kind == ErrorOther && t.tlsFailed:
+		return ErrorTLS
+	case kind != ErrorTimeout:
+		return kind
+	case t.gotConn.IsZero() && !t.tlsStart.IsZero():
+		return ErrorTLSTimeout
+	case t.gotConn.IsZero():
+		return ErrorConnectTimeout
+	case t.firstByte.IsZero():
+		return ErrorResponseTimeout
+	}
+	return kind
+}
+
+// finish fecha a medição após a leitura completa do corpo da resposta.
+func (t *requestTracer) finish() PhaseTimings {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if !t.firstByte.IsZero() {
+		t.phases.ContentTransfer = time.Since(t.firstByte)
+	}
+	return t.phases
+}
+
+func (t *requestTracer) RemoteAddr() string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.remoteAddr
+}
+
+func (t *requestTracer) Reused() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.reused
+}
+
+func (t *requestTracer) Got100Continue() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.got100
+}
+
+type NamedPhase struct {
+	Name  string
+	Stats PhaseStats
+}
+
+func (b PhaseBreakdown) List() []NamedPhase {
+	return []NamedPhase{
+		{"DNS Lookup", b.DNSLookup},
+		{"TCP Connect", b.TCPConnect},
+		{"TLS Handshake", b.TLSHandshake},
+		{"Request Upload", b.RequestUpload},
+		{"Time to First Byte", b.TimeToFirstByte},
+		{"Content Transfer", b.ContentTransfer},
+		{"Connection Setup", b.ConnectionSetup},
+	}
+}
+
+// Merge combina estatísticas de fase. O total não é exportado, então é
+// reconstruído a partir da média quando o relatório veio de JSON.
+func (p *PhaseStats) Merge(o PhaseStats) {
+	if o.Count == 0 {
+		return
+	}
+	if p.Count > 0 && p.total == 0 {
+		p.total = p.Avg * time.Duration(p.Count)
+	}
+	otherTotal := o.total
+	if otherTotal == 0 {
+		otherTotal = o.Avg * time.Duration(o.Count)
+	}
+	if p.Min == 0 || (o.Min > 0 && o.Min < p.Min) {
+		p.Min = o.Min
+	}
+	p.Max = max(p.Max, o.Max)
+	p.Count += o.Count
+	p.total += otherTotal
+	p.Avg = p.total / time.Duration(p.Count)
+}
+
+func (b *PhaseBreakdown) Merge(o PhaseBreakdown) {
+	b.DNSLookup.Merge(o.DNSLookup)
+	b.TCPConnect.Merge(o.TCPConnect)
+	b.TLSHandshake.Merge(o.TLSHandshake)
+	b.RequestUpload.Merge(o.RequestUpload)
+	b.TimeToFirstByte.Merge(o.TimeToFirstByte)
+	b.ContentTransfer.Merge(o.ContentTransfer)
+	b.ConnectionSetup.Merge(o.ConnectionSetup)
+}
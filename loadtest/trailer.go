@@ -0,0 +1,75 @@
+package loadtest
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Limite de valores distintos contabilizados por trailer; trailers como
+// checksums mudam a cada resposta e fariam o relatório crescer sem limite.
+const maxTrailerValues = 20
+
+// Headers que não podem ser enviados como trailer (RFC 9110, seção 6.5.1)
+var forbiddenTrailers = map[string]bool{
+	"Authorization":     true,
+	"Cache-Control":     true,
+	"Content-Encoding":  true,
+	"Content-Length":    true,
+	"Content-Range":     true,
+	"Content-Type":      true,
+	"Expect":            true,
+	"Host":              true,
+	"Max-Forwards":      true,
+	"Te":                true,
+	"Trailer":           true,
+	"Transfer-Encoding": true,
+}
+
+// parseTrailers interpreta valores 'Name:value' de -trailer.
+func parseTrailers(values []string) (http.Header, error) {
+	if len(values) == 0 {
+		return nil, nil
+	}
+	trailers := make(http.Header)
+	for _, value := range values {
+		name, val, ok := strings.Cut(value, ":")
+		name = http.CanonicalHeaderKey(strings.TrimSpace(name))
+		if !ok || name == "" {
+			return nil, fmt.Errorf("invalid -trailer %q (use 'Name:value')", value)
+		}
+		if forbiddenTrailers[name] {
+			return nil, fmt.Errorf("%s cannot be sent as a trailer", name)
+		}
+		trailers.Add(name, strings.TrimSpace(val))
+	}
+	return trailers, nil
+}
+
+// TrailerStats conta os valores recebidos em cada trailer de resposta.
+type TrailerStats struct {
+	Responses int                       // respostas com pelo menos um trailer
+	Values    map[string]map[string]int // nome -> valor -> contagem
+}
+
+func (s *TrailerStats) add(trailers http.Header) {
+	if len(trailers) == 0 {
+		return
+	}
+	if s.Values == nil {
+		s.Values = make(map[string]map[string]int)
+	}
+	s.Responses++
+	for name, values := range trailers {
+		counts, ok := s.Values[name]
+		if !ok {
+			counts = make(map[string]int)
+			s.Values[name] = counts
+		}
+		value := strings.Join(values, ", ")
+		if _, seen := counts[value]; !seen && len(counts) >= maxTrailerValues {
+			value = "(other)"
+		}
+		counts[value]++
+	}
+}
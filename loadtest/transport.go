@@ -0,0 +1,106 @@
+package loadtest
+
+import (
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+)
+
+func newHTTPClient(config Config) (*http.Client, error) {
+	transport, err := newTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	var rt http.RoundTripper = transport
+	if config.NTLM != nil {
+		rt = ntlmTransport(transport)
+	}
+	client := &http.Client{
+		Timeout:       config.Timeout,
+		Transport:     rt,
+		CheckRedirect: redirectPolicy(config),
+	}
+	if config.SSE != nil {
+		// Conexões SSE ficam abertas além do -timeout; o limite é aplicado
+		// até os headers pela própria sessão
+		client.Timeout = 0
+	}
+	return client, nil
+}
+
+func newTransport(config Config) (*http.Transport, error) {
+	transport := &http.Transport{
+		MaxIdleConns:          config.MaxIdleConns,
+		MaxIdleConnsPerHost:   config.MaxIdleConnsPerHost,
+		MaxConnsPerHost:       config.MaxConnsPerHost,
+		IdleConnTimeout:       config.IdleConnTimeout,
+		WriteBufferSize:       config.WriteBufferSize,
+		ReadBufferSize:        config.ReadBufferSize,
+		DisableCompression:    true,
+		DialContext:           newDialContext(config),
+		DisableKeepAlives:     config.DisableKeepAlive,
+		TLSHandshakeTimeout:   config.TLSTimeout,
+		ResponseHeaderTimeout: config.ResponseHeaderTimeout,
+	}
+	if config.ExpectContinue {
+		transport.ExpectContinueTimeout = config.ContinueTimeout
+	}
+
+	// Por padrão o pool de conexões ociosas acompanha a concorrência
+	if transport.MaxIdleConnsPerHost == 0 {
+		transport.MaxIdleConnsPerHost = max(config.Concurrency, 100)
+	}
+	if transport.MaxIdleConns == 0 {
+		transport.MaxIdleConns = transport.MaxIdleConnsPerHost
+	}
+
+	protocols, err := parseHTTPVersion(config.HTTPVersion)
+	if err != nil {
+		return nil, err
+	}
+	transport.Protocols = protocols
+	if config.NTLM != nil {
+		// NTLM autentica a conexão e não funciona sobre HTTP/2
+		transport.Protocols = new(http.Protocols)
+		transport.Protocols.SetHTTP1(true)
+	}
+
+	if config.TLSConfig != nil {
+		transport.TLSClientConfig = config.TLSConfig.Clone()
+	}
+	if config.HostHeader != "" {
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{}
+		}
+		transport.TLSClientConfig.ServerName = hostWithoutPort(config.HostHeader)
+	}
+
+	return transport, nil
+}
+
+func parseHTTPVersion(version string) (*http.Protocols, error) {
+	protocols := new(http.Protocols)
+	switch version {
+	case "", "auto":
+		// HTTP/2 via ALPN quando disponível, HTTP/1.1 caso contrário
+		protocols.SetHTTP1(true)
+		protocols.SetHTTP2(true)
+	case "1.1":
+		protocols.SetHTTP1(true)
+	case "2":
+		protocols.SetHTTP2(true)
+	case "h2c":
+		protocols.SetUnencryptedHTTP2(true)
+	default:
+		return nil, fmt.Errorf("invalid http version %q (use auto, 1.1, 2 or h2c)", version)
+	}
+	return protocols, nil
+}
+
+func hostWithoutPort(hostport string) string {
+	if host, _, err := net.SplitHostPort(hostport); err == nil {
+		return host
+	}
+	return hostport
+}
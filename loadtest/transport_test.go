@@ -0,0 +1,45 @@
+package loadtest
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+)
+
+// Todas as requisições de um teste usam o mesmo cliente, então o servidor
+// vê no máximo uma conexão por worker em vez de uma por requisição.
+func TestRunReusesConnections(t *testing.T) {
+	var opened atomic.Int64
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			opened.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	const requests, concurrency = 200, 4
+	config, err := parseFlags(t, "-url", server.URL, "-requests", strconv.Itoa(requests), "-concurrency", strconv.Itoa(concurrency))
+	if err != nil {
+		t.Fatal(err)
+	}
+	config.Quiet = true
+	report, err := Run(context.Background(), config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.TotalRequests != requests || report.Errors != 0 {
+		t.Fatalf("%d requests with %d errors, want %d without errors", report.TotalRequests, report.Errors, requests)
+	}
+	if n := opened.Load(); n > concurrency {
+		t.Errorf("server saw %d connections for %d requests, want at most %d", n, requests, concurrency)
+	}
+	if report.ReusedConns < requests-concurrency {
+		t.Errorf("ReusedConns = %d, want at least %d", report.ReusedConns, requests-concurrency)
+	}
+}
@@ -0,0 +1,184 @@
+package loadtest
+
+import (
+	"fmt"
+	"maps"
+	"os"
+	"slices"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// O painel de terminal substitui a linha de progresso quando a saída é um
+// terminal. Ele usa a tela alternativa, então o relatório final aparece
+// normalmente depois que o teste termina.
+
+var sparkBlocks = []rune("▁▂▃▄▅▆▇█")
+
+func StdoutIsTerminal() bool {
+	return term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// runTUI redesenha o painel a cada 500ms com o contador de requisições
+// concluídas até finished ser fechado. done é fechado depois que a tela é
+// restaurada.
+func runTUI(config Config, completed *atomic.Int64, finished <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+	total, live := config.Requests, config.Live
+	fmt.Print("\x1b[?1049h\x1b[?25l") // tela alternativa, cursor oculto
+	defer fmt.Print("\x1b[?25h\x1b[?1049l")
+	defer readKeys(config.Control, config.abort)()
+
+	start := time.Now()
+	ticker := time.NewTicker(500 * time.Millisecond)
+	defer ticker.Stop()
+	var rps []float64
+	lastSecond := int64(-1)
+
+	for {
+		select {
+		case <-finished:
+			return
+		case <-ticker.C:
+			snapshot := live.Snapshot()
+			if sec := int64(time.Since(start).Seconds()); sec != lastSecond {
+				lastSecond = sec
+				rps = append(rps, snapshot.RPS)
+			}
+			drawTUI(total, int(completed.Load()), start, rps, snapshot, config.Control.Current())
+		}
+	}
+}
+
+func drawTUI(total, current int, start time.Time, rps []float64, s LiveSnapshot, concurrency int) {
+	width, height, err := term.GetSize(int(os.Stdout.Fd()))
+	if err != nil || width < 40 {
+		width, height = 80, 24
+	}
+
+	var b strings.Builder
+	b.WriteString("\x1b[H") // redesenhar por cima evita o piscar de limpar a tela
+	line := func(format string, args ...any) {
+		// \r porque o modo raw do readKeys desliga a conversão de \n
+		fmt.Fprintf(&b, format+"\x1b[K\r\n", args...)
+	}
+
+	elapsed := time.Since(start)
+	percent := float64(current) / float64(total)
+	eta := "-"
+	if current > 0 && current < total {
+		eta = (time.Duration(float64(elapsed)/percent) - elapsed).Round(time.Second).String()
+	}
+	line("📊 Stress Test  %s elapsed | ETA %s", elapsed.Round(time.Second), eta)
+	if concurrency > 0 {
+		line("👥 Concurrency %d (+/- to change)", concurrency)
+	}
+	line("")
+
+	barWidth := width - 30
+	filled := int(percent * float64(barWidth))
+	line("[%s%s] %5.1f%% (%d/%d)", strings.Repeat("█", filled), strings.Repeat("░", barWidth-filled), percent*100, current, total)
+	line("")
+
+	line("⚡ RPS %-8.0f %s", s.RPS, sparkline(rps, width-16))
+	line("⏱️  P50 %.1fms | P90 %.1fms | P95 %.1fms | P99 %.1fms (last %d requests)",
+		s.P50, s.P90, s.P95, s.P99, min(s.Total, liveWindow))
+	line("")
+
+	line("📈 Status Codes")
+	codes := make([]int, 0, len(s.Status))
+	for code := range s.Status {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+	for _, code := range codes {
+		icon := "✅"
+		if code >= 400 {
+			icon = "❌"
+		}
+		line("   %s %d: %d", icon, code, s.Status[code])
+	}
+	kinds := slices.Sorted(maps.Keys(s.Kinds))
+	for _, kind := range kinds {
+		line("   ❌ %s: %d", kind, s.Kinds[kind])
+	}
+	line("")
+
+	// O log de erros ocupa o restante da tela
+	line("❌ Errors: %d", s.Errors)
+	used := 10 + len(codes) + len(kinds)
+	if concurrency > 0 {
+		used++
+	}
+	room := height - used - 1
+	for i := len(s.Feed) - 1; i >= 0 && room > 0; i-- {
+		e := s.Feed[i]
+		msg := fmt.Sprintf("   %s [%s] %s", e.Time.Format("15:04:05"), e.label(), e.Message)
+		if len([]rune(msg)) > width {
+			msg = string([]rune(msg)[:width-1]) + "…"
+		}
+		line("%s", msg)
+		room--
+	}
+	b.WriteString("\x1b[J")
+	fmt.Print(b.String())
+}
+
+// readKeys lê o teclado com o terminal em modo raw: + e - mudam a
+// concorrência em 10% (no mínimo 1) e Ctrl+C, que no modo raw não vira
+// SIGINT, aborta o teste. A função devolvida restaura o terminal.
+func readKeys(control *ConcurrencyControl, abort func()) func() {
+	fd := int(os.Stdin.Fd())
+	if control == nil || abort == nil || !term.IsTerminal(fd) {
+		return func() {}
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return func() {}
+	}
+	go func() {
+		buf := make([]byte, 16)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			for _, key := range buf[:n] {
+				step := max(1, control.Current()/10)
+				switch key {
+				case '+', '=':
+					control.Adjust(step)
+				case '-', '_':
+					control.Adjust(-step)
+				case 3: // Ctrl+C
+					abort()
+				}
+			}
+		}
+	}()
+	return func() { term.Restore(fd, state) }
+}
+
+// sparkline desenha os últimos valores que cabem em width colunas.
+func sparkline(values []float64, width int) string {
+	if len(values) > width {
+		values = values[len(values)-width:]
+	}
+	peak := 0.0
+	for _, v := range values {
+		peak = max(peak, v)
+	}
+	var b strings.Builder
+	for _, v := range values {
+		i := 0
+		if peak > 0 {
+			i = int(v / peak * float64(len(sparkBlocks)-1))
+		}
+		b.WriteRune(sparkBlocks[i])
+	}
+	return b.String()
+}
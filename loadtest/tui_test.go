@@ -0,0 +1,25 @@
+package loadtest
+
+import "testing"
+
+func TestSparkline(t *testing.T) {
+	tests := []struct {
+		name   string
+		values []float64
+		width  int
+		want   string
+	}{
+		{"empty", nil, 10, ""},
+		{"zeros", []float64{0, 0, 0}, 10, "▁▁▁"},
+		{"scaled to the peak", []float64{0, 50, 100}, 10, "▁▄█"},
+		{"every block", []float64{0, 1, 2, 3, 4, 5, 6, 7}, 10, "▁▂▃▄▅▆▇█"},
+		{"keeps the latest values", []float64{100, 1, 2, 4}, 3, "▂▄█"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := sparkline(tt.values, tt.width); got != tt.want {
+				t.Errorf("sparkline(%v, %d) = %q, want %q", tt.values, tt.width, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package loadtest
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// UDPOptions configura o modo UDP: cada requisição envia um datagrama e,
+// opcionalmente, espera a resposta para medir RTT e perda.
+type UDPOptions struct {
+	Payload   []byte
+	Rate      int  // datagramas por segundo no total, 0 = sem limite
+	WaitReply bool // esperar a resposta de cada datagrama
+}
+
+func newUDPOptions(payloadHex string, size int64, rate int, waitReply bool) (*UDPOptions, error) {
+	opts := &UDPOptions{Rate: rate, WaitReply: waitReply}
+	switch {
+	case payloadHex != "" && size > 0:
+		return nil, fmt.Errorf("-udp-payload and -udp-size are mutually exclusive")
+	case payloadHex != "":
+		payload, err := hex.DecodeString(strings.ReplaceAll(payloadHex, " ", ""))
+		if err != nil {
+			return nil, fmt.Errorf("invalid -udp-payload: %w", err)
+		}
+		opts.Payload = payload
+	default:
+		if size <= 0 {
+			size = 64
+		}
+		if size > 65507 {
+			return nil, fmt.Errorf("-udp-size %d exceeds the maximum UDP payload (65507 bytes)", size)
+		}
+		opts.Payload = make([]byte, size)
+		patternReader{}.Read(opts.Payload)
+	}
+	if rate < 0 {
+		return nil, fmt.Errorf("-udp-rate must not be negative")
+	}
+	return opts, nil
+}
+
+type UDPStats struct {
+	Sent     int
+	Replies  int
+	Lost     int
+	LossRate float64
+	Mismatch int // resposta diferente do datagrama enviado
+	RTT      PhaseStats
+}
+
+type udpResult struct {
+	replied  bool
+	lost     bool
+	mismatch bool
+}
+
+var errUDPNoReply = errors.New("no UDP reply before timeout")
+
+func (s *UDPStats) add(result Result) {
+	if result.UDP == nil || result.BytesSent == 0 {
+		return
+	}
+	s.Sent++
+	switch {
+	case result.UDP.replied:
+		s.Replies++
+		s.RTT.add(result.Duration)
+	case result.UDP.lost:
+		s.Lost++
+	}
+	if result.UDP.mismatch {
+		s.Mismatch++
+	}
+	if s.Replies+s.Lost > 0 {
+		s.LossRate = float64(s.Lost) / float64(s.Replies+s.Lost) * 100
+	}
+}
+
+func executeUDPLoadTest(config Config) Report {
+	addr := strings.TrimPrefix(config.URL, "udp://")
+	if u, err := url.Parse(config.URL); err == nil && u.Host != "" {
+		addr = u.Host
+	}
+	dial := newDialContext(config)
+
+	// Cadência global de envio (-udp-rate)
+	var tick <-chan time.Time
+	if config.UDP.Rate > 0 {
+		ticker := time.NewTicker(time.Second / time.Duration(config.UDP.Rate))
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	report := runRequests(config, func(_ templateVars, _ *virtualUser, results chan<- Result) {
+		if tick != nil {
+			<-tick
+		}
+		results <- makeUDPRequest(dial, config, addr)
+	})
+	report.Mode = "udp"
+	return report
+}
+
+func makeUDPRequest(dial dialFunc, config Config, addr string) Result {
+	ctx, cancel := context.WithTimeout(config.context(), config.Timeout)
+	defer cancel()
+
+	result := Result{UDP: &udpResult{}}
+	conn, err := dial(ctx, "udp", addr)
+	if err != nil {
+		result.Error = err
+		result.ErrorKind = ClassifyError(err)
+		return result
+	}
+	defer conn.Close()
+	result.RemoteAddr = conn.RemoteAddr().String()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	start := time.Now()
+	n, err := conn.Write(config.UDP.Payload)
+	result.BytesSent = int64(n)
+	if err != nil || !config.UDP.WaitReply {
+		result.Duration = time.Since(start)
+		result.Error = err
+		if err != nil {
+			result.ErrorKind = ClassifyError(err)
+		}
+		return result
+	}
+
+	reply := make([]byte, 65535)
+	n, err = conn.Read(reply)
+	result.Duration = time.Since(start)
+	result.BytesRead = int64(n)
+	var netErr net.Error
+	switch {
+	case errors.As(err, &netErr) && netErr.Timeout():
+		// Sem resposta não há RTT; a espera não entra nas estatísticas
+		result.Duration = 0
+		result.UDP.lost = true
+		result.Error = errUDPNoReply
+		result.ErrorKind = ErrorTimeout
+	case err != nil:
+		// ICMP port unreachable aparece como connection refused
+		result.Error = err
+		result.ErrorKind = ClassifyError(err)
+	default:
+		result.UDP.replied = true
+		result.UDP.mismatch = !bytes.Equal(reply[:n], config.UDP.Payload)
+	}
+	return result
+}
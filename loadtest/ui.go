@@ -0,0 +1,58 @@
+package loadtest
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/http"
+)
+
+//go:embed ui.html
+var uiPage []byte
+
+// StartUI serve o painel web em addr. O botão de parar fecha stop; as
+// requisições em andamento terminam e as restantes não são enviadas. Os
+// botões de concorrência e POST /api/concurrency usam control.
+func StartUI(addr string, live *LiveStats, stop *StopSignal, control *ConcurrencyControl) (*http.Server, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Write(uiPage)
+	})
+	mux.HandleFunc("GET /api/stats", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(struct {
+			LiveSnapshot
+			Concurrency int `json:"concurrency"`
+		}{live.Snapshot(), control.Current()})
+	})
+	mux.HandleFunc("POST /api/stop", func(w http.ResponseWriter, r *http.Request) {
+		stop.Stop()
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /api/concurrency", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Concurrency int `json:"concurrency"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+		if err := control.Set(req.Concurrency); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("starting web UI: %w", err)
+	}
+	server := &http.Server{Handler: mux}
+	go server.Serve(listener)
+	slog.Info("live dashboard", "url", "http://"+listener.Addr().String())
+	return server, nil
+}
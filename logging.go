@@ -0,0 +1,53 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+)
+
+// Avisos, erros e mensagens de estado passam pelo slog e vão para o stderr;
+// o relatório e o progresso continuam fora dele.
+
+// logFlags registra -log-level e -log-format em fs e devolve a função que
+// monta o logger depois do parse. Com quiet, o nível padrão passa a ser
+// warn, a menos que -log-level tenha sido informado (explicit).
+func logFlags(fs *flag.FlagSet) func(quiet bool, explicit func(string) bool) (*slog.Logger, error) {
+	levelFlag := fs.String("log-level", "info", "Minimum level of the log messages written to stderr (debug, info, warn, error)")
+	formatFlag := fs.String("log-format", "text", "Format of the log messages (text, json)")
+	return func(quiet bool, explicit func(string) bool) (*slog.Logger, error) {
+		level := *levelFlag
+		if quiet && !explicit("log-level") {
+			level = "warn"
+		}
+		return newLogger(level, *formatFlag)
+	}
+}
+
+// flagSet informa se a flag foi passada na linha de comando.
+func flagSet(fs *flag.FlagSet, name string) bool {
+	set := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+func newLogger(level, format string) (*slog.Logger, error) {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return nil, fmt.Errorf("invalid -log-level %q (use debug, info, warn or error)", level)
+	}
+	opts := &slog.HandlerOptions{Level: lvl}
+	switch format {
+	case "text":
+		return slog.New(slog.NewTextHandler(os.Stderr, opts)), nil
+	case "json":
+		return slog.New(slog.NewJSONHandler(os.Stderr, opts)), nil
+	default:
+		return nil, fmt.Errorf("invalid -log-format %q (use text or json)", format)
+	}
+}
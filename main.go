@@ -1,546 +1,590 @@
 package main
 
 import (
-	"encoding/json"
+	"context"
+	"errors"
 	"flag"
 	"fmt"
-	"math"
-	"net"
-	"net/http"
-	"sort"
+	"io"
+	"log/slog"
+	"math/rand/v2"
+	"net/url"
+	"os"
+	"os/signal"
+	"slices"
 	"strings"
-	"sync"
+	"syscall"
 	"time"
-)
-
-type Result struct {
-	StatusCode int
-	Duration   time.Duration
-	Error      error
-}
-
-type ReportExporter interface {
-	Export(Report) string
-}
-
-type JSONExporter struct{}
 
-type CSVExporter struct{}
+	"fullcycle-goexpert-desafio-stress-test/export"
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
 
-func (j JSONExporter) Export(r Report) string {
-	data, _ := json.MarshalIndent(r, "", " ")
-	return string(data)
+// cliOptions reúne as opções da linha de comando que controlam a execução
+// e não fazem parte da configuração do teste.
+type cliOptions struct {
+	RequestLog         string
+	UI                 string
+	NoTUI              bool
+	Watch              string
+	Checkpoint         string
+	CheckpointInterval time.Duration
+	Resume             string
+	Workers            []string
+	Webhook            string
+	Thresholds         report.Thresholds
+	SlackWebhook       string
+	TeamsWebhook       string
+	Name               string
+	Upload             *reportUpload
+	History            string
+	Args               []string // argumentos do teste, gravados no -history
+	Baseline           *loadtest.Report
+	Tolerances         report.Tolerances
+	DryRun             bool
+	DebugRequest       bool
+	Quiet              bool
+	NoColor            bool
+	Lang               string
 }
 
-func (c CSVExporter) Export(r Report) string {
-	var sb strings.Builder
-	// Cabeçalho
-	sb.WriteString("Total Time (s),Total Requests,RPS,Min Duration (ms),Max Duration (ms),Avg Duration (ms),Errors\n")
-	// Dados principais
-	sb.WriteString(fmt.Sprintf("%.2f,%d,%.2f,%.2f,%.2f,%.2f,%d\n",
-		r.TotalTime.Seconds(),
-		r.TotalRequests,
-		r.RPS,
-		float64(r.MinDuration.Milliseconds()),
-		float64(r.MaxDuration.Milliseconds()),
-		float64(r.AvgDuration.Milliseconds()),
-		r.Errors))
-	// Status Codes
-	sb.WriteString("\nStatus Code Distribution\n")
-	sb.WriteString("Code,Count,Percentage\n")
-	for code, count := range r.StatusCodes {
-		percentage := float64(count) / float64(r.TotalRequests) * 100
-		sb.WriteString(fmt.Sprintf("%d,%d,%.2f\n", code, count, percentage))
-	}
-	return sb.String()
-}
+var errInvalidFlags = errors.New("invalid flags")
 
-type Config struct {
-	URL         string
-	Requests    int
-	Concurrency int
-	Timeout     time.Duration
-	Method      string
-	Headers     map[string]string
-	Body        string
-	Format      string // "plain", "json", "csv"
+// parseConfig interpreta os argumentos de um teste com o pacote flag, como o
+// serve os recebe pela API. Erros de sintaxe das flags são escritos em output.
+// O log do processo não muda, para que um teste submetido não troque o log
+// do servidor.
+func parseConfig(args []string, output io.Writer) (loadtest.Config, cliOptions, error) {
+	fs := flag.NewFlagSet("run", flag.ContinueOnError)
+	fs.SetOutput(output)
+	build := runFlags(fs, false)
+	if err := fs.Parse(args); err != nil {
+		return loadtest.Config{}, cliOptions{}, fmt.Errorf("%w: %w", errInvalidFlags, err)
+	}
+	return build(args, func(name string) bool { return flagSet(fs, name) })
 }
 
-type Report struct {
-	TotalTime     time.Duration
-	TotalRequests int
-	StatusCodes   map[int]int
-	Errors        int
-	Durations     []time.Duration
-	MinDuration   time.Duration
-	MaxDuration   time.Duration
-	AvgDuration   time.Duration
-	RPS           float64
-	StdDeviation  time.Duration
-	ErrorDetails  map[string]ErrorDetail
-}
+// runBuilder monta a configuração do teste depois que as flags registradas
+// por runFlags foram interpretadas. explicit informa quais flags vieram da
+// linha de comando, para que o -config não as sobrescreva.
+type runBuilder func(args []string, explicit func(name string) bool) (loadtest.Config, cliOptions, error)
+
+// runFlags registra as flags do `run` em fs. Com setLogger, -log-level e
+// -log-format passam a valer para todo o processo.
+func runFlags(fs *flag.FlagSet, setLogger bool) runBuilder {
+	buildConfig := loadtest.Flags(fs)
+	buildLogger := logFlags(fs)
+	profileFlag := fs.String("profile", "", profileUsage)
+	configFlag := fs.String("config", "", "JSON file with flag values, as written by 'init'; flags given on the command line take precedence")
+	dryRunFlag := fs.Bool("dry-run", false, "Validate the flags, resolve the target and print the first request without sending load")
+	debugRequestFlag := fs.Bool("debug-request", false, "Send a single request and print the full request, response and timing phases")
+	quietFlag := fs.Bool("quiet", false, "Only write the report in the chosen -format: no progress, no summaries and only warnings and errors in the log")
+	noColorFlag := fs.Bool("no-color", false, "Disable ANSI colors in the text report (also disabled by the NO_COLOR environment variable)")
+	langFlag := fs.String("lang", "en", langUsage)
+	watchFlag := fs.String("watch", "", "JSON file re-read during the test; changes to concurrency, headers and thresholds are applied without restarting")
+	checkpointFlag := fs.String("checkpoint", "", "Save the progress and the partial report to this file periodically, so an interrupted run can be continued with -resume")
+	checkpointIntervalFlag := fs.Duration("checkpoint-interval", 30*time.Second, "How often -checkpoint is written")
+	resumeFlag := fs.String("resume", "", "Continue the run saved in this -checkpoint file: only the remaining requests are sent and the report covers the whole run")
+	noTUIFlag := fs.Bool("no-tui", false, "Keep the single progress line even when stdout is a terminal")
+	workersFlag := fs.String("workers", "", "Comma-separated base URLs of 'serve' instances that split the test between them")
+	webhookFlag := fs.String("webhook", "", "POST the final report and the threshold verdict as JSON to this URL when the test ends")
+	historyFlag := fs.String("history", "", "Record the run summary in this SQLite database (e.g. "+defaultHistoryPath+"), listed by the 'history' subcommand")
+	nameFlag := fs.String("name", "", "Name of the test, used in notifications and uploaded report keys (default: the URL host)")
+	uploadFlag := fs.String("upload", "", "Upload the JSON and CSV reports to s3://bucket/key or gs://bucket/key; the key accepts {{date}}, {{time}}, {{name}} and {{sha}}")
+	slackWebhookFlag := fs.String("slack-webhook", "", "Slack incoming webhook URL that receives a short summary of the run")
+	teamsWebhookFlag := fs.String("teams-webhook", "", "Microsoft Teams incoming webhook URL that receives a short summary of the run")
+	baselineFlag := fs.String("baseline", "", "JSON report of a previous run; fail when p95, RPS or error rate regress beyond the tolerances")
+	p95ToleranceFlag := fs.Float64("baseline-p95-tolerance", 10, "Accepted p95 increase over -baseline, in percent")
+	rpsToleranceFlag := fs.Float64("baseline-rps-tolerance", 10, "Accepted RPS drop from -baseline, in percent")
+	errorRateToleranceFlag := fs.Float64("baseline-error-tolerance", 1, "Accepted error rate increase over -baseline, in percentage points")
+	maxP95Flag := fs.Duration("max-p95", 0, "Fail the test when the p95 latency is above this value (e.g. 500ms)")
+	maxErrorRateFlag := fs.Float64("max-error-rate", 0, "Fail the test when the error rate is above this percentage")
+	minRPSFlag := fs.Float64("min-rps", 0, "Fail the test when the throughput is below this many requests per second")
+	uiFlag := fs.String("ui", "", "Serve a live web dashboard on this address during the test (e.g. :8080)")
+	requestLogFlag := fs.String("request-log", "", "Write every HTTP request (method, URL, headers, body, status, timing) to an NDJSON file")
+	return func(args []string, explicit func(string) bool) (loadtest.Config, cliOptions, error) {
+		if *configFlag != "" {
+			fromConfig, err := applyConfigFile(fs, *configFlag, explicit)
+			if err != nil {
+				return loadtest.Config{}, cliOptions{}, err
+			}
+			// O -profile não sobrescreve o que veio do arquivo
+			fromFlags := explicit
+			explicit = func(name string) bool { return fromConfig[name] || fromFlags(name) }
+		}
+		if *profileFlag != "" {
+			if err := applyProfile(fs, *profileFlag, explicit); err != nil {
+				return loadtest.Config{}, cliOptions{}, err
+			}
+		}
+		if *workersFlag != "" {
+			if name := localFileFlag(fs); name != "" {
+				return loadtest.Config{}, cliOptions{}, fmt.Errorf("-%s is not supported with -workers: the workers would look for the file on their own disk", name)
+			}
+		}
+		logger, err := buildLogger(*quietFlag, explicit)
+		if err != nil {
+			return loadtest.Config{}, cliOptions{}, err
+		}
+		if setLogger {
+			slog.SetDefault(logger)
+		}
+		config, err := buildConfig()
+		if err != nil {
+			return loadtest.Config{}, cliOptions{}, err
+		}
+		config.Quiet = *quietFlag
+		if err := report.CheckLang(*langFlag); err != nil {
+			return loadtest.Config{}, cliOptions{}, err
+		}
+		if *watchFlag != "" && (*workersFlag != "" || len(config.BodySizes) > 0) {
+			return loadtest.Config{}, cliOptions{}, errors.New("-watch is not supported with -workers or -body-sizes")
+		}
+		// O teste retomado continua gravando no mesmo arquivo
+		if *checkpointFlag == "" {
+			*checkpointFlag = *resumeFlag
+		}
+		if *checkpointFlag != "" && (*workersFlag != "" || len(config.BodySizes) > 0) {
+			return loadtest.Config{}, cliOptions{}, errors.New("-checkpoint and -resume are not supported with -workers or -body-sizes")
+		}
+		if *checkpointIntervalFlag <= 0 {
+			return loadtest.Config{}, cliOptions{}, fmt.Errorf("invalid -checkpoint-interval %v", *checkpointIntervalFlag)
+		}
 
-type ErrorDetail struct {
-	Count   int
-	Message string
-	Code    int // Código HTTP associado ao erro, se aplicável
-}
+		var baseline *loadtest.Report
+		if *baselineFlag != "" {
+			base, err := report.Load(*baselineFlag)
+			if err != nil {
+				return loadtest.Config{}, cliOptions{}, err
+			}
+			baseline = &base
+		}
 
-func main() {
-	urlFlag := flag.String("url", "", "URL to test")
-	requestsFlag := flag.Int("requests", 0, "Number of requests to make")
-	concurrencyFlag := flag.Int("concurrency", 1, "Number of concurrent requests")
-	timeoutFlag := flag.Duration("timeout", 10*time.Second, "Timeout for each request")
-	methodFlag := flag.String("method", "GET", "HTTP method to use")
-	formatFlag := flag.String("format", "plain", "Output format (plain, json, csv)")
-	headersFlag := flag.String("headers", "", "Headers in format 'key1:value1,key2:value2'")
-	bodyFlag := flag.String("body", "", "Request body")
-	flag.Parse()
-
-	// Processar headers
-	headersMap := make(map[string]string)
-	if *headersFlag != "" {
-		pairs := strings.Split(*headersFlag, ",")
-		for _, pair := range pairs {
-			kv := strings.SplitN(pair, ":", 2)
-			if len(kv) == 2 {
-				headersMap[strings.TrimSpace(kv[0])] = strings.TrimSpace(kv[1])
+		var upload *reportUpload
+		if *uploadFlag != "" {
+			if upload, err = newReportUpload(*uploadFlag); err != nil {
+				return loadtest.Config{}, cliOptions{}, err
+			}
+		}
+		name := *nameFlag
+		if name == "" {
+			if u, err := url.Parse(config.URL); err == nil && u.Host != "" {
+				name = u.Host
+			} else {
+				name = config.URL
 			}
 		}
-	}
 
-	config := Config{
-		URL:         *urlFlag,
-		Requests:    *requestsFlag,
-		Concurrency: *concurrencyFlag,
-		Timeout:     *timeoutFlag,
-		Method:      *methodFlag,
-		Format:      *formatFlag,
-		Headers:     headersMap,
-		Body:        *bodyFlag,
+		return config, cliOptions{
+			Args:     args,
+			Baseline: baseline,
+			Tolerances: report.Tolerances{
+				P95:       *p95ToleranceFlag,
+				RPS:       *rpsToleranceFlag,
+				ErrorRate: *errorRateToleranceFlag,
+			},
+			Name:               name,
+			DryRun:             *dryRunFlag,
+			Quiet:              *quietFlag,
+			NoColor:            *noColorFlag,
+			Lang:               *langFlag,
+			DebugRequest:       *debugRequestFlag,
+			History:            *historyFlag,
+			Upload:             upload,
+			RequestLog:         *requestLogFlag,
+			UI:                 *uiFlag,
+			NoTUI:              *noTUIFlag,
+			Watch:              *watchFlag,
+			Checkpoint:         *checkpointFlag,
+			CheckpointInterval: *checkpointIntervalFlag,
+			Resume:             *resumeFlag,
+			Workers:            splitList(*workersFlag),
+			Webhook:            *webhookFlag,
+			SlackWebhook:       *slackWebhookFlag,
+			TeamsWebhook:       *teamsWebhookFlag,
+			Thresholds: report.Thresholds{
+				MaxP95:          *maxP95Flag,
+				MaxErrorRate:    *maxErrorRateFlag,
+				MinRPS:          *minRPSFlag,
+				MaxCategoryRate: categoryRates(config.ErrorRules),
+			},
+		}, nil
 	}
+}
 
-	if config.URL == "" || config.Requests == 0 {
-		fmt.Println("URL and number of requests are required")
-		return
+// categoryRates são os limites por categoria definidos no -error-rules.
+func categoryRates(rules *loadtest.ErrorRules) map[string]float64 {
+	if rules == nil {
+		return nil
 	}
-
-	report := executeLoadTest(config)
-	printReport(report)
-	printErrorDetails(report)
+	rates := make(map[string]float64)
+	for name, category := range rules.Categories {
+		if category.MaxRate > 0 {
+			rates[name] = category.MaxRate
+		}
+	}
+	return rates
 }
 
-func executeLoadTest(config Config) Report {
-	results := make(chan Result, config.Requests)
-	start := time.Now()
-	var wg sync.WaitGroup
-	semaphore := make(chan struct{}, config.Concurrency)
-
-	// Mostrar progresso
-	progress := make(chan int, config.Requests)
-	go showProgress(config.Requests, progress)
-
-	for i := 0; i < config.Requests; i++ {
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			semaphore <- struct{}{}
-			makeRequest(config, results)
-			progress <- 1
-			<-semaphore
-		}()
+// splitList separa valores por vírgula, ignorando espaços e itens vazios.
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
 	}
-
-	go func() {
-		wg.Wait()
-		close(results)
-		close(progress)
-	}()
-
-	return collectResults(results, start)
+	return items
 }
 
-func showProgress(total int, progress chan int) {
-	current := 0
-	start := time.Now()
-	for range progress {
-		current++
-		percent := float64(current) / float64(total) * 100
-		elapsed := time.Since(start)
-		rate := float64(current) / elapsed.Seconds()
-		fmt.Printf("\rProgress: %.1f%% (%d/%d) | Rate: %.2f req/s", percent, current, total, rate)
-	}
-	fmt.Println()
+// colorEnabled decide se o relatório em texto usa cores: só em terminal e
+// sem -no-color ou a variável NO_COLOR (https://no-color.org).
+func colorEnabled(noColor bool) bool {
+	return !noColor && os.Getenv("NO_COLOR") == "" && loadtest.StdoutIsTerminal()
 }
 
-func classifyErrorToHTTPStatus(err error) int {
-	if err == nil {
-		return 200 // OK (não deveria acontecer)
-	}
+// langUsage é a ajuda do -lang, igual em todos os subcomandos.
+var langUsage = "Language of the text output (" + strings.Join(report.Langs, ", ") + "); JSON, CSV and logs are always in English"
 
-	// Verifica se o erro é um erro de timeout
-	if netErr, ok := err.(net.Error); ok {
-		if netErr.Timeout() {
-			return 408 // Request Timeout
-		} else if netErr.Temporary() {
-			return 503 // Service Unavailable (temporário)
-		}
+// setLang aplica o -lang de um subcomando.
+func setLang(lang string) error {
+	if err := report.CheckLang(lang); err != nil {
+		return err
 	}
+	report.Lang = lang
+	return nil
+}
 
-	// Análise baseada no texto da mensagem de erro
-	errMsg := err.Error()
+// Códigos de saída do `run`, listados na ajuda do comando (runLong).
+const (
+	exitOK         = 0
+	exitThresholds = 1
+	exitConfig     = 2
+	exitAborted    = 3
+	exitAllFailed  = 4
+)
 
-	// Connection refused
-	if strings.Contains(errMsg, "connection refused") {
-		return 503 // Service Unavailable
-	}
+func main() {
+	logger, _ := newLogger("info", "text")
+	slog.SetDefault(logger)
+	os.Exit(execute(os.Args[1:]))
+}
 
-	// DNS/Host não encontrado
-	if strings.Contains(errMsg, "no such host") ||
-		strings.Contains(errMsg, "lookup") && strings.Contains(errMsg, "no such host") {
-		return 404 // Not Found
+// runTest implementa o subcomando `run`, com as flags já interpretadas, e
+// devolve o código de saída.
+func runTest(build runBuilder, args []string, explicit func(string) bool) int {
+	// Em json e csv o stdout recebe só o relatório; avisos e progresso vão
+	// para o stderr, permitindo redirecionar a saída para um arquivo
+	reportOut := os.Stdout
+	os.Stdout = os.Stderr
+	config, opts, err := build(args, explicit)
+	if err == nil && config.Format == "plain" {
+		os.Stdout = reportOut
 	}
-
-	// Erros de certificado SSL
-	if strings.Contains(errMsg, "certificate") ||
-		strings.Contains(errMsg, "x509") {
-		return 495 // SSL Certificate Error (não padrão)
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		return exitConfig
 	}
+	report.Color = colorEnabled(opts.NoColor)
+	report.Lang = opts.Lang
 
-	// Connection reset
-	if strings.Contains(errMsg, "connection reset") {
-		return 500 // Internal Server Error
+	if opts.DryRun {
+		if !runDryRun(config) {
+			return exitConfig
+		}
+		return exitOK
 	}
-
-	// TLS handshake timeout
-	if strings.Contains(errMsg, "TLS handshake timeout") {
-		return 408 // Request Timeout
+	if opts.DebugRequest {
+		if !runDebugRequest(config) {
+			return exitAllFailed
+		}
+		return exitOK
 	}
 
-	// Too many redirects
-	if strings.Contains(errMsg, "stopped after") && strings.Contains(errMsg, "redirects") {
-		return 310 // Too many redirects
+	// Ctrl+C ou SIGTERM abortam o teste e o relatório parcial segue o
+	// fluxo normal; um segundo sinal encerra o processo na hora
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+
+	if len(opts.Workers) > 0 {
+		result, err := runDistributed(ctx, args, config, opts.Workers)
+		stop()
+		if err == nil && result.Aborted {
+			slog.Warn("test aborted, reporting the partial results", "requests", result.TotalRequests)
+		}
+		return finishRun(reportOut, config, opts, result, err)
 	}
 
-	// EOF
-	if strings.Contains(errMsg, "EOF") {
-		return 500 // Internal Server Error
+	if opts.RequestLog != "" {
+		logger, err := loadtest.NewRequestLogger(opts.RequestLog)
+		if err != nil {
+			slog.Error("opening the request log", "error", err)
+			return exitConfig
+		}
+		defer func() {
+			if err := logger.Close(); err != nil {
+				slog.Error("closing the request log", "error", err)
+			}
+		}()
+		config.RequestLog = logger
 	}
 
-	// Connection closed
-	if strings.Contains(errMsg, "connection closed") {
-		return 500 // Internal Server Error
+	var resumed *loadtest.Checkpoint
+	if opts.Resume != "" {
+		if resumed, err = resumeCheckpoint(opts.Resume, &config); err != nil {
+			slog.Error("resuming the test", "error", err)
+			return exitConfig
+		}
+	}
+	if opts.Checkpoint != "" {
+		// A semente sorteada antes do Run vai para o checkpoint, para o
+		// -resume repetir os mesmos valores
+		for config.Seed == 0 {
+			config.Seed = rand.Uint64()
+		}
+		config.Checkpoint = newCheckpointer(opts.Checkpoint, opts.CheckpointInterval, config, resumed)
 	}
 
-	// Request canceled (context deadline exceeded)
-	if strings.Contains(errMsg, "context deadline exceeded") {
-		return 408 // Request Timeout
+	var watcher *configWatcher
+	if opts.Watch != "" {
+		if watcher, err = newConfigWatcher(opts.Watch, &config, opts.Thresholds); err != nil {
+			slog.Error("invalid configuration", "error", err)
+			return exitConfig
+		}
 	}
 
-	// Dial TCP especificamente
-	if strings.Contains(errMsg, "dial tcp") {
-		// Se contiver "connection refused"
-		if strings.Contains(errMsg, "connection refused") {
-			return 503 // Service Unavailable
+	if opts.UI != "" {
+		config.Live = loadtest.NewLiveStats(config.Requests)
+		config.Stop = loadtest.NewStopSignal()
+		if config.Control == nil {
+			config.Control = loadtest.NewConcurrencyControl()
 		}
-		// Se contiver "i/o timeout"
-		if strings.Contains(errMsg, "i/o timeout") {
-			return 408 // Request Timeout
+		server, err := loadtest.StartUI(opts.UI, config.Live, config.Stop, config.Control)
+		if err != nil {
+			slog.Error("starting the dashboard", "error", err)
+			return exitConfig
 		}
+		defer server.Close()
 	}
 
-	// Erro genérico de rede
-	if strings.Contains(errMsg, "net/http") {
-		return 500 // Internal Server Error
+	if !opts.NoTUI && !opts.Quiet && config.Progress != "json" && loadtest.StdoutIsTerminal() {
+		config.TUI = true
+		if config.Live == nil {
+			config.Live = loadtest.NewLiveStats(config.Requests)
+		}
+		if config.Control == nil {
+			config.Control = loadtest.NewConcurrencyControl()
+		}
 	}
 
-	// Fallback para qualquer outro erro
-	return 500 // Internal Server Error genérico
-}
-
-func makeRequest(config Config, results chan<- Result) {
-	client := &http.Client{
-		Timeout: config.Timeout,
-		Transport: &http.Transport{
-			MaxIdleConns:        100,
-			MaxIdleConnsPerHost: 100,
-			IdleConnTimeout:     90 * time.Second,
-			DisableCompression:  true,
-		},
+	if len(config.BodySizes) > 0 {
+		code := runSizeSweep(ctx, reportOut, config, opts)
+		stop()
+		return code
 	}
 
-	req, err := http.NewRequest(config.Method, config.URL, strings.NewReader(config.Body))
-	if err != nil {
-		statusCode := classifyErrorToHTTPStatus(err)
-		results <- Result{
-			StatusCode: statusCode,
-			Error:      err,
-			Duration:   0,
+	if watcher != nil {
+		watcher.watch()
+	}
+	result, err := loadtest.Run(ctx, config)
+	stop()
+	if watcher != nil {
+		var timeline []loadtest.TimelineEvent
+		opts.Thresholds, timeline = watcher.finish()
+		if result != nil {
+			result.Timeline = timeline
 		}
-		return
 	}
-
-	// Adicionar headers
-	for k, v := range config.Headers {
-		req.Header.Add(k, v)
+	if err == nil && resumed != nil {
+		merged := report.MergeSegments(append(resumed.Segments, *result))
+		result = &merged
 	}
+	if err == nil && result.Aborted {
+		slog.Warn("test aborted, reporting the partial results", "requests", result.TotalRequests)
+	}
+	return finishRun(reportOut, config, opts, result, err)
+}
 
-	start := time.Now()
-	resp, err := client.Do(req)
-	duration := time.Since(start)
-
-	if err != nil {
-		statusCode := classifyErrorToHTTPStatus(err)
-		results <- Result{
-			StatusCode: statusCode,
-			Error:      err,
-			Duration:   duration,
+// runSizeSweep repete o teste para cada tamanho do -body-sizes. Cada rodada
+// segue o caminho de um teste comum (limites, histórico e webhooks); em
+// plain cada relatório é impresso ao fim da rodada e a tabela compara as
+// rodadas no fim, e em json e csv sai um documento só, com a comparação e
+// os relatórios. O código de saída é o pior entre as rodadas; Ctrl+C aborta
+// a rodada em andamento e as seguintes não são feitas.
+func runSizeSweep(ctx context.Context, reportOut io.Writer, config loadtest.Config, opts cliOptions) int {
+	// A mesma semente em todas as rodadas, para repetir a varredura com -seed
+	for config.Seed == 0 {
+		config.Seed = rand.Uint64()
+	}
+	name := opts.Name
+	stepOut := reportOut
+	if config.Format != "plain" {
+		stepOut = io.Discard
+	}
+	steps := make([]loadtest.SweepStep, 0, len(config.BodySizes))
+	codes := make([]int, 0, len(config.BodySizes))
+	for _, size := range config.BodySizes {
+		if !opts.Quiet {
+			fmt.Printf(report.Msg("\n📦 Body size %s\n"), loadtest.FormatByteSize(size))
+		}
+		config.BodySize = size
+		opts.Name = fmt.Sprintf("%s (%s)", name, loadtest.FormatByteSize(size))
+		result, err := loadtest.Run(ctx, config)
+		if err == nil && result.Aborted {
+			slog.Warn("test aborted, reporting the partial results", "requests", result.TotalRequests)
+		}
+		codes = append(codes, finishRun(stepOut, config, opts, result, err))
+		if err != nil {
+			break
+		}
+		steps = append(steps, loadtest.SweepStep{Size: size, Report: *result})
+		if result.Aborted {
+			break
 		}
-		return
 	}
-
-	defer resp.Body.Close()
-	results <- Result{
-		StatusCode: resp.StatusCode,
-		Duration:   duration,
+	switch {
+	case len(steps) == 0:
+	case config.Format == "json":
+		fmt.Fprintln(reportOut, export.JSONExporter{}.ExportSizeSweep(steps))
+	case config.Format == "csv":
+		fmt.Fprint(reportOut, export.CSVExporter{}.ExportSizeSweep(steps))
+	case !opts.Quiet:
+		report.PrintSizeSweep(steps)
 	}
+	return worstExit(codes)
 }
 
-func collectResults(results chan Result, startTime time.Time) Report {
-	report := Report{
-		StatusCodes:  make(map[int]int),
-		Durations:    make([]time.Duration, 0),
-		MinDuration:  time.Hour,
-		ErrorDetails: make(map[string]ErrorDetail),
-	}
-
-	for result := range results {
-		report.TotalRequests++
-
-		// Incrementar contagem do código de status
-		report.StatusCodes[result.StatusCode]++
-
-		// Registrar erro se existir
-		if result.Error != nil {
-			report.Errors++
-			errMsg := result.Error.Error()
-			detail, exists := report.ErrorDetails[errMsg]
-			if !exists {
-				detail = ErrorDetail{
-					Message: errMsg,
-					Code:    result.StatusCode,
+// finishRun imprime o relatório, aplica os limites e avisa os webhooks.
+// Devolve o código de saída do teste.
+func finishRun(reportOut io.Writer, config loadtest.Config, opts cliOptions, result *loadtest.Report, runErr error) int {
+	var verdict *report.Verdict
+	if runErr != nil {
+		slog.Error("test failed", "error", runErr)
+	} else {
+		writeReport(reportOut, config.Format, *result)
+		if opts.Thresholds.Enabled() || opts.Baseline != nil {
+			v, deltas := judge(opts, *result)
+			if deltas != nil && !opts.Quiet {
+				report.PrintComparison(deltas)
+			}
+			verdict = &v
+			if !opts.Quiet {
+				report.PrintVerdict(v)
+			} else {
+				// Sem o resumo, as violações ainda aparecem no log
+				for _, failure := range v.Failures {
+					slog.Warn("threshold failed", "reason", failure)
 				}
 			}
-			detail.Count++
-			report.ErrorDetails[errMsg] = detail
 		}
-
-		// Processar duração
-		if result.Duration > 0 {
-			report.Durations = append(report.Durations, result.Duration)
-			if result.Duration < report.MinDuration {
-				report.MinDuration = result.Duration
+		if opts.History != "" {
+			run := newHistoryRun(opts.Name, config.URL, opts.Args, *result, verdict)
+			if id, err := saveHistoryRun(opts.History, run); err != nil {
+				slog.Error("saving the run history", "error", err)
+			} else {
+				slog.Info("run saved", "id", id, "db", opts.History)
 			}
-			if result.Duration > report.MaxDuration {
-				report.MaxDuration = result.Duration
+		}
+		if opts.Upload != nil {
+			uploaded, err := opts.Upload.upload(opts.Name, *result)
+			for _, dest := range uploaded {
+				slog.Info("report uploaded", "dest", dest)
+			}
+			if err != nil {
+				slog.Error("uploading the report", "error", err)
 			}
 		}
 	}
 
-	report.TotalTime = time.Since(startTime)
-
-	// Calcular média
-	var total time.Duration
-	for _, d := range report.Durations {
-		total += d
+	if opts.Webhook != "" {
+		if err := postJSON(opts.Webhook, newWebhookPayload(result, verdict, runErr)); err != nil {
+			slog.Error("sending the webhook", "error", err)
+		}
 	}
-	if len(report.Durations) > 0 {
-		report.AvgDuration = total / time.Duration(len(report.Durations))
+	if opts.SlackWebhook != "" || opts.TeamsWebhook != "" {
+		summary := newRunSummary(opts.Name, result, verdict, runErr)
+		if opts.SlackWebhook != "" {
+			if err := postJSON(opts.SlackWebhook, summary.slackMessage()); err != nil {
+				slog.Error("sending the Slack notification", "error", err)
+			}
+		}
+		if opts.TeamsWebhook != "" {
+			if err := postJSON(opts.TeamsWebhook, summary.teamsMessage()); err != nil {
+				slog.Error("sending the Teams notification", "error", err)
+			}
+		}
 	}
 
-	// Calcular RPS
-	report.RPS = float64(report.TotalRequests) / report.TotalTime.Seconds()
-
-	// Calcular desvio padrão
-	report.StdDeviation = calculateStdDeviation(report.Durations, report.AvgDuration)
-
-	return report
+	return exitCode(result, verdict, runErr)
 }
 
-func calculatePercentile(durations []time.Duration, percentile float64) time.Duration {
-	if len(durations) == 0 {
-		return 0
-	}
-
-	sort.Slice(durations, func(i, j int) bool {
-		return durations[i] < durations[j]
-	})
-	index := int(float64(len(durations)) * percentile / 100)
-	if index >= len(durations) {
-		index = len(durations) - 1
+// judge aplica os limites e as tolerâncias do -baseline ao relatório. As
+// variações só são calculadas com -baseline.
+func judge(opts cliOptions, result loadtest.Report) (report.Verdict, []report.Delta) {
+	verdict := opts.Thresholds.Evaluate(result)
+	if opts.Baseline == nil {
+		return verdict, nil
 	}
-	return durations[index]
+	deltas := report.Compare(*opts.Baseline, result)
+	verdict.Include(opts.Tolerances.Regressions(deltas))
+	return verdict, deltas
 }
 
-func calculateStdDeviation(durations []time.Duration, avg time.Duration) time.Duration {
-	if len(durations) == 0 {
-		return 0
-	}
-
-	var sumSquares float64
-	for _, d := range durations {
-		diff := d.Seconds() - avg.Seconds()
-		sumSquares += diff * diff
+// worstExit escolhe, entre os códigos de saída de várias execuções, o mais
+// grave pela política de exitCode.
+func worstExit(codes []int) int {
+	for _, code := range []int{exitConfig, exitAborted, exitAllFailed, exitThresholds} {
+		if slices.Contains(codes, code) {
+			return code
+		}
 	}
-	variance := sumSquares / float64(len(durations))
-	return time.Duration(math.Sqrt(variance) * float64(time.Second))
+	return exitOK
 }
 
-func getStatusCodeDescription(code int) string {
-	switch code {
-	case 0:
-		return "Erro não identificado"
-	case 200:
-		return "OK"
-	case 201:
-		return "Created"
-	case 204:
-		return "No Content"
-	case 310:
-		return "Too Many Redirects"
-	case 400:
-		return "Bad Request"
-	case 401:
-		return "Unauthorized"
-	case 403:
-		return "Forbidden"
-	case 404:
-		return "Not Found"
-	case 408:
-		return "Request Timeout"
-	case 429:
-		return "Too Many Requests"
-	case 495:
-		return "SSL Certificate Error"
-	case 500:
-		return "Internal Server Error"
-	case 502:
-		return "Bad Gateway"
-	case 503:
-		return "Service Unavailable"
-	case 504:
-		return "Gateway Timeout"
-	default:
-		return "Status Code " + fmt.Sprintf("%d", code)
-	}
+// exitCode aplica a política de códigos de saída descrita em runLong (cli.go).
+// Um teste abortado ou sem nenhuma resposta do servidor não chega a ser
+// julgado pelos limites; se houve respostas, mas todas as requisições
+// falharam (por exemplo, todas fora do -expect-status), o teste falha como
+// um limite violado.
+func exitCode(result *loadtest.Report, verdict *report.Verdict, runErr error) int {
+	switch {
+	case runErr != nil:
+		return exitConfig
+	case result.Aborted:
+		return exitAborted
+	case result.TotalRequests > 0 && !result.Responded():
+		return exitAllFailed
+	case result.TotalRequests > 0 && result.Errors == result.TotalRequests:
+		return exitThresholds
+	case verdict != nil && !verdict.Passed:
+		return exitThresholds
+	}
+	return exitOK
 }
 
-func printReport(report Report) {
-	fmt.Printf("\n📊 Test Results Summary\n")
-	fmt.Printf("----------------------------------------\n")
-	fmt.Printf("Total Time: %.2f seconds\n", report.TotalTime.Seconds())
-	fmt.Printf("Total Requests: %d\n", report.TotalRequests)
-	fmt.Printf("Requests per Second: %.2f\n", report.RPS)
-	fmt.Printf("----------------------------------------\n\n")
-
-	fmt.Printf("⚡ Response Time Stats\n")
-	fmt.Printf("----------------------------------------\n")
-	if len(report.Durations) > 0 {
-		fmt.Printf("Minimum: %v\n", report.MinDuration)
-		fmt.Printf("Maximum: %v\n", report.MaxDuration)
-		fmt.Printf("Average: %v\n", report.AvgDuration)
-		fmt.Printf("P50: %v\n", calculatePercentile(report.Durations, 50))
-		fmt.Printf("P90: %v\n", calculatePercentile(report.Durations, 90))
-		fmt.Printf("P95: %v\n", calculatePercentile(report.Durations, 95))
-		fmt.Printf("P99: %v\n", calculatePercentile(report.Durations, 99))
-	} else {
-		fmt.Printf("No successful requests to measure response time\n")
-	}
-	fmt.Printf("----------------------------------------\n\n")
-
-	fmt.Printf("📈 Status Code Distribution\n")
-	fmt.Printf("----------------------------------------\n")
-
-	// Ordenar códigos para exibição
-	var codes []int
-	for code := range report.StatusCodes {
-		codes = append(codes, code)
-	}
-	sort.Ints(codes)
-
-	// Destacar sucesso e falhas
-	successCount := report.StatusCodes[200]
-	successRate := float64(successCount) / float64(report.TotalRequests) * 100
-
-	fmt.Printf("✅ Status 200 (Success): %d requests (%.1f%%)\n", successCount, successRate)
-
-	for _, code := range codes {
-		if code == 200 {
-			continue // Já exibimos o 200 acima
-		}
-
-		count := report.StatusCodes[code]
-		percentage := float64(count) / float64(report.TotalRequests) * 100
-
-		if code >= 400 || code == 0 {
-			// Erro
-			fmt.Printf("❌ Status %d (%s): %d requests (%.1f%%)\n",
-				code, getStatusCodeDescription(code), count, percentage)
-		} else if code >= 300 {
-			// Redirecionamento
-			fmt.Printf("↪️ Status %d (%s): %d requests (%.1f%%)\n",
-				code, getStatusCodeDescription(code), count, percentage)
-		} else {
-			// Outros códigos de sucesso
-			fmt.Printf("✅ Status %d (%s): %d requests (%.1f%%)\n",
-				code, getStatusCodeDescription(code), count, percentage)
-		}
-	}
-	fmt.Printf("----------------------------------------\n")
-
-	if report.Errors > 0 {
-		errorRate := float64(report.Errors) / float64(report.TotalRequests) * 100
-		fmt.Printf("\n❌ Total Errors: %d (%.1f%%)\n", report.Errors, errorRate)
+// checkReportFormat confere o --format dos subcomandos que reimprimem
+// relatórios salvos.
+func checkReportFormat(format string) error {
+	if format != "plain" && format != "json" && format != "csv" {
+		return fmt.Errorf("invalid --format %q (use plain, json or csv)", format)
 	}
+	return nil
 }
 
-func printErrorDetails(report Report) {
-	if report.Errors > 0 {
-		fmt.Printf("\n❌ Detalhes dos Erros:\n")
-		fmt.Printf("----------------------------------------\n")
-		fmt.Printf("| %-8s | %-50s | %-8s | %-10s |\n",
-			"Status", "Mensagem de Erro", "Count", "Percentual")
-		fmt.Printf("----------------------------------------\n")
-
-		// Ordenar erros por contagem
-		type ErrEntry struct {
-			Type   string
-			Detail ErrorDetail
-		}
-
-		entries := make([]ErrEntry, 0, len(report.ErrorDetails))
-		for errType, detail := range report.ErrorDetails {
-			entries = append(entries, ErrEntry{errType, detail})
-		}
-
-		sort.Slice(entries, func(i, j int) bool {
-			return entries[i].Detail.Count > entries[j].Detail.Count
-		})
-
-		for _, entry := range entries {
-			percent := float64(entry.Detail.Count) / float64(report.TotalRequests) * 100
-
-			shortErrType := entry.Type
-			if len(shortErrType) > 50 {
-				shortErrType = shortErrType[:47] + "..."
-			}
-
-			fmt.Printf("| %-8d | %-50s | %-8d | %-9.1f%% |\n",
-				entry.Detail.Code,
-				shortErrType,
-				entry.Detail.Count,
-				percent)
-		}
-		fmt.Printf("----------------------------------------\n")
+// writeReport imprime o relatório no formato escolhido com -format.
+func writeReport(w io.Writer, format string, result loadtest.Report) {
+	switch format {
+	case "json":
+		fmt.Fprintln(w, export.JSONExporter{}.Export(result))
+	case "csv":
+		fmt.Fprint(w, export.CSVExporter{}.Export(result))
+	default:
+		report.Print(result)
+		report.PrintErrorDetails(result)
 	}
 }
@@ -0,0 +1,42 @@
+package main
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// mergeCommand implementa o subcomando `merge`: combina relatórios JSON
+// (-format json) de execuções repetidas ou de workers em um só.
+func mergeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "merge [flags] report.json...",
+		Short:   "Combine several JSON reports into one",
+		GroupID: "reports",
+		Args:    cobra.MinimumNArgs(1),
+	}
+	formatFlag := cmd.Flags().String("format", "plain", "Output format of the merged report (plain, json, csv)")
+	addLangFlag(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkReportFormat(*formatFlag); err != nil {
+			return err
+		}
+		reports := make([]loadtest.Report, 0, len(args))
+		for _, path := range args {
+			result, err := report.Load(path)
+			if err != nil {
+				return fmt.Errorf("loading report: %w", err)
+			}
+			reports = append(reports, result)
+		}
+		slog.Info("reports merged", "count", len(reports))
+		writeReport(os.Stdout, *formatFlag, report.Merge(reports))
+		return nil
+	}
+	return cmd
+}
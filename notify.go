@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// runSummary é o resumo curto enviado aos canais do Slack e do Teams.
+type runSummary struct {
+	Target    string
+	Status    string // PASSED, FAILED, COMPLETED, STOPPED, ABORTED ou ERROR
+	Requests  int
+	RPS       float64
+	P95       string
+	ErrorRate float64
+	Details   []string
+}
+
+func newRunSummary(target string, result *loadtest.Report, verdict *report.Verdict, runErr error) runSummary {
+	summary := runSummary{Target: target}
+	if runErr != nil {
+		summary.Status = "ERROR"
+		summary.Details = []string{runErr.Error()}
+		return summary
+	}
+
+	summary.Requests = result.TotalRequests
+	summary.RPS = result.RPS
+	summary.P95 = report.Latency(result.Percentile(95), result.HasLatency())
+	summary.ErrorRate = report.ErrorRate(*result)
+	switch {
+	case verdict != nil && !verdict.Passed:
+		summary.Status = "FAILED"
+		summary.Details = verdict.Failures
+	case verdict != nil:
+		summary.Status = "PASSED"
+	case result.Aborted:
+		summary.Status = "ABORTED"
+	case result.Stopped:
+		summary.Status = "STOPPED"
+	default:
+		summary.Status = "COMPLETED"
+	}
+	return summary
+}
+
+func (s runSummary) icon() string {
+	switch s.Status {
+	case "PASSED", "COMPLETED":
+		return "✅"
+	case "STOPPED":
+		return "⏹️"
+	case "ABORTED":
+		return "⛔"
+	default:
+		return "❌"
+	}
+}
+
+func (s runSummary) title() string {
+	return fmt.Sprintf("%s Load test %s: %s", s.icon(), s.Status, s.Target)
+}
+
+func (s runSummary) facts() [][2]string {
+	if s.Status == "ERROR" {
+		return nil
+	}
+	return [][2]string{
+		{"Requests", fmt.Sprintf("%d", s.Requests)},
+		{"RPS", fmt.Sprintf("%.2f", s.RPS)},
+		{"P95", s.P95},
+		{"Error rate", fmt.Sprintf("%.2f%%", s.ErrorRate)},
+	}
+}
+
+// slackMessage usa o formato dos incoming webhooks do Slack.
+func (s runSummary) slackMessage() map[string]any {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "*%s*\n", s.title())
+	for _, fact := range s.facts() {
+		fmt.Fprintf(&sb, "• %s: `%s`\n", fact[0], fact[1])
+	}
+	for _, detail := range s.Details {
+		fmt.Fprintf(&sb, "> %s\n", detail)
+	}
+	return map[string]any{"text": sb.String()}
+}
+
+// teamsMessage usa o MessageCard aceito pelos incoming webhooks do Teams.
+func (s runSummary) teamsMessage() map[string]any {
+	color := "2EB886"
+	if s.icon() == "❌" {
+		color = "D7263D"
+	}
+	facts := []map[string]string{}
+	for _, fact := range s.facts() {
+		facts = append(facts, map[string]string{"name": fact[0], "value": fact[1]})
+	}
+	return map[string]any{
+		"@type":      "MessageCard",
+		"@context":   "https://schema.org/extensions",
+		"themeColor": color,
+		"summary":    s.title(),
+		"title":      s.title(),
+		"sections": []map[string]any{{
+			"facts": facts,
+			"text":  strings.Join(s.Details, "<br>"),
+		}},
+	}
+}
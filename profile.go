@@ -0,0 +1,47 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"strings"
+)
+
+// profileNames são os perfis do -profile, na ordem mostrada na ajuda.
+var profileNames = []string{"smoke", "load", "stress", "spike", "soak"}
+
+// loadProfiles dão valores padrão às flags de carga e limites para os tipos
+// de teste mais comuns. Valem só para as flags que não vieram da linha de
+// comando nem do -config.
+var loadProfiles = map[string]map[string]string{
+	// Verificação rápida de que o alvo responde sem erros (0 desligaria o
+	// limite, então qualquer erro passa de 0.01%)
+	"smoke": {"requests": "10", "concurrency": "1", "max-duration": "1m", "max-p95": "1s", "max-error-rate": "0.01"},
+	// Carga esperada em produção
+	"load": {"requests": "10000", "concurrency": "50", "max-duration": "10m", "max-p95": "500ms", "max-error-rate": "1"},
+	// Acima do esperado, para achar o limite
+	"stress": {"requests": "100000", "concurrency": "500", "max-duration": "15m", "max-p95": "2s", "max-error-rate": "5"},
+	// Rajada curta com muita concorrência
+	"spike": {"requests": "20000", "concurrency": "1000", "max-duration": "2m", "max-error-rate": "10"},
+	// Carga moderada por horas, para vazamentos e degradação
+	"soak": {"requests": "10000000", "concurrency": "50", "max-duration": "2h", "max-p95": "500ms", "max-error-rate": "1"},
+}
+
+var profileUsage = "Preset of requests, concurrency, -max-duration and thresholds (" + strings.Join(profileNames, ", ") + "); flags given on the command line or in -config take precedence"
+
+// applyProfile aplica o perfil às flags de fs que não foram definidas
+// explicitamente.
+func applyProfile(fs *flag.FlagSet, name string, explicit func(string) bool) error {
+	values, ok := loadProfiles[name]
+	if !ok {
+		return fmt.Errorf("invalid -profile %q (use %s)", name, strings.Join(profileNames, ", "))
+	}
+	for flagName, value := range values {
+		if explicit(flagName) {
+			continue
+		}
+		if err := fs.Set(flagName, value); err != nil {
+			return fmt.Errorf("-profile %s: -%s: %w", name, flagName, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,148 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// recorder guarda as requisições que passam pelo proxy no formato do
+// -targets. O arquivo é regravado a cada requisição para que a captura
+// sobreviva a um Ctrl+C.
+type recorder struct {
+	mu       sync.Mutex
+	upstream *url.URL
+	out      string
+	first    time.Time
+	targets  []loadtest.Target
+}
+
+// Headers adicionados pelo proxy ou gerados pelo client no replay.
+var recordSkippedHeaders = map[string]bool{
+	"X-Forwarded-For":   true,
+	"X-Forwarded-Host":  true,
+	"X-Forwarded-Proto": true,
+	"Accept-Encoding":   true,
+}
+
+// recordCommand implementa o subcomando `record`: um proxy reverso que captura
+// o tráfego para ser reproduzido depois com -targets.
+func recordCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "record [flags]",
+		Short:   "Record traffic through a reverse proxy into a -targets file",
+		GroupID: "test",
+		Args:    cobra.NoArgs,
+	}
+	fs := cmd.Flags()
+	listenFlag := fs.String("listen", "127.0.0.1:8081", "Address the recording proxy listens on")
+	upstreamFlag := fs.String("upstream", "", "Base URL requests are forwarded to (required)")
+	outFlag := fs.String("out", "capture.json", "Targets file written with the captured requests")
+	addLangFlag(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		upstream, err := url.Parse(*upstreamFlag)
+		if err != nil || upstream.Host == "" || (upstream.Scheme != "http" && upstream.Scheme != "https") {
+			slog.Error("invalid configuration", "error", errors.New("-upstream must be an http:// or https:// URL"))
+			os.Exit(exitConfig)
+		}
+
+		rec := &recorder{upstream: upstream, out: *outFlag}
+		proxy := &httputil.ReverseProxy{
+			Rewrite: func(r *httputil.ProxyRequest) {
+				r.SetURL(upstream)
+				r.SetXForwarded()
+			},
+		}
+		server := &http.Server{
+			Addr: *listenFlag,
+			Handler: http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, err := io.ReadAll(r.Body)
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusBadRequest)
+					return
+				}
+				r.Body = io.NopCloser(bytes.NewReader(body))
+				if err := rec.add(r, body); err != nil {
+					slog.Warn("recording request", "error", err)
+				}
+				proxy.ServeHTTP(w, r)
+			}),
+		}
+
+		stop := make(chan os.Signal, 1)
+		signal.Notify(stop, os.Interrupt)
+		go func() {
+			<-stop
+			server.Close()
+		}()
+
+		slog.Info("recording, press Ctrl+C to stop", "upstream", upstream, "url", "http://"+*listenFlag)
+		if err := server.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+			slog.Error("serving the proxy", "error", err)
+			return
+		}
+		rec.mu.Lock()
+		fmt.Printf(report.Msg("\n💾 %d requests written to %s\n"), len(rec.targets), rec.out)
+		rec.mu.Unlock()
+		fmt.Printf(report.Msg("Replay with: -targets %s -preserve-timing\n"), rec.out)
+	}
+	return cmd
+}
+
+func (rec *recorder) add(r *http.Request, body []byte) error {
+	target := rec.upstream.JoinPath(r.URL.Path)
+	target.RawQuery = r.URL.RawQuery
+
+	t := loadtest.Target{
+		Name:   r.Method + " " + r.URL.RequestURI(),
+		Method: r.Method,
+		URL:    target.String(),
+	}
+	if len(body) > 0 {
+		if !utf8.Valid(body) {
+			return fmt.Errorf("skipping %s: binary bodies are not supported in targets files", t.Name)
+		}
+		t.Body = string(body)
+	}
+	headers := make(map[string]string)
+	for name, values := range r.Header {
+		if loadtest.HARSkippedHeaders[strings.ToLower(name)] || recordSkippedHeaders[name] {
+			continue
+		}
+		sep := ", "
+		if name == "Cookie" {
+			sep = "; "
+		}
+		headers[name] = strings.Join(values, sep)
+	}
+	if len(headers) > 0 {
+		t.Headers = headers
+	}
+
+	rec.mu.Lock()
+	defer rec.mu.Unlock()
+	now := time.Now()
+	if rec.first.IsZero() {
+		rec.first = now
+	}
+	t.OffsetMS = float64(now.Sub(rec.first)) / float64(time.Millisecond)
+	rec.targets = append(rec.targets, t)
+	fmt.Printf("● %s\n", t.Name)
+	return loadtest.SaveTargets(rec.out, rec.targets)
+}
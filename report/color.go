@@ -0,0 +1,36 @@
+package report
+
+// Color habilita as cores ANSI no relatório em texto. O CLI a liga quando
+// a saída é um terminal e nem -no-color nem a variável NO_COLOR foram usados.
+var Color bool
+
+const (
+	ansiReset  = "\x1b[0m"
+	ansiBold   = "\x1b[1m"
+	ansiRed    = "\x1b[31m"
+	ansiGreen  = "\x1b[32m"
+	ansiYellow = "\x1b[33m"
+	ansiCyan   = "\x1b[36m"
+)
+
+func paint(color, text string) string {
+	if !Color {
+		return text
+	}
+	return color + text + ansiReset
+}
+
+// statusColor segue a classe do código: 2xx verde, 3xx ciano, 4xx amarelo,
+// 5xx e falhas sem resposta (0) vermelho.
+func statusColor(code int) string {
+	switch {
+	case code == 0 || code >= 500:
+		return ansiRed
+	case code >= 400:
+		return ansiYellow
+	case code >= 300:
+		return ansiCyan
+	default:
+		return ansiGreen
+	}
+}
@@ -0,0 +1,117 @@
+package report
+
+import (
+	"fmt"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Delta é a variação de uma métrica entre dois relatórios.
+type Delta struct {
+	Metric         string
+	Base           float64
+	Current        float64
+	Unit           string
+	HigherIsBetter bool
+}
+
+// Change é a variação percentual em relação à base.
+func (d Delta) Change() float64 {
+	if d.Base == 0 {
+		if d.Current == 0 {
+			return 0
+		}
+		return 100
+	}
+	return (d.Current - d.Base) / d.Base * 100
+}
+
+// Worse informa se a métrica piorou, independente do sentido dela.
+func (d Delta) Worse() bool {
+	if d.HigherIsBetter {
+		return d.Current < d.Base
+	}
+	return d.Current > d.Base
+}
+
+// Compare calcula as variações das métricas principais entre base e
+// current. Os percentis são recalculados das durações de cada relatório.
+func Compare(base, current loadtest.Report) []Delta {
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	latency := func(name string, b, c time.Duration) Delta {
+		return Delta{Metric: name, Base: ms(b), Current: ms(c), Unit: "ms"}
+	}
+	return []Delta{
+		{Metric: "RPS", Base: base.RPS, Current: current.RPS, Unit: "req/s", HigherIsBetter: true},
+		{Metric: "Error rate", Base: ErrorRate(base), Current: ErrorRate(current), Unit: "%"},
+		latency("Average", base.AvgDuration, current.AvgDuration),
+		latency("P50", base.Percentile(50), current.Percentile(50)),
+		latency("P90", base.Percentile(90), current.Percentile(90)),
+		latency("P95", base.Percentile(95), current.Percentile(95)),
+		latency("P99", base.Percentile(99), current.Percentile(99)),
+		latency("Maximum", base.MaxDuration, current.MaxDuration),
+	}
+}
+
+// PrintComparison imprime a tabela de variações entre os relatórios.
+func PrintComparison(deltas []Delta) {
+	printf("\n⚖️  Comparison with Baseline\n")
+	fmt.Printf("----------------------------------------\n")
+	fmt.Printf("| %-10s | %-14s | %-14s | %-9s |    |\n", Msg("Metric"), Msg("Baseline"), Msg("Current"), Msg("Change"))
+	for _, d := range deltas {
+		mark, color := "  ", ""
+		switch {
+		case d.Base == d.Current:
+		case d.Worse():
+			mark, color = "🔴", ansiRed
+		default:
+			mark, color = "🟢", ansiGreen
+		}
+		change := fmt.Sprintf("%+8.1f%%", d.Change())
+		if color != "" {
+			change = paint(color, change)
+		}
+		fmt.Printf("| %-10s | %-14s | %-14s | %s | %s |\n",
+			Msg(d.Metric), formatDeltaValue(d.Base, d.Unit), formatDeltaValue(d.Current, d.Unit), change, mark)
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+func formatDeltaValue(value float64, unit string) string {
+	if unit == "%" {
+		return fmt.Sprintf("%.2f%%", value)
+	}
+	return fmt.Sprintf("%.2f %s", value, unit)
+}
+
+// Tolerances são as pioras aceitas em relação ao -baseline.
+type Tolerances struct {
+	P95       float64 // percentual
+	RPS       float64 // percentual
+	ErrorRate float64 // pontos percentuais
+}
+
+// Regressions devolve como violações as métricas que pioraram além das
+// tolerâncias.
+func (t Tolerances) Regressions(deltas []Delta) Verdict {
+	verdict := Verdict{Failures: []string{}}
+	for _, d := range deltas {
+		switch d.Metric {
+		case "P95":
+			if d.Worse() && d.Change() > t.P95 {
+				verdict.fail("p95 regressed %.1f%% (%.2f ms -> %.2f ms, tolerance %.1f%%)", d.Change(), d.Base, d.Current, t.P95)
+			}
+		case "RPS":
+			if d.Worse() && -d.Change() > t.RPS {
+				verdict.fail("RPS dropped %.1f%% (%.2f -> %.2f, tolerance %.1f%%)", -d.Change(), d.Base, d.Current, t.RPS)
+			}
+		case "Error rate":
+			if increase := d.Current - d.Base; increase > t.ErrorRate {
+				verdict.fail("error rate rose %.2f points (%.2f%% -> %.2f%%, tolerance %.2f)", increase, d.Base, d.Current, t.ErrorRate)
+			}
+		}
+	}
+	verdict.Passed = len(verdict.Failures) == 0
+	return verdict
+}
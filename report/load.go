@@ -0,0 +1,22 @@
+package report
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Load lê um relatório salvo com -format json.
+func Load(path string) (loadtest.Report, error) {
+	var result loadtest.Report
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return result, err
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return result, fmt.Errorf("parsing report %s: %w", path, err)
+	}
+	return result, nil
+}
@@ -0,0 +1,149 @@
+package report
+
+import (
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Merge combina relatórios de vários workers ou execuções em um só.
+// Os percentis são recalculados a partir dos histogramas somados, nunca
+// pela média dos percentis. Os metadados são os do primeiro relatório. As
+// seções específicas de cada modo (gRPC, TCP, cache, range...) não são
+// combinadas.
+func Merge(reports []loadtest.Report) loadtest.Report {
+	merged := loadtest.Report{
+		StatusCodes:  make(map[int]int),
+		ErrorKinds:   make(map[loadtest.ErrorKind]int),
+		Latencies:    loadtest.NewHistogram(),
+		MinDuration:  time.Hour,
+		ErrorDetails: make(map[string]loadtest.ErrorDetail),
+		Protocols:    make(map[string]int),
+		TLSVersions:  make(map[string]int),
+		IPFamilies:   make(map[string]int),
+		PerIP:        make(map[string]*loadtest.IPStats),
+	}
+
+	for i, r := range reports {
+		if i == 0 {
+			merged.Mode = r.Mode
+			merged.Metadata = r.Metadata
+			merged.Seed = r.Seed
+			merged.SuccessStatus = r.SuccessStatus
+		}
+		merged.TotalTime = max(merged.TotalTime, r.TotalTime)
+		merged.SendWindow = max(merged.SendWindow, r.SendWindow)
+		merged.TotalRequests += r.TotalRequests
+		merged.Errors += r.Errors
+		merged.Latencies.Merge(r.Latencies)
+		// Relatórios antigos guardavam as durações em vez do histograma
+		for _, d := range r.Durations {
+			merged.Latencies.Add(d)
+		}
+		// Um relatório sem sucessos tem as durações das falhas, que não
+		// entram no mínimo e no máximo
+		if r.HasLatency() {
+			merged.MinDuration = min(merged.MinDuration, r.MinDuration)
+			merged.MaxDuration = max(merged.MaxDuration, r.MaxDuration)
+		}
+		for code, count := range r.StatusCodes {
+			merged.StatusCodes[code] += count
+		}
+		for kind, count := range r.ErrorKinds {
+			merged.ErrorKinds[kind] += count
+		}
+		for name, category := range r.ErrorCategories {
+			if merged.ErrorCategories == nil {
+				merged.ErrorCategories = make(map[string]loadtest.ErrorCategory)
+			}
+			existing := merged.ErrorCategories[name]
+			existing.Severity = category.Severity
+			existing.Count += category.Count
+			merged.ErrorCategories[name] = existing
+		}
+		for msg, detail := range r.ErrorDetails {
+			existing, ok := merged.ErrorDetails[msg]
+			if !ok {
+				existing = loadtest.ErrorDetail{Message: detail.Message, Code: detail.Code, Kind: detail.Kind}
+			}
+			existing.Count += detail.Count
+			merged.ErrorDetails[msg] = existing
+		}
+		mergeCounts(merged.Protocols, r.Protocols)
+		mergeCounts(merged.TLSVersions, r.TLSVersions)
+		mergeCounts(merged.IPFamilies, r.IPFamilies)
+		for ip, stats := range r.PerIP {
+			existing, ok := merged.PerIP[ip]
+			if !ok {
+				existing = &loadtest.IPStats{}
+				merged.PerIP[ip] = existing
+			}
+			existing.Requests += stats.Requests
+			existing.Errors += stats.Errors
+			existing.Latency.Merge(stats.Latency)
+		}
+		merged.Phases.Merge(r.Phases)
+		merged.NewConns += r.NewConns
+		merged.ReusedConns += r.ReusedConns
+		merged.BytesRead += r.BytesRead
+		merged.BytesDecoded += r.BytesDecoded
+		merged.Compressed += r.Compressed
+		merged.Redirects += r.Redirects
+		merged.BytesSent += r.BytesSent
+		merged.Stopped = merged.Stopped || r.Stopped
+		merged.Aborted = merged.Aborted || r.Aborted
+		merged.MaxDurationReached = merged.MaxDurationReached || r.MaxDurationReached
+	}
+
+	// Recalcular as métricas derivadas como em collectResults
+	if merged.Latencies.Count == 0 {
+		merged.MinDuration = 0
+	}
+	merged.AvgDuration = merged.Latencies.Mean()
+	if merged.TotalRequests > 0 {
+		merged.AvgRedirects = float64(merged.Redirects) / float64(merged.TotalRequests)
+	}
+	upload := merged.Phases.RequestUpload
+	if uploadTime := upload.Avg * time.Duration(upload.Count); uploadTime > 0 {
+		merged.UploadRate = float64(merged.BytesSent) / uploadTime.Seconds()
+	}
+	if merged.Window() > 0 {
+		merged.RPS = float64(merged.TotalRequests) / merged.Window().Seconds()
+	}
+	merged.StdDeviation = merged.Latencies.StdDeviation()
+	if !merged.HasLatency() {
+		merged.MinDuration, merged.MaxDuration, merged.AvgDuration, merged.StdDeviation = 0, 0, 0, 0
+	}
+	return merged
+}
+
+// MergeSegments junta os trechos de um teste retomado com -resume. Ao
+// contrário do Merge, os trechos rodaram um depois do outro: o tempo total é
+// a soma deles, e o desfecho (parado, abortado, mudanças de concorrência e
+// metadados) é o do último trecho.
+func MergeSegments(segments []loadtest.Report) loadtest.Report {
+	merged := Merge(segments)
+	last := segments[len(segments)-1]
+	merged.TotalTime, merged.SendWindow = 0, 0
+	for _, segment := range segments {
+		merged.TotalTime += segment.TotalTime
+		merged.SendWindow += segment.SendWindow
+	}
+	if merged.Window() > 0 {
+		merged.RPS = float64(merged.TotalRequests) / merged.Window().Seconds()
+	}
+	merged.Stopped = last.Stopped
+	merged.Aborted = last.Aborted
+	merged.MaxDurationReached = last.MaxDurationReached
+	merged.ConcurrencyChanges = last.ConcurrencyChanges
+	merged.Timeline = last.Timeline
+	merged.Metadata = last.Metadata
+	merged.Seed = last.Seed
+	return merged
+}
+
+func mergeCounts(dst, src map[string]int) {
+	for k, v := range src {
+		dst[k] += v
+	}
+}
@@ -0,0 +1,47 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Um relatório em que tudo falhou ainda tem as durações das falhas, mas
+// não pode levar o mínimo e o máximo do relatório combinado.
+func TestMergeIgnoresLatencyOfAllFailedReport(t *testing.T) {
+	good := loadtest.Report{
+		TotalRequests: 2,
+		TotalTime:     time.Second,
+		StatusCodes:   map[int]int{200: 2},
+		ErrorKinds:    map[loadtest.ErrorKind]int{},
+		Durations:     []time.Duration{10 * time.Millisecond, 30 * time.Millisecond},
+		MinDuration:   10 * time.Millisecond,
+		MaxDuration:   30 * time.Millisecond,
+	}
+	failed := loadtest.Report{
+		TotalRequests: 2,
+		Errors:        2,
+		TotalTime:     time.Second,
+		StatusCodes:   map[int]int{},
+		ErrorKinds:    map[loadtest.ErrorKind]int{loadtest.ErrorConnRefused: 2},
+		Durations:     []time.Duration{time.Millisecond, 50 * time.Millisecond},
+		MinDuration:   time.Millisecond,
+		MaxDuration:   50 * time.Millisecond,
+	}
+
+	for name, merged := range map[string]loadtest.Report{
+		"Merge":         Merge([]loadtest.Report{good, failed}),
+		"MergeSegments": MergeSegments([]loadtest.Report{good, failed}),
+	} {
+		if merged.MinDuration != 10*time.Millisecond {
+			t.Errorf("%s: MinDuration = %v, want 10ms", name, merged.MinDuration)
+		}
+		if merged.MaxDuration != 30*time.Millisecond {
+			t.Errorf("%s: MaxDuration = %v, want 30ms", name, merged.MaxDuration)
+		}
+		if !merged.HasLatency() {
+			t.Errorf("%s: HasLatency() = false", name)
+		}
+	}
+}
@@ -0,0 +1,264 @@
+package report
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Lang é o idioma do texto impresso pelo CLI. JSON, CSV e logs ficam
+// sempre em inglês, para não quebrar quem os processa.
+var Lang = "en"
+
+// Langs são os idiomas aceitos em Lang.
+var Langs = []string{"en", "pt-BR"}
+
+// CheckLang confere se há mensagens para lang.
+func CheckLang(lang string) error {
+	if !slices.Contains(Langs, lang) {
+		return fmt.Errorf("invalid -lang %q, expected %s", lang, strings.Join(Langs, " or "))
+	}
+	return nil
+}
+
+// Msg traduz uma mensagem para Lang. A chave é o próprio texto em inglês,
+// que volta inalterado quando não há tradução; quebras de linha nas pontas
+// ficam fora do catálogo.
+func Msg(key string) string {
+	text := strings.Trim(key, "\n")
+	translated, ok := catalog[Lang][text]
+	if !ok {
+		return key
+	}
+	i := strings.Index(key, text)
+	return key[:i] + translated + key[i+len(text):]
+}
+
+func printf(format string, args ...any) {
+	fmt.Printf(Msg(format), args...)
+}
+
+func sprintf(format string, args ...any) string {
+	return fmt.Sprintf(Msg(format), args...)
+}
+
+var catalog = map[string]map[string]string{
+	"pt-BR": {
+		// Resumo
+		"📊 Test Results Summary":                                          "📊 Resumo dos Resultados",
+		"Total Time: %.2f seconds":                                        "Tempo Total: %.2f segundos",
+		"Send Window: %.2f seconds (first request sent to last response)": "Janela de Envio: %.2f segundos (do envio da primeira requisição à última resposta)",
+		"Total Requests: %d":                                              "Total de Requisições: %d",
+		"Requests per Second: %.2f":                                       "Requisições por Segundo: %.2f",
+		"⛔ Test aborted: partial results":                                 "⛔ Teste abortado: resultados parciais",
+		"⏱️ Stopped by -max-duration before all requests were sent":       "⏱️ Parado pelo -max-duration antes de enviar todas as requisições",
+		"👥 Concurrency changed at %v: %d -> %d":                           "👥 Concorrência alterada em %v: %d -> %d",
+		"🔄 Reloaded at %v: %s":                                            "🔄 Recarregado em %v: %s",
+		"⏹️ Test stopped early":                                           "⏹️ Teste interrompido antes do fim",
+		"Labels: %s":                                                      "Rótulos: %s",
+		"Git: %s (%s) on %s":                                              "Git: %s (%s) em %s",
+		"Body Size: %s":                                                   "Tamanho do Corpo: %s",
+		"Seed: %d":                                                        "Semente: %d",
+		"Protocol %s: %d requests":                                        "Protocolo %s: %d requisições",
+		"Negotiated %s: %d requests":                                      "%s negociado: %d requisições",
+		"Address family %s: %d requests":                                  "Família de endereço %s: %d requisições",
+		"Connections: %d new, %d reused":                                  "Conexões: %d novas, %d reutilizadas",
+		"Bytes Received: %d (%d decoded, %d compressed responses)":        "Bytes Recebidos: %d (%d decodificados, %d respostas comprimidas)",
+		"Bytes Sent: %d (upload %.2f MB/s)":                               "Bytes Enviados: %d (upload %.2f MB/s)",
+		"Expect 100-continue: %d sent | %d continued | %d rejected early | %d timed out":                             "Expect 100-continue: %d enviados | %d continuados | %d rejeitados antes | %d expirados",
+		"Range Requests: %d sent | %d 206 ok | %d ignored (200) | %d wrong Content-Range | %d not satisfiable (416)": "Requisições Range: %d enviadas | %d 206 ok | %d ignoradas (200) | %d Content-Range errado | %d não satisfeitas (416)",
+		"Redirects Followed: %d (%.2f per request)":                                                                  "Redirecionamentos Seguidos: %d (%.2f por requisição)",
+
+		// Tempo de resposta e fases
+		"⚡ Response Time Stats": "⚡ Estatísticas do Tempo de Resposta",
+		"Minimum: %v":           "Mínimo: %v",
+		"Maximum: %v":           "Máximo: %v",
+		"Average: %v":           "Média: %v",
+		"No successful requests to measure response time": "Nenhuma requisição com sucesso para medir o tempo de resposta",
+		"🔍 Latency Breakdown":                             "🔍 Latência por Fase",
+		"%-20s avg %v | min %v | max %v (%d requests)":    "%-20s média %v | mín %v | máx %v (%d requisições)",
+		"%-20s avg %v | min %v | max %v (%d samples)":     "%-20s média %v | mín %v | máx %v (%d amostras)",
+		"DNS Lookup":         "Consulta DNS",
+		"TCP Connect":        "Conexão TCP",
+		"TLS Handshake":      "Handshake TLS",
+		"Request Upload":     "Envio da Requisição",
+		"Time to First Byte": "Tempo até o 1º Byte",
+		"Content Transfer":   "Transferência",
+		"Connection Setup":   "Abertura da Conexão",
+		"🌐 Per-IP Breakdown": "🌐 Resultados por IP",
+		"%-40s %d requests | avg %s | max %s | errors %d (%.1f%%)": "%-40s %d requisições | média %s | máx %s | erros %d (%.1f%%)",
+		"📎 Response Trailers (%d responses)":                       "📎 Trailers da Resposta (%d respostas)",
+		"%s: %s -> %d responses":                                   "%s: %s -> %d respostas",
+
+		// Status e erros
+		"📈 Status Code Distribution":              "📈 Distribuição dos Códigos de Status",
+		"✅ Success (%s): %d requests (%.1f%%)":    "✅ Sucesso (%s): %d requisições (%.1f%%)",
+		"%s Status %d (%s): %d requests (%.1f%%)": "%s Status %d (%s): %d requisições (%.1f%%)",
+		"Status Code %d":                          "Código de Status %d",
+		"Unidentified error":                      "Erro não identificado",
+		"❌ Total Errors: %d (%.1f%%)":             "❌ Total de Erros: %d (%.1f%%)",
+		"❌ Error Details:":                        "❌ Detalhes dos Erros:",
+		"Error Message":                           "Mensagem de Erro",
+		"Count":                                   "Qtde",
+		"Percent":                                 "Percentual",
+
+		// Cache e protocolos
+		"🗄️  Cache Validation":                                   "🗄️  Validação de Cache",
+		"Conditional Requests: %d (%d 304 Not Modified, %.1f%%)": "Requisições Condicionais: %d (%d 304 Not Modified, %.1f%%)",
+		"Validation":                       "Validação",
+		"Full Response":                    "Resposta Completa",
+		"📈 gRPC Status Distribution":       "📈 Distribuição dos Status gRPC",
+		"%s %s (%d): %d requests (%.1f%%)": "%s %s (%d): %d requisições (%.1f%%)",
+		"🔁 gRPC Streams":                   "🔁 Streams gRPC",
+		"Streams: %d | Messages sent: %d | Messages received: %d": "Streams: %d | Mensagens enviadas: %d | Mensagens recebidas: %d",
+		"Message Latency":      "Latência da Mensagem",
+		"Stream Duration":      "Duração do Stream",
+		"📡 Server-Sent Events": "📡 Server-Sent Events",
+		"Connections: %d | Events: %d | Dropped: %d (%.1f%%)": "Conexões: %d | Eventos: %d | Perdidas: %d (%.1f%%)",
+		"Time to First Event":                                   "Tempo até o 1º Evento",
+		"Inter-Event":                                           "Entre Eventos",
+		"🔌 TCP Results":                                         "🔌 Resultados TCP",
+		"Connections: %d (%d failed)":                           "Conexões: %d (%d falharam)",
+		"Bytes Sent: %d | Bytes Received: %d":                   "Bytes Enviados: %d | Bytes Recebidos: %d",
+		"❌ Echo mismatches: %d":                                 "❌ Ecos divergentes: %d",
+		"Connect latency: avg %v | min %v | max %v":             "Latência de conexão: média %v | mín %v | máx %v",
+		"📨 UDP Results":                                         "📨 Resultados UDP",
+		"Datagrams Sent: %d":                                    "Datagramas Enviados: %d",
+		"Replies: %d | Lost: %d (%.1f%% loss)":                  "Respostas: %d | Perdidos: %d (%.1f%% de perda)",
+		"❌ Reply mismatches: %d":                                "❌ Respostas divergentes: %d",
+		"Round trip: avg %v | min %v | max %v":                  "Ida e volta: média %v | mín %v | máx %v",
+		"🧭 DNS Results":                                         "🧭 Resultados DNS",
+		"%s %s: %d queries (%.1f%%)":                            "%s %s: %d consultas (%.1f%%)",
+		"⏱️ Timeouts: %d queries (%.1f%%)":                      "⏱️ Timeouts: %d consultas (%.1f%%)",
+		"Truncated responses: %d":                               "Respostas truncadas: %d",
+		"📶 MQTT Results":                                        "📶 Resultados MQTT",
+		"Clients: %d (%d failed to connect)":                    "Clientes: %d (%d não conectaram)",
+		"Connect time: avg %v | min %v | max %v":                "Tempo de conexão: média %v | mín %v | máx %v",
+		"Messages received: %d":                                 "Mensagens recebidas: %d",
+		"❌ Broker disconnects: %d":                              "❌ Desconexões do broker: %d",
+		"🧱 Redis Commands":                                      "🧱 Comandos Redis",
+		"%-10s %d calls | %d errors":                            "%-10s %d chamadas | %d erros",
+		" | %d nil replies":                                     " | %d respostas nil",
+		"           avg %v | P50 %v | P90 %v | P99 %v | max %v": "           média %v | P50 %v | P90 %v | P99 %v | máx %v",
+		"📦 Body size %s":                                        "📦 Tamanho do corpo %s",
+		"📦 Payload Size Sweep":                                  "📦 Variação do Tamanho do Corpo",
+		"Size":                                                  "Tamanho",
+		"Requests":                                              "Requisições",
+		"Errors":                                                "Erros",
+		"Avg":                                                   "Média",
+		"Upload MB/s":                                           "Upload MB/s",
+
+		// Comparação e limites
+		"⚖️  Comparison with Baseline": "⚖️  Comparação com a Base",
+		"Metric":                       "Métrica",
+		"Baseline":                     "Base",
+		"Current":                      "Atual",
+		"Change":                       "Variação",
+		"Error rate":                   "Taxa de erros",
+		"Minimum":                      "Mínimo",
+		"Average":                      "Média",
+		"Maximum":                      "Máximo",
+		"n/a":                          "n/d",
+		"p95 regressed %.1f%% (%.2f ms -> %.2f ms, tolerance %.1f%%)":    "p95 piorou %.1f%% (%.2f ms -> %.2f ms, tolerância %.1f%%)",
+		"RPS dropped %.1f%% (%.2f -> %.2f, tolerance %.1f%%)":            "RPS caiu %.1f%% (%.2f -> %.2f, tolerância %.1f%%)",
+		"error rate rose %.2f points (%.2f%% -> %.2f%%, tolerance %.2f)": "taxa de erros subiu %.2f pontos (%.2f%% -> %.2f%%, tolerância %.2f)",
+		"p95 %v is above %v":                "p95 %v está acima de %v",
+		"error rate %.2f%% is above %.2f%%": "taxa de erros %.2f%% está acima de %.2f%%",
+		"%.2f requests/s is below %.2f":     "%.2f requisições/s está abaixo de %.2f",
+		"✅ Thresholds passed":               "✅ Limites atendidos",
+		"❌ Thresholds failed":               "❌ Limites violados",
+
+		// Validação, depuração e histórico
+		"❌ Dry run failed: %v":                                "❌ Validação falhou: %v",
+		"🔎 Dry run: configuration is valid, no load was sent": "🔎 Validação: configuração válida, nenhuma carga foi enviada",
+		"Mode: %s":                        "Modo: %s",
+		"Requests: %d | Concurrency: %d":  "Requisições: %d | Concorrência: %d",
+		"Target: %s -> %s":                "Destino: %s -> %s",
+		"Target: %s":                      "Destino: %s",
+		"📨 First request":                 "📨 Primeira requisição",
+		"%s(binary body, %s)":             "%s(corpo binário, %s)",
+		"%s... (%s in total)":             "%s... (%s no total)",
+		"unknown size":                    "tamanho desconhecido",
+		"❌ Debug request failed: %v":      "❌ Requisição de depuração falhou: %v",
+		"🐞 Debug request (%s)":            "🐞 Requisição de depuração (%s)",
+		"⏱️ Timing: %v total":             "⏱️ Tempo: %v no total",
+		"Negotiated %s":                   "%s negociado",
+		"Redirects Followed: %d":          "Redirecionamentos Seguidos: %d",
+		"Bytes Received: %d (%d decoded)": "Bytes Recebidos: %d (%d decodificados)",
+		"🗂️  Run %d: %s":                  "🗂️  Execução %d: %s",
+		"Started: %s":                     "Início: %s",
+		"Git commit: %s":                  "Commit Git: %s",
+		"Arguments: %s":                   "Argumentos: %s",
+		"Result: %s":                      "Resultado: %s",
+		"Errors: %d (%.2f%%)":             "Erros: %d (%.2f%%)",
+		"No runs recorded yet":            "Nenhuma execução registrada ainda",
+		"No runs recorded for %q":         "Nenhuma execução registrada para %q",
+		"ID\tSTARTED\tNAME\tREQUESTS\tRPS\tP95\tERRORS\tRESULT": "ID\tINÍCIO\tNOME\tREQUISIÇÕES\tRPS\tP95\tERROS\tRESULTADO",
+		"passed":                   "aprovado",
+		"failed":                   "reprovado",
+		"stopped":                  "interrompido",
+		"📈 Trend for %s (%d runs)": "📈 Tendência de %s (%d execuções)",
+		"ID\tSTARTED\tRPS\tP50\tP95\tP99\tERRORS\tUNUSUAL":                                "ID\tINÍCIO\tRPS\tP50\tP95\tP99\tERROS\tINCOMUM",
+		"%s changed from a constant %.2f":                                                 "%s mudou de um valor constante %.2f",
+		"ℹ️  At least %d earlier runs are needed before changes are flagged":              "ℹ️  São necessárias ao menos %d execuções anteriores para apontar mudanças",
+		"💾 %d requests written to %s":                                                     "💾 %d requisições gravadas em %s",
+		"Replay with: -targets %s -preserve-timing":                                       "Reproduza com: -targets %s -preserve-timing",
+		"File exists, overwrite? (y/N)":                                                   "O arquivo já existe, sobrescrever? (y/N)",
+		"✅ Config written to %s":                                                          "✅ Configuração gravada em %s",
+		"Run it with: %s -config %s":                                                      "Execute com: %s -config %s",
+		"Target URL":                                                                      "URL do alvo",
+		"HTTP method":                                                                     "Método HTTP",
+		"Headers as Name:Value, separated by commas (empty for none)":                     "Headers no formato Nome:Valor, separados por vírgula (vazio para nenhum)",
+		"Request body (empty for none)":                                                   "Corpo da requisição (vazio para nenhum)",
+		"Total number of requests":                                                        "Número total de requisições",
+		"Concurrent requests":                                                             "Requisições simultâneas",
+		"Time limit, e.g. 5m (empty for none)":                                            "Tempo máximo, ex.: 5m (vazio para nenhum)",
+		"Fail when the p95 latency is above, e.g. 500ms (empty to skip)":                  "Falhar quando a latência p95 passar de, ex.: 500ms (vazio para pular)",
+		"Fail when the error rate is above this percentage (empty to skip)":               "Falhar quando a taxa de erros passar desta porcentagem (vazio para pular)",
+		"Fail when the throughput is below this many requests per second (empty to skip)": "Falhar quando a vazão ficar abaixo destas requisições por segundo (vazio para pular)",
+		"enter a full URL, e.g. https://api.example.com/health":                           "informe a URL completa, ex.: https://api.example.com/health",
+		"enter a method such as GET or POST":                                              "informe um método como GET ou POST",
+		"invalid header %q, expected Name:Value":                                          "header %q inválido, esperado Nome:Valor",
+		"enter a whole number of at least 1":                                              "informe um número inteiro maior ou igual a 1",
+		"enter a duration such as 500ms, 30s or 5m":                                       "informe uma duração como 500ms, 30s ou 5m",
+		"enter a percentage between 0 and 100":                                            "informe uma porcentagem entre 0 e 100",
+		"enter a number of at least 0":                                                    "informe um número maior ou igual a 0",
+
+		// Falhas de transporte
+		"❌ %s (%s): %d requests (%.1f%%)": "❌ %s (%s): %d requisições (%.1f%%)",
+		"DNS resolution failed":           "Falha na resolução DNS",
+		"Connection refused":              "Conexão recusada",
+		"Connection reset":                "Conexão reiniciada",
+		"Connection closed":               "Conexão encerrada",
+		"Connect timeout":                 "Timeout de conexão",
+		"TLS or certificate error":        "Erro de TLS ou certificado",
+		"TLS handshake timeout":           "Timeout do handshake TLS",
+		"No response before the timeout":  "Sem resposta dentro do timeout",
+		"Timeout":                         "Timeout",
+		"Too many redirects":              "Redirecionamentos demais",
+		"Other error":                     "Outro erro",
+
+		// Categorias do -error-rules
+		"🏷️ Error Categories":               "🏷️ Categorias de Erro",
+		"%-20s %-9s %d requests (%.1f%%)":   "%-20s %-9s %d requisições (%.1f%%)",
+		"info":                              "info",
+		"warning":                           "alerta",
+		"critical":                          "crítico",
+		"%s errors %.2f%% are above %.2f%%": "erros %s em %.2f%% estão acima de %.2f%%",
+
+		// Suítes
+		"▶ Test %d/%d: %s":                             "▶ Teste %d/%d: %s",
+		"📋 Suite Results (%d tests, %s, %.2f seconds)": "📋 Resultado da Suíte (%d testes, %s, %.2f segundos)",
+		"sequential": "em sequência",
+		"parallel":   "em paralelo",
+		"TEST\tREQUESTS\tERRORS\tRPS\tP95\tRESULT": "TESTE\tREQUISIÇÕES\tERROS\tRPS\tP95\tRESULTADO",
+		"✅ passed":                       "✅ aprovado",
+		"❌ thresholds failed":            "❌ limites violados",
+		"❌ all requests failed":          "❌ todas as requisições falharam",
+		"⛔ aborted":                      "⛔ abortado",
+		"💥 error":                        "💥 erro",
+		"⏭️ skipped":                     "⏭️ não executado",
+		"✅ Suite passed: %d of %d tests": "✅ Suíte aprovada: %d de %d testes",
+		"❌ Suite failed: %d of %d tests did not pass": "❌ Suíte reprovada: %d de %d testes não passaram",
+	},
+}
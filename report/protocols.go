@@ -0,0 +1,192 @@
+package report
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"google.golang.org/grpc/codes"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+func printCacheStats(c loadtest.CacheStats) {
+	printf("🗄️  Cache Validation\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Conditional Requests: %d (%d 304 Not Modified, %.1f%%)\n", c.Conditional, c.NotModified, c.NotModifiedPc)
+	for _, phase := range []loadtest.NamedPhase{{Name: "Validation", Stats: c.Validation}, {Name: "Full Response", Stats: c.Full}} {
+		if phase.Stats.Count == 0 {
+			fmt.Printf("%-20s n/a\n", Msg(phase.Name)+":")
+			continue
+		}
+		printf("%-20s avg %v | min %v | max %v (%d requests)\n",
+			Msg(phase.Name)+":", phase.Stats.Avg, phase.Stats.Min, phase.Stats.Max, phase.Stats.Count)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+}
+
+func printGRPCStatusCodes(report loadtest.Report) {
+	printf("📈 gRPC Status Distribution\n")
+	fmt.Printf("----------------------------------------\n")
+
+	var codeList []int
+	for code := range report.StatusCodes {
+		codeList = append(codeList, code)
+	}
+	sort.Ints(codeList)
+
+	for _, code := range codeList {
+		count := report.StatusCodes[code]
+		percentage := float64(count) / float64(report.TotalRequests) * 100
+		icon := "❌"
+		if codes.Code(code) == codes.OK {
+			icon = "✅"
+		}
+		printf("%s %s (%d): %d requests (%.1f%%)\n", icon, codes.Code(code), code, count, percentage)
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+func printStreamStats(s loadtest.StreamStats) {
+	printf("🔁 gRPC Streams\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Streams: %d | Messages sent: %d | Messages received: %d\n", s.Streams, s.MessagesSent, s.MessagesReceived)
+	for _, phase := range []loadtest.NamedPhase{{Name: "Message Latency", Stats: s.MessageLatency}, {Name: "Stream Duration", Stats: s.StreamDuration}} {
+		if phase.Stats.Count == 0 {
+			fmt.Printf("%-20s n/a\n", Msg(phase.Name)+":")
+			continue
+		}
+		printf("%-20s avg %v | min %v | max %v (%d samples)\n",
+			Msg(phase.Name)+":", phase.Stats.Avg, phase.Stats.Min, phase.Stats.Max, phase.Stats.Count)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+}
+
+func printSSEStats(s loadtest.SSEStats) {
+	printf("📡 Server-Sent Events\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Connections: %d | Events: %d | Dropped: %d (%.1f%%)\n", s.Connections, s.Events, s.Dropped, s.DropRate)
+	for _, phase := range []loadtest.NamedPhase{{Name: "Time to First Event", Stats: s.FirstEvent}, {Name: "Inter-Event", Stats: s.InterEvent}} {
+		if phase.Stats.Count == 0 {
+			fmt.Printf("%-20s n/a\n", Msg(phase.Name)+":")
+			continue
+		}
+		printf("%-20s avg %v | min %v | max %v (%d samples)\n",
+			Msg(phase.Name)+":", phase.Stats.Avg, phase.Stats.Min, phase.Stats.Max, phase.Stats.Count)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+}
+
+func printTCPStats(s loadtest.TCPStats) {
+	printf("🔌 TCP Results\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Connections: %d (%d failed)\n", s.Connections, s.Failed)
+	printf("Bytes Sent: %d | Bytes Received: %d\n", s.BytesSent, s.BytesReceived)
+	if s.EchoMismatch > 0 {
+		printf("❌ Echo mismatches: %d\n", s.EchoMismatch)
+	}
+	if s.Connect.Count > 0 {
+		printf("Connect latency: avg %v | min %v | max %v\n", s.Connect.Avg, s.Connect.Min, s.Connect.Max)
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+func printUDPStats(s loadtest.UDPStats) {
+	printf("📨 UDP Results\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Datagrams Sent: %d\n", s.Sent)
+	if s.Replies+s.Lost > 0 {
+		printf("Replies: %d | Lost: %d (%.1f%% loss)\n", s.Replies, s.Lost, s.LossRate)
+	}
+	if s.Mismatch > 0 {
+		printf("❌ Reply mismatches: %d\n", s.Mismatch)
+	}
+	if s.RTT.Count > 0 {
+		printf("Round trip: avg %v | min %v | max %v\n", s.RTT.Avg, s.RTT.Min, s.RTT.Max)
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+func printDNSStats(s loadtest.DNSStats) {
+	printf("🧭 DNS Results\n")
+	fmt.Printf("----------------------------------------\n")
+	codes := make([]string, 0, len(s.RCodes))
+	for code := range s.RCodes {
+		codes = append(codes, code)
+	}
+	sort.Strings(codes)
+	for _, code := range codes {
+		icon := "❌"
+		if code == "NOERROR" || code == "NXDOMAIN" {
+			icon = "✅"
+		}
+		count := s.RCodes[code]
+		printf("%s %s: %d queries (%.1f%%)\n", icon, code, count, float64(count)/float64(s.Queries)*100)
+	}
+	if s.Timeouts > 0 {
+		printf("⏱️ Timeouts: %d queries (%.1f%%)\n", s.Timeouts, float64(s.Timeouts)/float64(s.Queries)*100)
+	}
+	if s.Truncated > 0 {
+		printf("Truncated responses: %d\n", s.Truncated)
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+func printMQTTStats(s loadtest.MQTTStats) {
+	printf("📶 MQTT Results\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Clients: %d (%d failed to connect)\n", s.Clients, s.ConnectFailed)
+	if s.Connect.Count > 0 {
+		printf("Connect time: avg %v | min %v | max %v\n", s.Connect.Avg, s.Connect.Min, s.Connect.Max)
+	}
+	if s.Received > 0 {
+		printf("Messages received: %d\n", s.Received)
+	}
+	if s.Disconnects > 0 {
+		printf("❌ Broker disconnects: %d\n", s.Disconnects)
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+func printRedisStats(s loadtest.RedisStats) {
+	printf("🧱 Redis Commands\n")
+	fmt.Printf("----------------------------------------\n")
+	commands := make([]string, 0, len(s.Commands))
+	for command := range s.Commands {
+		commands = append(commands, command)
+	}
+	sort.Strings(commands)
+	for _, command := range commands {
+		stats := s.Commands[command]
+		printf("%-10s %d calls | %d errors", command, stats.Count, stats.Errors)
+		if stats.Misses > 0 {
+			printf(" | %d nil replies", stats.Misses)
+		}
+		fmt.Println()
+		if stats.Latency.Count > 0 {
+			printf("           avg %v | P50 %v | P90 %v | P99 %v | max %v\n",
+				stats.Latency.Avg, stats.P50, stats.P90, stats.P99, stats.Latency.Max)
+		}
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+// PrintSizeSweep compara as rodadas do -body-sizes.
+func PrintSizeSweep(steps []loadtest.SweepStep) {
+	printf("\n📦 Payload Size Sweep\n")
+	fmt.Printf("----------------------------------------\n")
+	fmt.Printf("| %-10s | %-8s | %-8s | %-12s | %-12s | %-10s | %-12s |\n",
+		Msg("Size"), Msg("Requests"), Msg("Errors"), Msg("Avg"), "P95", "RPS", Msg("Upload MB/s"))
+	for _, step := range steps {
+		r := step.Report
+		fmt.Printf("| %-10s | %-8d | %-8d | %-12v | %-12v | %-10.2f | %-12.2f |\n",
+			loadtest.FormatByteSize(step.Size),
+			r.TotalRequests,
+			r.Errors,
+			r.AvgDuration.Round(time.Microsecond),
+			r.Percentile(95).Round(time.Microsecond),
+			r.RPS,
+			r.UploadRate/(1<<20))
+	}
+	fmt.Printf("----------------------------------------\n")
+}
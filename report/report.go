@@ -0,0 +1,440 @@
+// Package report formata, combina e avalia os relatórios produzidos pelo
+// pacote loadtest.
+package report
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Print escreve o relatório em texto no stdout.
+func Print(report loadtest.Report) {
+	printf("\n📊 Test Results Summary\n")
+	fmt.Printf("----------------------------------------\n")
+	printf("Total Time: %.2f seconds\n", report.TotalTime.Seconds())
+	if report.SendWindow > 0 {
+		printf("Send Window: %.2f seconds (first request sent to last response)\n", report.SendWindow.Seconds())
+	}
+	printf("Total Requests: %d\n", report.TotalRequests)
+	printf("Requests per Second: %.2f\n", report.RPS)
+	switch {
+	case report.Aborted:
+		fmt.Println(paint(ansiYellow, Msg("⛔ Test aborted: partial results")))
+	case report.MaxDurationReached:
+		printf("⏱️ Stopped by -max-duration before all requests were sent\n")
+	case report.Stopped:
+		printf("⏹️ Test stopped early\n")
+	}
+	if len(report.Metadata.Labels) > 0 {
+		var labels []string
+		for key, value := range report.Metadata.Labels {
+			labels = append(labels, key+"="+value)
+		}
+		sort.Strings(labels)
+		printf("Labels: %s\n", strings.Join(labels, ", "))
+	}
+	if m := report.Metadata; m.GitCommit != "" {
+		printf("Git: %s (%s) on %s\n", m.GitCommit, m.GitBranch, m.Hostname)
+	}
+	if report.Seed != 0 {
+		printf("Seed: %d\n", report.Seed)
+	}
+	if report.BodySize > 0 {
+		printf("Body Size: %s\n", loadtest.FormatByteSize(report.BodySize))
+	}
+	for proto, count := range report.Protocols {
+		printf("Protocol %s: %d requests\n", proto, count)
+	}
+	for version, count := range report.TLSVersions {
+		printf("Negotiated %s: %d requests\n", version, count)
+	}
+	for family, count := range report.IPFamilies {
+		printf("Address family %s: %d requests\n", family, count)
+	}
+	if report.Mode == "" {
+		printf("Connections: %d new, %d reused\n", report.NewConns, report.ReusedConns)
+		printf("Bytes Received: %d (%d decoded, %d compressed responses)\n",
+			report.BytesRead, report.BytesDecoded, report.Compressed)
+	}
+	if report.BytesSent > 0 && report.Mode == "" {
+		printf("Bytes Sent: %d (upload %.2f MB/s)\n", report.BytesSent, report.UploadRate/(1<<20))
+	}
+	if c := report.Continue; c.Sent > 0 {
+		printf("Expect 100-continue: %d sent | %d continued | %d rejected early | %d timed out\n",
+			c.Sent, c.Received, c.Rejected, c.TimedOut)
+	}
+	if r := report.Range; r.Sent > 0 {
+		printf("Range Requests: %d sent | %d 206 ok | %d ignored (200) | %d wrong Content-Range | %d not satisfiable (416)\n",
+			r.Sent, r.Partial, r.Ignored, r.Mismatch, r.NotSatisfiable)
+	}
+	if report.Redirects > 0 {
+		printf("Redirects Followed: %d (%.2f per request)\n", report.Redirects, report.AvgRedirects)
+	}
+	for _, event := range report.Timeline {
+		printf("🔄 Reloaded at %v: %s\n", event.At.Round(time.Second), event.Change)
+	}
+	for _, change := range report.ConcurrencyChanges {
+		printf("👥 Concurrency changed at %v: %d -> %d\n", change.At.Round(time.Second), change.From, change.To)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+
+	printf("⚡ Response Time Stats\n")
+	fmt.Printf("----------------------------------------\n")
+	if report.HasLatency() {
+		printf("Minimum: %v\n", report.MinDuration)
+		printf("Maximum: %v\n", report.MaxDuration)
+		printf("Average: %v\n", report.AvgDuration)
+		printf("P50: %v\n", report.Percentile(50))
+		printf("P90: %v\n", report.Percentile(90))
+		printf("P95: %v\n", report.Percentile(95))
+		printf("P99: %v\n", report.Percentile(99))
+	} else {
+		for _, name := range []string{"Minimum", "Maximum", "Average", "P50", "P90", "P95", "P99"} {
+			fmt.Printf("%s: %s\n", Msg(name), Msg("n/a"))
+		}
+		printf("No successful requests to measure response time\n")
+	}
+	fmt.Printf("----------------------------------------\n\n")
+
+	if report.Mode == "" {
+		// As fases vêm do httptrace e não existem nas chamadas gRPC
+		printPhaseBreakdown(report.Phases)
+	}
+	printPerIP(report)
+	printTrailers(report.Trailers)
+	if report.Cache.Conditional > 0 {
+		printCacheStats(report.Cache)
+	}
+	if report.Streams.Streams > 0 {
+		printStreamStats(report.Streams)
+	}
+	if report.SSE.Connections > 0 {
+		printSSEStats(report.SSE)
+	}
+
+	switch report.Mode {
+	case "grpc":
+		printGRPCStatusCodes(report)
+	case "tcp":
+		printTCPStats(report.TCP)
+	case "udp":
+		printUDPStats(report.UDP)
+	case "dns":
+		printDNSStats(report.DNS)
+	case "mqtt":
+		printMQTTStats(report.MQTT)
+	case "redis":
+		printRedisStats(report.Redis)
+	default:
+		printStatusCodes(report)
+	}
+
+	printErrorCategories(report)
+
+	if report.Errors > 0 {
+		errorRate := float64(report.Errors) / float64(report.TotalRequests) * 100
+		fmt.Println()
+		fmt.Println(paint(ansiRed, sprintf("❌ Total Errors: %d (%.1f%%)", report.Errors, errorRate)))
+	}
+}
+
+func printStatusCodes(report loadtest.Report) {
+	printf("📈 Status Code Distribution\n")
+	fmt.Printf("----------------------------------------\n")
+
+	// Ordenar códigos para exibição
+	var codes []int
+	for code := range report.StatusCodes {
+		codes = append(codes, code)
+	}
+	sort.Ints(codes)
+
+	// Destacar sucesso e falhas; o sucesso é a classe 2xx ou o -success-status
+	spec, success := successStatus(report)
+	successCount := 0
+	for code, count := range report.StatusCodes {
+		if success.Contains(code) {
+			successCount += count
+		}
+	}
+	successRate := float64(successCount) / float64(report.TotalRequests) * 100
+	fmt.Println(paint(statusColor(200), sprintf("✅ Success (%s): %d requests (%.1f%%)", spec, successCount, successRate)))
+
+	for _, code := range codes {
+		count := report.StatusCodes[code]
+		percentage := float64(count) / float64(report.TotalRequests) * 100
+
+		icon := "❌"
+		if success.Contains(code) {
+			icon = "✅"
+		} else if code >= 300 && code < 400 {
+			icon = "↪️"
+		}
+		fmt.Println(paint(statusColor(code), sprintf("%s Status %d (%s): %d requests (%.1f%%)",
+			icon, code, statusCodeDescription(code), count, percentage)))
+	}
+
+	// Falhas sem resposta, que não têm código de status
+	kinds := slices.Sorted(maps.Keys(report.ErrorKinds))
+	for _, kind := range kinds {
+		count := report.ErrorKinds[kind]
+		percentage := float64(count) / float64(report.TotalRequests) * 100
+		fmt.Println(paint(ansiRed, sprintf("❌ %s (%s): %d requests (%.1f%%)",
+			errorKindDescription(kind), kind, count, percentage)))
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+// printErrorCategories mostra as falhas agrupadas pelo -error-rules, das
+// categorias mais graves para as menos graves.
+func printErrorCategories(report loadtest.Report) {
+	if len(report.ErrorCategories) == 0 {
+		return
+	}
+	names := slices.Collect(maps.Keys(report.ErrorCategories))
+	slices.SortFunc(names, func(a, b string) int {
+		sa := slices.Index(loadtest.Severities, report.ErrorCategories[a].Severity)
+		sb := slices.Index(loadtest.Severities, report.ErrorCategories[b].Severity)
+		if sa != sb {
+			return sb - sa
+		}
+		return strings.Compare(a, b)
+	})
+
+	printf("\n🏷️ Error Categories\n")
+	fmt.Printf("----------------------------------------\n")
+	for _, name := range names {
+		category := report.ErrorCategories[name]
+		percentage := float64(category.Count) / float64(report.TotalRequests) * 100
+		color := ansiYellow
+		switch category.Severity {
+		case "critical":
+			color = ansiRed
+		case "info":
+			color = ansiCyan
+		}
+		fmt.Println(paint(color, sprintf("%-20s %-9s %d requests (%.1f%%)", name, Msg(category.Severity), category.Count, percentage)))
+	}
+	fmt.Printf("----------------------------------------\n")
+}
+
+// Latency formata uma latência, ou n/a quando ela não foi medida.
+func Latency(d time.Duration, measured bool) string {
+	if !measured {
+		return Msg("n/a")
+	}
+	return d.String()
+}
+
+// successStatus devolve os códigos contados como sucesso no relatório.
+// Relatórios sem o -success-status, inclusive os de versões anteriores,
+// usam a classe 2xx.
+func successStatus(report loadtest.Report) (string, loadtest.StatusSet) {
+	if report.SuccessStatus != "" {
+		if set, err := loadtest.ParseStatusSet(report.SuccessStatus); err == nil {
+			return report.SuccessStatus, set
+		}
+	}
+	set, _ := loadtest.ParseStatusSet(loadtest.DefaultSuccessStatus)
+	return loadtest.DefaultSuccessStatus, set
+}
+
+// errorKindDescription descreve o tipo de falha de transporte.
+func errorKindDescription(kind loadtest.ErrorKind) string {
+	switch kind {
+	case loadtest.ErrorDNS:
+		return Msg("DNS resolution failed")
+	case loadtest.ErrorConnRefused:
+		return Msg("Connection refused")
+	case loadtest.ErrorConnReset:
+		return Msg("Connection reset")
+	case loadtest.ErrorConnClosed:
+		return Msg("Connection closed")
+	case loadtest.ErrorConnectTimeout:
+		return Msg("Connect timeout")
+	case loadtest.ErrorTLS:
+		return Msg("TLS or certificate error")
+	case loadtest.ErrorTLSTimeout:
+		return Msg("TLS handshake timeout")
+	case loadtest.ErrorResponseTimeout:
+		return Msg("No response before the timeout")
+	case loadtest.ErrorTimeout:
+		return Msg("Timeout")
+	case loadtest.ErrorTooManyRedirects:
+		return Msg("Too many redirects")
+	default:
+		return Msg("Other error")
+	}
+}
+
+func printPhaseBreakdown(phases loadtest.PhaseBreakdown) {
+	printf("🔍 Latency Breakdown\n")
+	fmt.Printf("----------------------------------------\n")
+	for _, phase := range phases.List() {
+		if phase.Stats.Count == 0 {
+			fmt.Printf("%-20s n/a\n", Msg(phase.Name)+":")
+			continue
+		}
+		printf("%-20s avg %v | min %v | max %v (%d requests)\n",
+			Msg(phase.Name)+":", phase.Stats.Avg, phase.Stats.Min, phase.Stats.Max, phase.Stats.Count)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+}
+
+func printPerIP(report loadtest.Report) {
+	if len(report.PerIP) < 2 {
+		return
+	}
+
+	ips := make([]string, 0, len(report.PerIP))
+	for ip := range report.PerIP {
+		ips = append(ips, ip)
+	}
+	sort.Strings(ips)
+
+	printf("🌐 Per-IP Breakdown\n")
+	fmt.Printf("----------------------------------------\n")
+	for _, ip := range ips {
+		stats := report.PerIP[ip]
+		errorRate := float64(stats.Errors) / float64(stats.Requests) * 100
+		avg, maxLatency := Latency(stats.Latency.Avg, stats.Latency.Count > 0), Latency(stats.Latency.Max, stats.Latency.Count > 0)
+		printf("%-40s %d requests | avg %s | max %s | errors %d (%.1f%%)\n",
+			ip, stats.Requests, avg, maxLatency, stats.Errors, errorRate)
+	}
+	fmt.Printf("----------------------------------------\n\n")
+}
+
+func printTrailers(stats loadtest.TrailerStats) {
+	if stats.Responses == 0 {
+		return
+	}
+
+	names := make([]string, 0, len(stats.Values))
+	for name := range stats.Values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	printf("📎 Response Trailers (%d responses)\n", stats.Responses)
+	fmt.Printf("----------------------------------------\n")
+	for _, name := range names {
+		values := make([]string, 0, len(stats.Values[name]))
+		for value := range stats.Values[name] {
+			values = append(values, value)
+		}
+		sort.Strings(values)
+		for _, value := range values {
+			printf("%s: %s -> %d responses\n", name, value, stats.Values[name][value])
+		}
+	}
+	fmt.Printf("----------------------------------------\n\n")
+}
+
+func PrintErrorDetails(report loadtest.Report) {
+	if report.Errors > 0 {
+		printf("\n❌ Error Details:\n")
+		fmt.Printf("----------------------------------------\n")
+		fmt.Printf("| %-16s | %-50s | %-8s | %-10s |\n",
+			Msg("Status"), Msg("Error Message"), Msg("Count"), Msg("Percent"))
+		fmt.Printf("----------------------------------------\n")
+
+		// Ordenar erros por contagem
+		type ErrEntry struct {
+			Type   string
+			Detail loadtest.ErrorDetail
+		}
+
+		entries := make([]ErrEntry, 0, len(report.ErrorDetails))
+		for errType, detail := range report.ErrorDetails {
+			entries = append(entries, ErrEntry{errType, detail})
+		}
+
+		sort.Slice(entries, func(i, j int) bool {
+			return entries[i].Detail.Count > entries[j].Detail.Count
+		})
+
+		for _, entry := range entries {
+			percent := float64(entry.Detail.Count) / float64(report.TotalRequests) * 100
+
+			shortErrType := entry.Type
+			if len(shortErrType) > 50 {
+				shortErrType = shortErrType[:47] + "..."
+			}
+
+			// Sem resposta, a coluna mostra o tipo da falha
+			status := fmt.Sprintf("%-16d", entry.Detail.Code)
+			if entry.Detail.Code == 0 && entry.Detail.Kind != loadtest.ErrorNone {
+				status = fmt.Sprintf("%-16s", entry.Detail.Kind)
+			}
+			fmt.Printf("| %s | %-50s | %-8d | %-9.1f%% |\n",
+				paint(statusColor(entry.Detail.Code), status),
+				shortErrType,
+				entry.Detail.Count,
+				percent)
+		}
+		fmt.Printf("----------------------------------------\n")
+	}
+}
+
+func statusCodeDescription(code int) string {
+	switch code {
+	case 0:
+		return Msg("Unidentified error")
+	case 200:
+		return "OK"
+	case 201:
+		return "Created"
+	case 204:
+		return "No Content"
+	case 206:
+		return "Partial Content"
+	case 304:
+		return "Not Modified"
+	case 310:
+		// 310, 452, 495, 522, 524 e 525 marcavam falhas de transporte nos
+		// relatórios gravados antes do ErrorKind
+		return "Too Many Redirects"
+	case 400:
+		return "Bad Request"
+	case 401:
+		return "Unauthorized"
+	case 403:
+		return "Forbidden"
+	case 404:
+		return "Not Found"
+	case 408:
+		return "Request Timeout"
+	case 413:
+		return "Payload Too Large"
+	case 416:
+		return "Range Not Satisfiable"
+	case 429:
+		return "Too Many Requests"
+	case 452:
+		return "DNS Resolution Error"
+	case 495:
+		return "SSL Certificate Error"
+	case 500:
+		return "Internal Server Error"
+	case 502:
+		return "Bad Gateway"
+	case 503:
+		return "Service Unavailable"
+	case 504:
+		return "Gateway Timeout"
+	case 522:
+		return "Connection Timed Out"
+	case 524:
+		return "Response Header Timeout"
+	case 525:
+		return "TLS Handshake Timeout"
+	default:
+		return sprintf("Status Code %d", code)
+	}
+}
@@ -0,0 +1,110 @@
+package report
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Thresholds são os limites que decidem se o teste passou.
+type Thresholds struct {
+	MaxP95       time.Duration
+	MaxErrorRate float64 // percentual
+	MinRPS       float64
+	// Percentual máximo por categoria do -error-rules
+	MaxCategoryRate map[string]float64
+}
+
+// Enabled informa se algum limite foi definido.
+func (t Thresholds) Enabled() bool {
+	return t.MaxP95 > 0 || t.MaxErrorRate > 0 || t.MinRPS > 0 || len(t.MaxCategoryRate) > 0
+}
+
+// Verdict é o resultado dos limites. Failures fica em inglês, como o JSON,
+// os webhooks e os logs; o terminal mostra a versão traduzida.
+type Verdict struct {
+	Passed   bool     `json:"passed"`
+	Failures []string `json:"failures"`
+
+	localized []string
+}
+
+// fail registra uma violação em inglês e no idioma do terminal.
+func (v *Verdict) fail(format string, args ...any) {
+	v.Failures = append(v.Failures, fmt.Sprintf(format, args...))
+	v.localized = append(v.localized, sprintf(format, args...))
+}
+
+// Include acrescenta as violações de outro veredito.
+func (v *Verdict) Include(other Verdict) {
+	localized := slices.Concat(v.Localized(), other.Localized())
+	v.Failures = append(v.Failures, other.Failures...)
+	v.localized = localized
+	v.Passed = len(v.Failures) == 0
+}
+
+// Localized devolve as violações no idioma do terminal. Um veredito lido
+// de um JSON só tem o texto em inglês.
+func (v Verdict) Localized() []string {
+	if len(v.localized) != len(v.Failures) {
+		return v.Failures
+	}
+	return v.localized
+}
+
+// ErrorRate é o percentual de requisições com erro.
+func ErrorRate(report loadtest.Report) float64 {
+	if report.TotalRequests == 0 {
+		return 0
+	}
+	return float64(report.Errors) / float64(report.TotalRequests) * 100
+}
+
+// CategoryRate é o percentual de requisições na categoria do -error-rules.
+func CategoryRate(report loadtest.Report, name string) float64 {
+	if report.TotalRequests == 0 {
+		return 0
+	}
+	return float64(report.ErrorCategories[name].Count) / float64(report.TotalRequests) * 100
+}
+
+// Evaluate confere o relatório contra os limites.
+func (t Thresholds) Evaluate(report loadtest.Report) Verdict {
+	verdict := Verdict{Failures: []string{}}
+	if t.MaxP95 > 0 {
+		if p95 := report.Percentile(95); p95 > t.MaxP95 {
+			verdict.fail("p95 %v is above %v", p95, t.MaxP95)
+		}
+	}
+	if t.MaxErrorRate > 0 {
+		if rate := ErrorRate(report); rate > t.MaxErrorRate {
+			verdict.fail("error rate %.2f%% is above %.2f%%", rate, t.MaxErrorRate)
+		}
+	}
+	if t.MinRPS > 0 && report.RPS < t.MinRPS {
+		verdict.fail("%.2f requests/s is below %.2f", report.RPS, t.MinRPS)
+	}
+	for _, name := range slices.Sorted(maps.Keys(t.MaxCategoryRate)) {
+		if rate := CategoryRate(report, name); rate > t.MaxCategoryRate[name] {
+			verdict.fail("%s errors %.2f%% are above %.2f%%", name, rate, t.MaxCategoryRate[name])
+		}
+	}
+	verdict.Passed = len(verdict.Failures) == 0
+	return verdict
+}
+
+func PrintVerdict(verdict Verdict) {
+	if verdict.Passed {
+		fmt.Println()
+		fmt.Println(paint(ansiBold+ansiGreen, Msg("✅ Thresholds passed")))
+		return
+	}
+	fmt.Println()
+	fmt.Println(paint(ansiBold+ansiRed, Msg("❌ Thresholds failed")))
+	for _, failure := range verdict.Localized() {
+		fmt.Println(paint(ansiRed, "  - "+failure))
+	}
+}
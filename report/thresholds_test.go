@@ -0,0 +1,44 @@
+package report
+
+import (
+	"testing"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// As violações ficam em inglês para o JSON, os webhooks e os logs, e só a
+// versão mostrada no terminal segue o -lang.
+func TestVerdictKeepsFailuresInEnglish(t *testing.T) {
+	defer func(lang string) { Lang = lang }(Lang)
+	Lang = "pt-BR"
+
+	latencies := loadtest.NewHistogram()
+	latencies.Add(500 * time.Millisecond)
+	result := loadtest.Report{
+		TotalRequests: 1,
+		StatusCodes:   map[int]int{200: 1},
+		ErrorKinds:    map[loadtest.ErrorKind]int{},
+		Latencies:     latencies,
+		RPS:           1,
+	}
+	verdict := Thresholds{MaxP95: 100 * time.Millisecond}.Evaluate(result)
+	verdict.Include(Tolerances{RPS: 10}.Regressions([]Delta{{Metric: "RPS", Base: 100, Current: 1, HigherIsBetter: true}}))
+
+	if verdict.Passed || len(verdict.Failures) != 2 {
+		t.Fatalf("verdict = %+v, want 2 failures", verdict)
+	}
+	english := []string{"p95 ", "RPS dropped "}
+	localized := []string{"p95 ", "RPS caiu "}
+	for i, failure := range verdict.Failures {
+		if len(failure) < len(english[i]) || failure[:len(english[i])] != english[i] {
+			t.Errorf("Failures[%d] = %q, want it in English", i, failure)
+		}
+		if got := verdict.Localized()[i]; len(got) < len(localized[i]) || got[:len(localized[i])] != localized[i] {
+			t.Errorf("Localized()[%d] = %q, want it in pt-BR", i, got)
+		}
+	}
+	if verdict.Failures[0] == verdict.Localized()[0] {
+		t.Errorf("p95 failure was not translated: %q", verdict.Failures[0])
+	}
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// reportCommand implementa o subcomando `report`: reimprime um relatório
+// JSON salvo em outro formato.
+func reportCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "report [flags] report.json",
+		Short:   "Print a saved JSON report in another format",
+		GroupID: "reports",
+		Args:    cobra.ExactArgs(1),
+	}
+	formatFlag := cmd.Flags().String("format", "plain", "Output format (plain, json, csv)")
+	noColorFlag := cmd.Flags().Bool("no-color", false, "Disable ANSI colors (also disabled by the NO_COLOR environment variable)")
+	addLangFlag(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkReportFormat(*formatFlag); err != nil {
+			return err
+		}
+		report.Color = colorEnabled(*noColorFlag)
+		result, err := report.Load(args[0])
+		if err != nil {
+			return fmt.Errorf("loading report: %w", err)
+		}
+		writeReport(os.Stdout, *formatFlag, result)
+		return nil
+	}
+	return cmd
+}
+
+// compareCommand implementa o subcomando `compare`: mostra a variação das
+// métricas principais entre um relatório base e um atual.
+func compareCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "compare [flags] baseline.json current.json",
+		Short:   "Show how the metrics changed between two JSON reports",
+		GroupID: "reports",
+		Args:    cobra.ExactArgs(2),
+	}
+	noColorFlag := cmd.Flags().Bool("no-color", false, "Disable ANSI colors (also disabled by the NO_COLOR environment variable)")
+	addLangFlag(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		report.Color = colorEnabled(*noColorFlag)
+		base, err := report.Load(args[0])
+		if err != nil {
+			return fmt.Errorf("loading report: %w", err)
+		}
+		current, err := report.Load(args[1])
+		if err != nil {
+			return fmt.Errorf("loading report: %w", err)
+		}
+		report.PrintComparison(report.Compare(base, current))
+		return nil
+	}
+	return cmd
+}
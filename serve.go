@@ -0,0 +1,283 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// Estados de um teste submetido ao modo serve.
+const (
+	testRunning   = "running"
+	testCompleted = "completed"
+	testCancelled = "cancelled"
+	testFailed    = "failed"
+)
+
+// serverTest é um teste submetido pela API. Os campos exportados formam a
+// resposta de status.
+type serverTest struct {
+	ID          string                 `json:"id"`
+	Args        []string               `json:"args"`
+	Status      string                 `json:"status"`
+	Error       string                 `json:"error,omitempty"`
+	Created     time.Time              `json:"created"`
+	Finished    *time.Time             `json:"finished,omitempty"`
+	Progress    *loadtest.LiveSnapshot `json:"progress,omitempty"`
+	Concurrency int                    `json:"concurrency,omitempty"`
+	report      *loadtest.Report
+	live        *loadtest.LiveStats
+	stop        *loadtest.StopSignal
+	control     *loadtest.ConcurrencyControl
+}
+
+type testServer struct {
+	mu    sync.Mutex
+	tests map[string]*serverTest
+}
+
+// serveCommand implementa o subcomando `serve`: uma API REST que recebe testes
+// com os mesmos argumentos da linha de comando e os executa em background.
+func serveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "serve [flags]",
+		Short:   "Run tests submitted through a REST API",
+		GroupID: "test",
+		Args:    cobra.NoArgs,
+	}
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	listenFlag := fs.String("listen", "127.0.0.1:8090", "Address the REST API listens on")
+	buildLogger := logFlags(fs)
+	cmd.Flags().AddGoFlagSet(fs)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		logger, err := buildLogger(false, cmd.Flags().Changed)
+		if err != nil {
+			fmt.Fprintln(os.Stderr, err)
+			os.Exit(2)
+		}
+		slog.SetDefault(logger)
+
+		s := &testServer{tests: make(map[string]*serverTest)}
+		mux := http.NewServeMux()
+		mux.HandleFunc("POST /tests", s.handleSubmit)
+		mux.HandleFunc("GET /tests", s.handleList)
+		mux.HandleFunc("GET /tests/{id}", s.handleStatus)
+		mux.HandleFunc("GET /tests/{id}/report", s.handleReport)
+		mux.HandleFunc("DELETE /tests/{id}", s.handleCancel)
+		mux.HandleFunc("PUT /tests/{id}/concurrency", s.handleConcurrency)
+
+		slog.Info("load generator API listening", "url", "http://"+*listenFlag)
+		if err := http.ListenAndServe(*listenFlag, mux); err != nil {
+			slog.Error("serving the API", "error", err)
+			os.Exit(1)
+		}
+	}
+	return cmd
+}
+
+func (s *testServer) handleSubmit(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		Args []string `json:"args"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+
+	var usage bytes.Buffer
+	config, opts, err := parseConfig(req.Args, &usage)
+	switch {
+	case errors.Is(err, errInvalidFlags) || errors.Is(err, flag.ErrHelp):
+		writeJSONError(w, http.StatusBadRequest, errors.New(strings.SplitN(usage.String(), "\n", 2)[0]))
+		return
+	case err != nil:
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	case opts.UI != "" || opts.Watch != "" || opts.Checkpoint != "" || len(config.BodySizes) > 0:
+		writeJSONError(w, http.StatusBadRequest, errors.New("-ui, -watch, -checkpoint, -resume and -body-sizes are not supported in serve mode"))
+		return
+	}
+
+	test := &serverTest{
+		ID:      loadtest.UUIDv4(),
+		Args:    req.Args,
+		Status:  testRunning,
+		Created: time.Now(),
+		live:    loadtest.NewLiveStats(config.Requests),
+		stop:    loadtest.NewStopSignal(),
+		control: loadtest.NewConcurrencyControl(),
+	}
+	config.Live = test.live
+	config.Stop = test.stop
+	config.Control = test.control
+	config.Quiet = true
+	if opts.RequestLog != "" {
+		logger, err := loadtest.NewRequestLogger(opts.RequestLog)
+		if err != nil {
+			writeJSONError(w, http.StatusBadRequest, err)
+			return
+		}
+		config.RequestLog = logger
+	}
+
+	s.mu.Lock()
+	s.tests[test.ID] = test
+	s.mu.Unlock()
+	slog.Info("test submitted", "id", test.ID, "url", config.URL, "requests", config.Requests)
+	go s.run(test, config)
+
+	w.Header().Set("Location", "/tests/"+test.ID)
+	writeJSON(w, http.StatusCreated, s.view(test))
+}
+
+func (s *testServer) run(test *serverTest, config loadtest.Config) {
+	result, err := loadtest.Run(context.Background(), config)
+	if config.RequestLog != nil {
+		if closeErr := config.RequestLog.Close(); err == nil {
+			err = closeErr
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	test.Finished = &now
+	switch {
+	case err != nil:
+		test.Status = testFailed
+		test.Error = err.Error()
+		slog.Error("test failed", "id", test.ID, "error", err)
+	case result.Stopped:
+		test.Status = testCancelled
+		test.report = result
+	default:
+		test.Status = testCompleted
+		test.report = result
+	}
+	if err == nil {
+		slog.Info("test finished", "id", test.ID, "status", test.Status, "requests", result.TotalRequests, "rps", result.RPS)
+	}
+}
+
+// view copia o teste com o progresso atual, sob o lock do servidor.
+func (s *testServer) view(test *serverTest) serverTest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	v := *test
+	snapshot := test.live.Snapshot()
+	v.Progress = &snapshot
+	v.Concurrency = test.control.Current()
+	return v
+}
+
+func (s *testServer) lookup(w http.ResponseWriter, r *http.Request) *serverTest {
+	s.mu.Lock()
+	test, ok := s.tests[r.PathValue("id")]
+	s.mu.Unlock()
+	if !ok {
+		writeJSONError(w, http.StatusNotFound, fmt.Errorf("test %s not found", r.PathValue("id")))
+		return nil
+	}
+	return test
+}
+
+func (s *testServer) handleList(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	tests := make([]*serverTest, 0, len(s.tests))
+	for _, test := range s.tests {
+		tests = append(tests, test)
+	}
+	s.mu.Unlock()
+	sort.Slice(tests, func(i, j int) bool { return tests[i].Created.Before(tests[j].Created) })
+
+	views := make([]serverTest, len(tests))
+	for i, test := range tests {
+		views[i] = s.view(test)
+	}
+	writeJSON(w, http.StatusOK, views)
+}
+
+func (s *testServer) handleStatus(w http.ResponseWriter, r *http.Request) {
+	if test := s.lookup(w, r); test != nil {
+		writeJSON(w, http.StatusOK, s.view(test))
+	}
+}
+
+func (s *testServer) handleReport(w http.ResponseWriter, r *http.Request) {
+	test := s.lookup(w, r)
+	if test == nil {
+		return
+	}
+	s.mu.Lock()
+	result, status := test.report, test.Status
+	s.mu.Unlock()
+	if result == nil {
+		writeJSONError(w, http.StatusConflict, fmt.Errorf("test is %s, no report available", status))
+		return
+	}
+	writeJSON(w, http.StatusOK, result)
+}
+
+func (s *testServer) handleCancel(w http.ResponseWriter, r *http.Request) {
+	if test := s.lookup(w, r); test != nil {
+		test.stop.Stop()
+		writeJSON(w, http.StatusAccepted, s.view(test))
+	}
+}
+
+// handleConcurrency muda a concorrência do teste em andamento, com o
+// corpo {"concurrency": n}.
+func (s *testServer) handleConcurrency(w http.ResponseWriter, r *http.Request) {
+	test := s.lookup(w, r)
+	if test == nil {
+		return
+	}
+	var req struct {
+		Concurrency int `json:"concurrency"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSONError(w, http.StatusBadRequest, fmt.Errorf("invalid request body: %w", err))
+		return
+	}
+	s.mu.Lock()
+	status := test.Status
+	s.mu.Unlock()
+	if status != testRunning {
+		writeJSONError(w, http.StatusConflict, fmt.Errorf("test is %s", status))
+		return
+	}
+	if err := test.control.Set(req.Concurrency); err != nil {
+		writeJSONError(w, http.StatusBadRequest, err)
+		return
+	}
+	writeJSON(w, http.StatusAccepted, s.view(test))
+}
+
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(data)
+}
+
+func writeJSONError(w http.ResponseWriter, status int, err error) {
+	writeJSON(w, status, map[string]string{"error": err.Error()})
+}
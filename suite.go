@@ -0,0 +1,338 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// suiteFile é o arquivo do `suite`: testes nomeados, cada um com os valores
+// das flags do `run` no formato do -config, e valores comuns a todos.
+type suiteFile struct {
+	Parallel bool             `json:"parallel"`
+	Defaults map[string]any   `json:"defaults"`
+	Tests    []map[string]any `json:"tests"`
+}
+
+// suiteUnsupported são as flags do `run` que não fazem sentido dentro de
+// uma suíte, seja por controlarem a saída ou por dependerem de um teste só.
+var suiteUnsupported = []string{
+	"format", "dry-run", "debug-request", "workers", "ui", "watch", "checkpoint", "resume",
+	"body-sizes", "history", "upload", "webhook", "slack-webhook", "teams-webhook",
+}
+
+// suiteTest é um teste da suíte e, depois de executado, o seu resultado.
+// Os campos exportados formam o relatório JSON.
+type suiteTest struct {
+	Name     string
+	Passed   bool
+	Skipped  bool             `json:",omitempty"`
+	Error    string           `json:",omitempty"`
+	Failures []string         `json:",omitempty"`
+	Report   *loadtest.Report `json:",omitempty"`
+	verdict  *report.Verdict
+	config   loadtest.Config
+	opts     cliOptions
+	exit     int
+}
+
+// suiteOptions são as flags do `suite`. Parallel é nil quando -parallel não
+// foi passado e vale o do arquivo.
+type suiteOptions struct {
+	Format   string
+	Details  bool
+	Parallel *bool
+}
+
+type suiteReport struct {
+	Passed    bool
+	Parallel  bool
+	TotalTime time.Duration
+	Tests     []*suiteTest
+}
+
+// suiteCommand implementa o subcomando `suite`: executa os testes de um
+// arquivo em sequência ou em paralelo e resume o resultado de cada um.
+func suiteCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "suite [flags] suite.json",
+		Short:   "Run the named tests of a suite file and report each one",
+		GroupID: "test",
+		Args:    cobra.ExactArgs(1),
+	}
+	fs := flag.NewFlagSet("suite", flag.ContinueOnError)
+	parallelFlag := fs.Bool("parallel", false, "Run the tests at the same time instead of one after the other (overrides \"parallel\" in the file)")
+	formatFlag := fs.String("format", "plain", "Output format of the suite report (plain, json)")
+	detailsFlag := fs.Bool("details", false, "Print the full report of each test before the suite summary")
+	buildLogger := logFlags(fs)
+	cmd.Flags().AddGoFlagSet(fs)
+	addLangFlag(cmd)
+	cmd.Run = func(cmd *cobra.Command, args []string) {
+		logger, err := buildLogger(false, cmd.Flags().Changed)
+		if err != nil {
+			slog.Error("invalid configuration", "error", err)
+			os.Exit(exitConfig)
+		}
+		slog.SetDefault(logger)
+		opts := suiteOptions{Format: *formatFlag, Details: *detailsFlag}
+		if cmd.Flags().Changed("parallel") {
+			opts.Parallel = parallelFlag
+		}
+		os.Exit(runSuite(args[0], opts))
+	}
+	return cmd
+}
+
+// runSuite executa a suíte e devolve o código de saída, com a mesma
+// política do `run` aplicada ao pior teste.
+func runSuite(path string, opts suiteOptions) int {
+	if opts.Format != "plain" && opts.Format != "json" {
+		slog.Error("invalid configuration", "error", fmt.Errorf("invalid -format %q (use plain or json)", opts.Format))
+		return exitConfig
+	}
+	tests, file, err := loadSuite(path)
+	if err != nil {
+		slog.Error("invalid configuration", "error", err)
+		return exitConfig
+	}
+
+	// No json o stdout recebe só o relatório, como no `run`
+	reportOut := os.Stdout
+	if opts.Format == "json" {
+		os.Stdout = os.Stderr
+	} else {
+		report.Color = colorEnabled(false)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	go func() {
+		<-ctx.Done()
+		stop()
+	}()
+	suite := suiteReport{Parallel: file.Parallel, Tests: tests}
+	if opts.Parallel != nil {
+		suite.Parallel = *opts.Parallel
+	}
+	start := time.Now()
+	if suite.Parallel {
+		// As barras de progresso se misturariam; o andamento fica no log
+		var wg sync.WaitGroup
+		for _, test := range tests {
+			test.config.Quiet = true
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				slog.Info("suite test started", "test", test.Name)
+				test.run(ctx)
+				slog.Info("suite test finished", "test", test.Name, "passed", test.Passed)
+			}()
+		}
+		wg.Wait()
+	} else {
+		for i, test := range tests {
+			if ctx.Err() != nil {
+				test.Skipped, test.exit = true, exitAborted
+				continue
+			}
+			if !test.config.Quiet {
+				fmt.Printf(report.Msg("\n▶ Test %d/%d: %s\n"), i+1, len(tests), test.Name)
+			}
+			test.run(ctx)
+		}
+	}
+	stop()
+	suite.TotalTime = time.Since(start)
+
+	codes := make([]int, len(tests))
+	for i, t := range tests {
+		codes[i] = t.exit
+	}
+	exit := worstExit(codes)
+	suite.Passed = exit == exitOK
+
+	if opts.Format == "json" {
+		data, _ := json.MarshalIndent(suite, "", " ")
+		fmt.Fprintln(reportOut, string(data))
+		return exit
+	}
+	if opts.Details {
+		for _, test := range tests {
+			if test.Report != nil {
+				fmt.Printf(report.Msg("\n▶ %s\n"), test.Name)
+				writeReport(reportOut, "plain", *test.Report)
+			}
+		}
+	}
+	printSuite(suite)
+	return exit
+}
+
+// loadSuite lê o arquivo e monta a configuração de cada teste, validando
+// todos antes de o primeiro começar.
+func loadSuite(path string) ([]*suiteTest, suiteFile, error) {
+	var file suiteFile
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, file, err
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&file); err != nil {
+		return nil, file, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if len(file.Tests) == 0 {
+		return nil, file, fmt.Errorf("%s: no tests", path)
+	}
+
+	tests := make([]*suiteTest, 0, len(file.Tests))
+	names := make(map[string]bool)
+	for i, values := range file.Tests {
+		test, err := newSuiteTest(fmt.Sprintf("%s: test %d", path, i+1), values, file.Defaults)
+		if err != nil {
+			return nil, file, err
+		}
+		if names[test.Name] {
+			return nil, file, fmt.Errorf("%s: duplicate test name %q, give each test a \"name\"", path, test.Name)
+		}
+		names[test.Name] = true
+		tests = append(tests, test)
+	}
+	return tests, file, nil
+}
+
+// newSuiteTest monta um teste com as flags do `run`. Os valores do teste
+// têm precedência sobre os defaults, que têm precedência sobre o -profile.
+func newSuiteTest(source string, values, defaults map[string]any) (*suiteTest, error) {
+	for _, name := range suiteUnsupported {
+		_, inTest := values[name]
+		_, inDefaults := defaults[name]
+		if inTest || inDefaults {
+			return nil, fmt.Errorf("%s: -%s is not supported in a suite", source, name)
+		}
+	}
+	fs := flag.NewFlagSet("suite", flag.ContinueOnError)
+	build := runFlags(fs, false)
+	fromTest, err := applyConfigValues(fs, source, values, func(string) bool { return false })
+	if err != nil {
+		return nil, err
+	}
+	fromDefaults, err := applyConfigValues(fs, source+" defaults", defaults, func(name string) bool { return fromTest[name] })
+	if err != nil {
+		return nil, err
+	}
+	config, opts, err := build(nil, func(name string) bool { return fromTest[name] || fromDefaults[name] })
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", source, err)
+	}
+	return &suiteTest{Name: opts.Name, config: config, opts: opts}, nil
+}
+
+// run executa o teste e julga o resultado pelos limites dele.
+func (t *suiteTest) run(ctx context.Context) {
+	if t.opts.RequestLog != "" {
+		logger, err := loadtest.NewRequestLogger(t.opts.RequestLog)
+		if err != nil {
+			t.Error, t.exit = err.Error(), exitConfig
+			return
+		}
+		defer func() {
+			if err := logger.Close(); err != nil {
+				slog.Error("closing the request log", "test", t.Name, "error", err)
+			}
+		}()
+		t.config.RequestLog = logger
+	}
+
+	result, err := loadtest.Run(ctx, t.config)
+	if err != nil {
+		slog.Error("test failed", "test", t.Name, "error", err)
+		t.Error, t.exit = err.Error(), exitConfig
+		return
+	}
+	t.Report = result
+	var verdict *report.Verdict
+	if t.opts.Thresholds.Enabled() || t.opts.Baseline != nil {
+		v, _ := judge(t.opts, *result)
+		t.Failures = v.Failures
+		verdict = &v
+		t.verdict = verdict
+	}
+	t.exit = exitCode(result, verdict, nil)
+	t.Passed = t.exit == exitOK
+}
+
+// outcome resume em poucas palavras o resultado do teste.
+func (t *suiteTest) outcome() string {
+	switch {
+	case t.Skipped:
+		return report.Msg("⏭️ skipped")
+	case t.Error != "":
+		return report.Msg("💥 error")
+	case t.exit == exitAborted:
+		return report.Msg("⛔ aborted")
+	case t.exit == exitAllFailed, t.Report != nil && t.Report.TotalRequests > 0 && t.Report.Errors == t.Report.TotalRequests:
+		return report.Msg("❌ all requests failed")
+	case t.exit == exitThresholds:
+		return report.Msg("❌ thresholds failed")
+	}
+	return report.Msg("✅ passed")
+}
+
+func printSuite(suite suiteReport) {
+	mode := report.Msg("sequential")
+	if suite.Parallel {
+		mode = report.Msg("parallel")
+	}
+	fmt.Printf(report.Msg("\n📋 Suite Results (%d tests, %s, %.2f seconds)\n"), len(suite.Tests), mode, suite.TotalTime.Seconds())
+	fmt.Println("----------------------------------------")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, report.Msg("TEST\tREQUESTS\tERRORS\tRPS\tP95\tRESULT"))
+	for _, t := range suite.Tests {
+		if t.Report == nil {
+			fmt.Fprintf(w, "%s\t-\t-\t-\t-\t%s\n", t.Name, t.outcome())
+			continue
+		}
+		r := t.Report
+		fmt.Fprintf(w, "%s\t%d\t%.2f%%\t%.2f\t%s\t%s\n", t.Name, r.TotalRequests, report.ErrorRate(*r), r.RPS,
+			report.Latency(r.Percentile(95).Round(time.Microsecond), r.HasLatency()), t.outcome())
+	}
+	w.Flush()
+
+	fmt.Println("----------------------------------------")
+	failed := 0
+	for _, t := range suite.Tests {
+		if t.Passed {
+			continue
+		}
+		failed++
+		switch {
+		case t.Error != "":
+			fmt.Printf("%s: %s\n", t.Name, t.Error)
+		case len(t.Failures) > 0:
+			fmt.Printf("%s:\n", t.Name)
+			for _, failure := range t.verdict.Localized() {
+				fmt.Println("  - " + failure)
+			}
+		}
+	}
+	if suite.Passed {
+		fmt.Printf(report.Msg("✅ Suite passed: %d of %d tests\n"), len(suite.Tests), len(suite.Tests))
+		return
+	}
+	fmt.Printf(report.Msg("❌ Suite failed: %d of %d tests did not pass\n"), failed, len(suite.Tests))
+}
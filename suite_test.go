@@ -0,0 +1,88 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadSuite(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		want    []string // nomes dos testes carregados
+		wantErr string
+	}{
+		{
+			name: "defaults and names",
+			file: `{"defaults": {"requests": 10}, "tests": [
+				{"url": "http://localhost/a"},
+				{"name": "search", "url": "http://localhost/b", "requests": 5}]}`,
+			want: []string{"localhost", "search"},
+		},
+		{name: "no tests", file: `{"tests": []}`, wantErr: "no tests"},
+		{name: "unknown field", file: `{"test": []}`, wantErr: "unknown field"},
+		{
+			name:    "duplicate names",
+			file:    `{"tests": [{"url": "http://localhost/a", "requests": 1}, {"url": "http://localhost/b", "requests": 1}]}`,
+			wantErr: `duplicate test name "localhost"`,
+		},
+		{
+			name:    "unsupported flag in a test",
+			file:    `{"tests": [{"url": "http://localhost", "requests": 1, "workers": "http://w1"}]}`,
+			wantErr: "test 1: -workers is not supported in a suite",
+		},
+		{
+			name:    "unsupported flag in the defaults",
+			file:    `{"defaults": {"format": "json"}, "tests": [{"url": "http://localhost", "requests": 1}]}`,
+			wantErr: "-format is not supported in a suite",
+		},
+		{
+			name:    "invalid test",
+			file:    `{"tests": [{"name": "ok", "url": "http://localhost", "requests": 1}, {"name": "bad", "url": "http://localhost"}]}`,
+			wantErr: "test 2:",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "suite.json")
+			if err := os.WriteFile(path, []byte(tt.file), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			loaded, _, err := loadSuite(path)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, test := range loaded {
+				names = append(names, test.Name)
+			}
+			if strings.Join(names, ",") != strings.Join(tt.want, ",") {
+				t.Errorf("tests = %q, want %q", names, tt.want)
+			}
+		})
+	}
+}
+
+// O valor do teste tem precedência sobre o dos defaults.
+func TestLoadSuiteDefaultsPrecedence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "suite.json")
+	file := `{"defaults": {"requests": 10, "concurrency": 3}, "tests": [{"url": "http://localhost", "requests": 5}]}`
+	if err := os.WriteFile(path, []byte(file), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	loaded, _, err := loadSuite(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if config := loaded[0].config; config.Requests != 5 || config.Concurrency != 3 {
+		t.Errorf("requests %d, concurrency %d; want 5 and 3", config.Requests, config.Concurrency)
+	}
+}
@@ -0,0 +1,232 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"slices"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// Execuções anteriores necessárias antes de sinalizar uma mudança incomum
+const trendMinHistory = 3
+
+// trendPoint é uma execução na série do `trend`, com as métricas que
+// fugiram do padrão das execuções anteriores.
+type trendPoint struct {
+	Run     historyRun `json:"run"`
+	Unusual []string   `json:"unusual"` // em inglês, como o JSON e o CSV
+
+	localized []string // Unusual no idioma do terminal
+}
+
+// flag sinaliza uma métrica incomum, em inglês e no idioma do terminal. O
+// format começa pelo nome da métrica.
+func (p *trendPoint) flag(metric, format string, args ...any) {
+	p.Unusual = append(p.Unusual, fmt.Sprintf(format, append([]any{metric}, args...)...))
+	p.localized = append(p.localized, fmt.Sprintf(report.Msg(format), append([]any{report.Msg(metric)}, args...)...))
+}
+
+type trendMetric struct {
+	name    string
+	value   func(historyRun) float64
+	latency bool // só vale para execuções com latências medidas
+}
+
+var trendMetrics = []trendMetric{
+	{"RPS", func(r historyRun) float64 { return r.RPS }, false},
+	{"P95", func(r historyRun) float64 { return toMillis(r.P95) }, true},
+	{"P99", func(r historyRun) float64 { return toMillis(r.P99) }, true},
+	{"Error rate", func(r historyRun) float64 { return r.ErrorRate }, false},
+}
+
+// buildTrend compara cada execução com as anteriores da série e sinaliza
+// métricas a mais de zLimit desvios padrão da média delas. Variações abaixo
+// de minChange (percentual) são ignoradas, pois séries muito estáveis têm
+// desvio tão pequeno que qualquer ruído viraria um alerta. Execuções sem
+// latências medidas ficam fora da série dos percentis.
+func buildTrend(runs []historyRun, zLimit, minChange float64) []trendPoint {
+	points := make([]trendPoint, len(runs))
+	for i, run := range runs {
+		points[i] = trendPoint{Run: run, Unusual: []string{}}
+		for _, metric := range trendMetrics {
+			previous := runs[:i]
+			if metric.latency {
+				if !run.hasLatency() {
+					continue
+				}
+				previous = slices.DeleteFunc(slices.Clone(previous), func(r historyRun) bool { return !r.hasLatency() })
+			}
+			if len(previous) < trendMinHistory {
+				continue
+			}
+			mean, stddev := meanStdDev(previous, metric.value)
+			value := metric.value(run)
+			if math.Abs(percentChange(mean, value)) < minChange && mean != 0 {
+				continue
+			}
+			if stddev == 0 {
+				// Série constante: qualquer mudança é incomum
+				if value != mean {
+					points[i].flag(metric.name, "%s changed from a constant %.2f", mean)
+				}
+				continue
+			}
+			if z := (value - mean) / stddev; math.Abs(z) > zLimit {
+				arrow := "↑"
+				if z < 0 {
+					arrow = "↓"
+				}
+				points[i].flag(metric.name, "%s %s (z=%.1f)", arrow, z)
+			}
+		}
+	}
+	return points
+}
+
+func meanStdDev(runs []historyRun, value func(historyRun) float64) (float64, float64) {
+	var sum float64
+	for _, run := range runs {
+		sum += value(run)
+	}
+	mean := sum / float64(len(runs))
+	var variance float64
+	for _, run := range runs {
+		diff := value(run) - mean
+		variance += diff * diff
+	}
+	return mean, math.Sqrt(variance / float64(len(runs)))
+}
+
+// trendCommand implementa o subcomando `trend`: a evolução de RPS e percentis
+// das últimas execuções de um teste gravadas com -history.
+func trendCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:     "trend [flags]",
+		Short:   "Show how RPS and percentiles of a test evolved across recorded runs",
+		GroupID: "reports",
+		Args:    cobra.NoArgs,
+	}
+	fs := cmd.Flags()
+	dbFlag := fs.String("db", defaultHistoryPath, "History database written by -history")
+	nameFlag := fs.String("name", "", "Test whose runs are analysed (default: the test of the latest run)")
+	limitFlag := fs.Int("limit", 20, "Number of most recent runs analysed")
+	zFlag := fs.Float64("z", 2, "Flag values more than this many standard deviations away from the previous runs")
+	minChangeFlag := fs.Float64("min-change", 5, "Ignore changes smaller than this percentage of the previous runs' average")
+	formatFlag := fs.String("format", "plain", "Output format (plain, json, csv)")
+	addLangFlag(cmd)
+	cmd.RunE = func(cmd *cobra.Command, args []string) error {
+		if err := checkReportFormat(*formatFlag); err != nil {
+			return err
+		}
+		db, err := openHistory(*dbFlag)
+		if err != nil {
+			return err
+		}
+		defer db.Close()
+
+		name := *nameFlag
+		if name == "" {
+			latest, err := listHistoryRuns(db, "", 1)
+			if err != nil {
+				return fmt.Errorf("listing runs: %w", err)
+			}
+			if len(latest) == 0 {
+				fmt.Println(report.Msg("No runs recorded yet"))
+				return nil
+			}
+			name = latest[0].Name
+		}
+		runs, err := listHistoryRuns(db, name, *limitFlag)
+		if err != nil {
+			return fmt.Errorf("listing runs: %w", err)
+		}
+		if len(runs) == 0 {
+			fmt.Printf(report.Msg("No runs recorded for %q\n"), name)
+			return nil
+		}
+		// A série vai da execução mais antiga para a mais recente
+		for i, j := 0, len(runs)-1; i < j; i, j = i+1, j-1 {
+			runs[i], runs[j] = runs[j], runs[i]
+		}
+		points := buildTrend(runs, *zFlag, *minChangeFlag)
+
+		switch *formatFlag {
+		case "json":
+			data, _ := json.MarshalIndent(points, "", " ")
+			fmt.Println(string(data))
+		case "csv":
+			w := csv.NewWriter(os.Stdout)
+			w.Write([]string{"ID", "Started", "Requests", "RPS", "P50 (ms)", "P90 (ms)", "P95 (ms)", "P99 (ms)", "Error Rate (%)", "Unusual"})
+			for _, p := range points {
+				r := p.Run
+				w.Write([]string{strconv.FormatInt(r.ID, 10), r.StartedAt.Format(time.RFC3339), strconv.Itoa(r.Requests),
+					fmt.Sprintf("%.2f", r.RPS), trendMillis(r, r.P50), trendMillis(r, r.P90),
+					trendMillis(r, r.P95), trendMillis(r, r.P99), fmt.Sprintf("%.2f", r.ErrorRate),
+					strings.Join(p.Unusual, "; ")})
+			}
+			w.Flush()
+		default:
+			printTrend(name, points)
+		}
+		return nil
+	}
+	return cmd
+}
+
+func printTrend(name string, points []trendPoint) {
+	fmt.Printf(report.Msg("\n📈 Trend for %s (%d runs)\n"), name, len(points))
+	fmt.Println("----------------------------------------")
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, report.Msg("ID\tSTARTED\tRPS\tP50\tP95\tP99\tERRORS\tUNUSUAL"))
+	for _, p := range points {
+		r := p.Run
+		measured := r.hasLatency()
+		fmt.Fprintf(w, "%d\t%s\t%.2f\t%s\t%s\t%s\t%.2f%%\t%s\n", r.ID,
+			r.StartedAt.Local().Format("2006-01-02 15:04"), r.RPS, report.Latency(r.P50.Round(time.Microsecond), measured),
+			report.Latency(r.P95.Round(time.Microsecond), measured), report.Latency(r.P99.Round(time.Microsecond), measured),
+			r.ErrorRate, strings.Join(p.localized, ", "))
+	}
+	w.Flush()
+
+	if len(points) > 1 {
+		first, last := points[0].Run, points[len(points)-1].Run
+		fmt.Println("----------------------------------------")
+		fmt.Printf(report.Msg("RPS: %.2f -> %.2f (%+.1f%%)\n"), first.RPS, last.RPS, percentChange(first.RPS, last.RPS))
+		if first.hasLatency() && last.hasLatency() {
+			fmt.Printf(report.Msg("P95: %v -> %v (%+.1f%%)\n"), first.P95.Round(time.Microsecond), last.P95.Round(time.Microsecond),
+				percentChange(toMillis(first.P95), toMillis(last.P95)))
+		} else {
+			fmt.Printf(report.Msg("P95: %s -> %s\n"), report.Latency(first.P95.Round(time.Microsecond), first.hasLatency()),
+				report.Latency(last.P95.Round(time.Microsecond), last.hasLatency()))
+		}
+	}
+	if len(points) <= trendMinHistory {
+		fmt.Printf(report.Msg("ℹ️  At least %d earlier runs are needed before changes are flagged\n"), trendMinHistory)
+	}
+}
+
+// trendMillis é a célula do CSV para uma latência, vazia quando a execução
+// não mediu nenhuma.
+func trendMillis(r historyRun, d time.Duration) string {
+	if !r.hasLatency() {
+		return ""
+	}
+	return fmt.Sprintf("%.2f", toMillis(d))
+}
+
+func percentChange(from, to float64) float64 {
+	if from == 0 {
+		return 0
+	}
+	return (to - from) / from * 100
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+func TestBuildTrend(t *testing.T) {
+	run := func(rps float64, p95 time.Duration) historyRun {
+		return historyRun{RPS: rps, P95: p95, P99: p95, Max: p95}
+	}
+	failed := historyRun{RPS: 100, ErrorRate: 0}
+	tests := []struct {
+		name string
+		runs []historyRun
+		want []string // métricas sinalizadas na última execução
+	}{
+		{"too few runs", []historyRun{run(100, 10*time.Millisecond), run(100, 10*time.Millisecond), run(500, time.Second)}, nil},
+		{"stable", []historyRun{run(100, 10*time.Millisecond), run(102, 11*time.Millisecond), run(98, 9*time.Millisecond), run(101, 10*time.Millisecond)}, nil},
+		{"latency spike", []historyRun{run(100, 10*time.Millisecond), run(102, 11*time.Millisecond), run(98, 9*time.Millisecond), run(100, 50*time.Millisecond)},
+			[]string{"P95 ↑ (z=49.0)", "P99 ↑ (z=49.0)"}},
+		{"constant series", []historyRun{run(100, 10*time.Millisecond), run(100, 10*time.Millisecond), run(100, 10*time.Millisecond), run(50, 10*time.Millisecond)},
+			[]string{"RPS changed from a constant 100.00"}},
+		{"run without latency", []historyRun{run(100, 10*time.Millisecond), run(102, 11*time.Millisecond), run(98, 9*time.Millisecond), failed}, nil},
+		{"runs without latency stay out of the baseline", []historyRun{failed, run(100, 10*time.Millisecond), run(102, 11*time.Millisecond), failed, run(100, 50*time.Millisecond)}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			points := buildTrend(tt.runs, 2, 5)
+			if got := points[len(points)-1].Unusual; !slices.Equal(got, tt.want) && len(got)+len(tt.want) > 0 {
+				t.Errorf("Unusual = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Sem nenhuma resposta o histórico não guarda as durações das falhas.
+func TestNewHistoryRunWithoutLatency(t *testing.T) {
+	latencies := loadtest.NewHistogram()
+	latencies.Add(2 * time.Second)
+	result := loadtest.Report{
+		TotalRequests: 1, Errors: 1, Latencies: latencies,
+		MinDuration: 2 * time.Second, MaxDuration: 2 * time.Second,
+		ErrorKinds: map[loadtest.ErrorKind]int{loadtest.ErrorTimeout: 1},
+	}
+	run := newHistoryRun("api", "http://localhost", nil, result, nil)
+	if run.hasLatency() || run.Min != 0 || run.P95 != 0 {
+		t.Errorf("run without responses stored latencies: min %v, P95 %v", run.Min, run.P95)
+	}
+
+	result.StatusCodes = map[int]int{200: 1}
+	if run := newHistoryRun("api", "http://localhost", nil, result, nil); !run.hasLatency() || run.Max != 2*time.Second {
+		t.Errorf("run with a response stored max %v, want 2s", run.Max)
+	}
+}
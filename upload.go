@@ -0,0 +1,300 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/export"
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+)
+
+// reportUpload envia os artefatos do relatório para um bucket S3 ou GCS.
+// O destino é s3://bucket/chave ou gs://bucket/chave, e a chave aceita os
+// placeholders {{date}}, {{time}}, {{name}} e {{sha}}.
+type reportUpload struct {
+	scheme string
+	bucket string
+	key    *template.Template
+	store  *objectStore
+}
+
+func newReportUpload(dest string) (*reportUpload, error) {
+	u, err := url.Parse(dest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -upload destination: %w", err)
+	}
+	if u.Scheme != "s3" && u.Scheme != "gs" {
+		return nil, fmt.Errorf("invalid -upload destination %q (use s3://bucket/key or gs://bucket/key)", dest)
+	}
+	key := strings.TrimPrefix(u.Path, "/")
+	if u.Host == "" || key == "" {
+		return nil, fmt.Errorf("-upload needs a bucket and a key, e.g. %s://bucket/reports/{{date}}/{{name}}", u.Scheme)
+	}
+
+	tmpl, err := template.New("upload").Funcs(template.FuncMap{
+		"date": func() string { return "" },
+		"time": func() string { return "" },
+		"name": func() string { return "" },
+		"sha":  func() string { return "" },
+	}).Parse(key)
+	if err != nil {
+		return nil, fmt.Errorf("parsing -upload key: %w", err)
+	}
+	// Credenciais são conferidas antes do teste, não depois dele
+	store, err := newObjectStore(u.Scheme)
+	if err != nil {
+		return nil, err
+	}
+	return &reportUpload{scheme: u.Scheme, bucket: u.Host, key: tmpl, store: store}, nil
+}
+
+func (u *reportUpload) renderKey(name, sha string, now time.Time) (string, error) {
+	var sb strings.Builder
+	err := u.key.Funcs(template.FuncMap{
+		"date": func() string { return now.Format("2006-01-02") },
+		"time": func() string { return now.Format("150405") },
+		"name": func() string { return name },
+		"sha":  func() string { return sha },
+	}).Execute(&sb, nil)
+	return sb.String(), err
+}
+
+// gitSHA abrevia o commit capturado nos metadados do relatório.
+func gitSHA(metadata loadtest.Metadata) string {
+	sha := metadata.GitCommit
+	if sha == "" {
+		return "unknown"
+	}
+	if len(sha) > 12 {
+		return sha[:12]
+	}
+	return sha
+}
+
+// upload grava <chave>.json e <chave>.csv e devolve os destinos gravados.
+func (u *reportUpload) upload(name string, result loadtest.Report) ([]string, error) {
+	key, err := u.renderKey(name, gitSHA(result.Metadata), time.Now().UTC())
+	if err != nil {
+		return nil, fmt.Errorf("rendering -upload key: %w", err)
+	}
+
+	artifacts := []struct {
+		ext, contentType, data string
+	}{
+		{".json", "application/json", export.JSONExporter{}.Export(result)},
+		{".csv", "text/csv", export.CSVExporter{}.Export(result)},
+	}
+	var uploaded []string
+	for _, artifact := range artifacts {
+		if err := u.store.put(u.bucket, key+artifact.ext, artifact.contentType, []byte(artifact.data)); err != nil {
+			return uploaded, err
+		}
+		uploaded = append(uploaded, fmt.Sprintf("%s://%s/%s%s", u.scheme, u.bucket, key, artifact.ext))
+	}
+	return uploaded, nil
+}
+
+// objectStore fala a API XML compatível com S3, assinando com SigV4. O GCS
+// aceita a mesma assinatura com chaves HMAC, ou um token OAuth no lugar dela.
+type objectStore struct {
+	endpoint    *url.URL
+	pathStyle   bool
+	region      string
+	accessKey   string
+	secretKey   string
+	token       string // x-amz-security-token de credenciais temporárias
+	bearerToken string
+	client      *http.Client
+}
+
+func newObjectStore(scheme string) (*objectStore, error) {
+	store := &objectStore{client: &http.Client{Timeout: 2 * time.Minute}}
+	endpoint := ""
+	if scheme == "gs" {
+		endpoint = "https://storage.googleapis.com"
+		store.pathStyle = true
+		store.region = "auto"
+		store.accessKey = os.Getenv("GCS_HMAC_ACCESS_KEY_ID")
+		store.secretKey = os.Getenv("GCS_HMAC_SECRET")
+		store.bearerToken = os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+		if store.bearerToken == "" && (store.accessKey == "" || store.secretKey == "") {
+			return nil, fmt.Errorf("gs:// uploads need GCS_HMAC_ACCESS_KEY_ID and GCS_HMAC_SECRET, or GOOGLE_OAUTH_ACCESS_TOKEN")
+		}
+	} else {
+		store.region = firstEnv("AWS_REGION", "AWS_DEFAULT_REGION")
+		if store.region == "" {
+			store.region = "us-east-1"
+		}
+		store.accessKey = os.Getenv("AWS_ACCESS_KEY_ID")
+		store.secretKey = os.Getenv("AWS_SECRET_ACCESS_KEY")
+		store.token = os.Getenv("AWS_SESSION_TOKEN")
+		if store.accessKey == "" || store.secretKey == "" {
+			return nil, fmt.Errorf("s3:// uploads need AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+		}
+		// Endpoints próprios (MinIO, R2, LocalStack) usam path-style
+		endpoint = firstEnv("AWS_ENDPOINT_URL_S3", "AWS_ENDPOINT_URL")
+		store.pathStyle = endpoint != ""
+		if endpoint == "" {
+			endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", store.region)
+		}
+	}
+
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage endpoint %q: %w", endpoint, err)
+	}
+	store.endpoint = u
+	return store, nil
+}
+
+func firstEnv(names ...string) string {
+	for _, name := range names {
+		if value := os.Getenv(name); value != "" {
+			return value
+		}
+	}
+	return ""
+}
+
+func (s *objectStore) put(bucket, key, contentType string, data []byte) error {
+	target := *s.endpoint
+	if s.pathStyle {
+		target.Path = "/" + bucket + "/" + key
+	} else {
+		target.Host = bucket + "." + target.Host
+		target.Path = "/" + key
+	}
+	target.RawPath = awsURIEncode(target.Path, false)
+
+	req, err := http.NewRequest(http.MethodPut, target.String(), bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.bearerToken != "" {
+		req.Header.Set("Authorization", "Bearer "+s.bearerToken)
+	} else {
+		signSigV4(req, data, s.accessKey, s.secretKey, s.token, s.region, "s3", time.Now().UTC())
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("uploading %s: %w", key, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		var body bytes.Buffer
+		body.ReadFrom(resp.Body)
+		return fmt.Errorf("uploading %s: %s: %s", key, resp.Status, strings.TrimSpace(body.String()))
+	}
+	return nil
+}
+
+// signSigV4 assina a requisição com AWS Signature Version 4, cobrindo o
+// host e todos os headers x-amz-*.
+func signSigV4(req *http.Request, payload []byte, accessKey, secretKey, token, region, service string, now time.Time) {
+	amzDate := now.Format("20060102T150405Z")
+	date := now.Format("20060102")
+	payloadHash := sha256Hex(payload)
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+	if token != "" {
+		req.Header.Set("X-Amz-Security-Token", token)
+	}
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		lower := strings.ToLower(name)
+		if strings.HasPrefix(lower, "x-amz-") {
+			headers[lower] = strings.TrimSpace(strings.Join(values, ","))
+		}
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		awsURIEncode(req.URL.Path, false),
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := "AWS4-HMAC-SHA256\n" + amzDate + "\n" + scope + "\n" + sha256Hex([]byte(canonicalRequest))
+
+	key := hmacSHA256([]byte("AWS4"+secretKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var parts []string
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, value := range vals {
+			parts = append(parts, awsURIEncode(key, true)+"="+awsURIEncode(value, true))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsURIEncode codifica tudo exceto os caracteres não reservados da RFC 3986;
+// a barra só é codificada em parâmetros da query.
+func awsURIEncode(s string, encodeSlash bool) string {
+	var sb strings.Builder
+	for _, b := range []byte(s) {
+		switch {
+		case b >= 'A' && b <= 'Z', b >= 'a' && b <= 'z', b >= '0' && b <= '9',
+			b == '-', b == '_', b == '.', b == '~':
+			sb.WriteByte(b)
+		case b == '/' && !encodeSlash:
+			sb.WriteByte(b)
+		default:
+			fmt.Fprintf(&sb, "%%%02X", b)
+		}
+	}
+	return sb.String()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"maps"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// O -watch relê um arquivo JSON durante testes longos e aplica só o que é
+// seguro mudar com o teste em andamento: concorrência, headers e limites.
+
+const watchInterval = 2 * time.Second
+
+// watchFile é o conteúdo do arquivo do -watch. Campos ausentes voltam ao
+// valor das flags.
+type watchFile struct {
+	Concurrency  *int              `json:"concurrency"`
+	Headers      map[string]string `json:"headers"`
+	MaxP95       string            `json:"max_p95"`
+	MaxErrorRate *float64          `json:"max_error_rate"`
+	MinRPS       *float64          `json:"min_rps"`
+}
+
+func readWatchFile(path string) (watchFile, time.Time, error) {
+	var file watchFile
+	info, err := os.Stat(path)
+	if err != nil {
+		return file, time.Time{}, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return file, time.Time{}, err
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return file, time.Time{}, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	if file.Concurrency != nil && *file.Concurrency < 1 {
+		return file, time.Time{}, fmt.Errorf("%s: concurrency must be at least 1", path)
+	}
+	if file.MaxP95 != "" {
+		if _, err := time.ParseDuration(file.MaxP95); err != nil {
+			return file, time.Time{}, fmt.Errorf("%s: invalid max_p95: %w", path, err)
+		}
+	}
+	return file, info.ModTime(), nil
+}
+
+// thresholds aplica os limites do arquivo sobre os das flags.
+func (f watchFile) thresholds(flags report.Thresholds) report.Thresholds {
+	t := flags
+	if f.MaxP95 != "" {
+		t.MaxP95, _ = time.ParseDuration(f.MaxP95)
+	}
+	if f.MaxErrorRate != nil {
+		t.MaxErrorRate = *f.MaxErrorRate
+	}
+	if f.MinRPS != nil {
+		t.MinRPS = *f.MinRPS
+	}
+	return t
+}
+
+type configWatcher struct {
+	path        string
+	control     *loadtest.ConcurrencyControl
+	headers     *loadtest.LiveHeaders
+	flags       report.Thresholds
+	flagConc    int // -concurrency, usado quando o arquivo não define concurrency
+	concurrency int
+	applied     watchFile
+	modTime     time.Time
+	start       time.Time
+	stop        chan struct{}
+	done        chan struct{}
+	thresholds  report.Thresholds
+	timeline    []loadtest.TimelineEvent
+}
+
+// newConfigWatcher lê o arquivo uma vez e aplica o conteúdo a config e aos
+// limites antes do teste, como se fossem flags.
+func newConfigWatcher(path string, config *loadtest.Config, flags report.Thresholds) (*configWatcher, error) {
+	file, modTime, err := readWatchFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if config.Control == nil {
+		config.Control = loadtest.NewConcurrencyControl()
+	}
+	config.LiveHeaders = &loadtest.LiveHeaders{}
+	if err := config.LiveHeaders.Set(file.Headers); err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	flagConc := config.Concurrency
+	if file.Concurrency != nil {
+		config.Concurrency = *file.Concurrency
+	}
+	return &configWatcher{
+		path:        path,
+		flagConc:    flagConc,
+		control:     config.Control,
+		headers:     config.LiveHeaders,
+		flags:       flags,
+		concurrency: config.Concurrency,
+		applied:     file,
+		modTime:     modTime,
+		thresholds:  file.thresholds(flags),
+	}, nil
+}
+
+// watch confere o arquivo a cada watchInterval até finish ser chamado.
+func (w *configWatcher) watch() {
+	w.start = time.Now()
+	w.stop = make(chan struct{})
+	w.done = make(chan struct{})
+	go func() {
+		defer close(w.done)
+		ticker := time.NewTicker(watchInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-ticker.C:
+			}
+			info, err := os.Stat(w.path)
+			if err != nil || info.ModTime().Equal(w.modTime) {
+				continue
+			}
+			w.modTime = info.ModTime()
+			file, _, err := readWatchFile(w.path)
+			if err != nil {
+				slog.Warn("ignoring the -watch file", "error", err)
+				continue
+			}
+			w.apply(file)
+		}
+	}()
+}
+
+// apply aplica o que mudou desde a última leitura e registra na linha do
+// tempo do relatório.
+func (w *configWatcher) apply(file watchFile) {
+	var changes []string
+	concurrency := w.flagConc
+	if file.Concurrency != nil {
+		concurrency = *file.Concurrency
+	}
+	if concurrency != w.concurrency {
+		if err := w.control.Set(concurrency); err != nil {
+			slog.Warn("ignoring the -watch concurrency", "error", err)
+		} else {
+			changes = append(changes, fmt.Sprintf("concurrency %d -> %d", w.concurrency, concurrency))
+			w.concurrency = concurrency
+		}
+	}
+	if !maps.Equal(file.Headers, w.applied.Headers) {
+		if err := w.headers.Set(file.Headers); err != nil {
+			slog.Warn("ignoring the -watch headers", "error", err)
+			file.Headers = w.applied.Headers
+		} else {
+			changes = append(changes, "headers "+headerNames(file.Headers))
+		}
+	}
+
+	old, next := w.thresholds, file.thresholds(w.flags)
+	if old.MaxP95 != next.MaxP95 {
+		changes = append(changes, fmt.Sprintf("max_p95 %v -> %v", old.MaxP95, next.MaxP95))
+	}
+	if old.MaxErrorRate != next.MaxErrorRate {
+		changes = append(changes, fmt.Sprintf("max_error_rate %.2f -> %.2f", old.MaxErrorRate, next.MaxErrorRate))
+	}
+	if old.MinRPS != next.MinRPS {
+		changes = append(changes, fmt.Sprintf("min_rps %.2f -> %.2f", old.MinRPS, next.MinRPS))
+	}
+	w.thresholds = next
+	at := time.Since(w.start)
+	for _, change := range changes {
+		w.timeline = append(w.timeline, loadtest.TimelineEvent{At: at, Change: change})
+	}
+
+	w.applied = file
+	if len(changes) > 0 {
+		slog.Info("config reloaded", "file", w.path, "changes", strings.Join(changes, "; "))
+	}
+}
+
+// finish para a observação e devolve os limites em vigor e as mudanças
+// aplicadas.
+func (w *configWatcher) finish() (report.Thresholds, []loadtest.TimelineEvent) {
+	close(w.stop)
+	<-w.done
+	return w.thresholds, w.timeline
+}
+
+// headerNames lista só os nomes, para não levar tokens ao relatório.
+func headerNames(headers map[string]string) string {
+	if len(headers) == 0 {
+		return "cleared"
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return "set: " + strings.Join(names, ", ")
+}
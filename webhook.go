@@ -0,0 +1,53 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"fullcycle-goexpert-desafio-stress-test/loadtest"
+	"fullcycle-goexpert-desafio-stress-test/report"
+)
+
+// webhookPayload é o corpo enviado ao -webhook ao final do teste.
+type webhookPayload struct {
+	Status  string           `json:"status"` // completed, stopped, aborted ou failed
+	Error   string           `json:"error,omitempty"`
+	Verdict *report.Verdict  `json:"verdict,omitempty"`
+	Report  *loadtest.Report `json:"report,omitempty"`
+}
+
+func newWebhookPayload(result *loadtest.Report, verdict *report.Verdict, runErr error) webhookPayload {
+	switch {
+	case runErr != nil:
+		return webhookPayload{Status: "failed", Error: runErr.Error()}
+	case result.Aborted:
+		return webhookPayload{Status: "aborted", Verdict: verdict, Report: result}
+	case result.Stopped:
+		return webhookPayload{Status: "stopped", Verdict: verdict, Report: result}
+	default:
+		return webhookPayload{Status: "completed", Verdict: verdict, Report: result}
+	}
+}
+
+// postJSON envia o payload para um webhook e falha em respostas fora de 2xx.
+func postJSON(url string, payload any) error {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	// Client próprio: o do teste pode ter TLS, proxy e limites de banda específicos
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return fmt.Errorf("sending webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}